@@ -25,6 +25,14 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/clicmd"
 )
 
+const (
+	// exitCodeError is the exit code when a command fails.
+	exitCodeError = 1
+	// exitCodeDiffFound is the exit code when `ks diff` finds differences or
+	// `ks drift` finds objects which have drifted.
+	exitCodeDiffFound = 10
+)
+
 // Version is overridden using `-X main.version` during release builds
 var version = "(dev build)"
 var apimachineryVersion = ""
@@ -36,13 +44,13 @@ func main() {
 	wd, err := os.Getwd()
 	if err != nil {
 		log.WithError(err).Error("unable to find working directory")
-		os.Exit(1)
+		os.Exit(exitCodeError)
 	}
 
 	rootCmd, err := clicmd.NewRoot(afero.NewOsFs(), wd, os.Args[1:])
 	if err != nil {
 		log.WithError(err).Error("unable create ksonnet command")
-		os.Exit(1)
+		os.Exit(exitCodeError)
 	}
 
 	if err := rootCmd.Execute(); err != nil {
@@ -56,11 +64,11 @@ func main() {
 		log.SetFormatter(logFmt)
 
 		switch err {
-		case actions.ErrDiffFound:
-			os.Exit(10)
+		case actions.ErrDiffFound, actions.ErrDriftFound:
+			os.Exit(exitCodeDiffFound)
 		default:
 			log.Error(err.Error())
-			os.Exit(1)
+			os.Exit(exitCodeError)
 		}
 	}
 }
@@ -47,7 +47,7 @@ var _ = Describe("ks delete", func() {
 
 	Context("deleting all items in a module", func() {
 		JustBeforeEach(func() {
-			o = a.runKs("delete", "default")
+			o = a.runKs("delete", "default", "--yes")
 			assertExitStatus(o, 0)
 		})
 
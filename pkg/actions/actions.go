@@ -41,8 +41,12 @@ const (
 	OptionComponentName = "component-name"
 	// OptionComponentNames is componentNames option.
 	OptionComponentNames = "component-names"
+	// OptionConcurrency is concurrency option.
+	OptionConcurrency = "concurrency"
 	// OptionCreate is create option.
 	OptionCreate = "create"
+	// OptionDiscovery is discovery option. Used to look up resources in a cluster.
+	OptionDiscovery = "discovery"
 	// OptionDryRun is dryRun option.
 	OptionDryRun = "dry-run"
 	// OptionEnvName is envName option.
@@ -51,12 +55,18 @@ const (
 	OptionEnvName1 = "env-name-1"
 	// OptionEnvName2 is envName1. Used for param diff.
 	OptionEnvName2 = "env-name-2"
+	// OptionEnvNames is envNames option. Used for the param diff matrix.
+	OptionEnvNames = "env-names"
 	// OptionExtVarFiles is jsonnet ext var files.
 	OptionExtVarFiles = "ext-vars-files"
 	// OptionExtVars is jsonnet ext vars.
 	OptionExtVars = "ext-vars"
+	// OptionFilename is a filename.
+	OptionFilename = "filename"
 	// OptionForce is force option.
 	OptionForce = "force"
+	// OptionExplain is explain option. Used for showing where params are defined.
+	OptionExplain = "explain"
 	// OptionFormat is format option.
 	OptionFormat = "format"
 	// OptionFs is fs option.
@@ -71,18 +81,28 @@ const (
 	OptionInstalled = "only-installed"
 	// OptionJPaths is jsonnet paths.
 	OptionJPaths = "jpaths"
+	// OptionKinds is kinds option. Used for selecting objects by kind.
+	OptionKinds = "kinds"
 	// OptionLibName is libName.
 	OptionLibName = "lib-name"
 	// OptionName is name option.
 	OptionName = "name"
+	// OptionMatrix is matrix option. Used for comparing params across environments.
+	OptionMatrix = "matrix"
 	// OptionModule is component module option.
 	OptionModule = "module"
+	// OptionNames is names option. Used for selecting objects by name.
+	OptionNames = "names"
 	// OptionNamespace is a cluster namespace option
 	OptionNamespace = "namespace"
 	// OptionNewEnvName is newEnvName option. Used for renaming environments.
 	OptionNewEnvName = "new-env-name"
+	// OptionOrphan is orphan option. Used to orphan the dependents of deleted objects.
+	OptionOrphan = "orphan"
 	// OptionOutput is output option.
 	OptionOutput = "output"
+	// OptionOutputDir is outputDir option. Used for writing objects to files.
+	OptionOutputDir = "output-dir"
 	// OptionOverride is override option.
 	OptionOverride = "override"
 	// OptionPackageName is packageName option.
@@ -94,14 +114,24 @@ const (
 	// OptionResolveImage is resolve image option. It is used to resolve docker image references
 	// when setting parameters.
 	OptionResolveImage = "resolve-image"
+	// OptionRevision is revision option. Used for rolling back environments.
+	OptionRevision = "revision"
 	// OptionRootPath is path option.
 	OptionRootPath = "root-path"
+	// OptionSchemaDir is schemaDir option. Used for loading additional schemas.
+	OptionSchemaDir = "schema-dir"
+	// OptionSecret is secret option. Used for setting encrypted parameters.
+	OptionSecret = "secret"
+	// OptionSelector is selector option. Used for selecting objects by label.
+	OptionSelector = "selector"
 	// OptionServer is server option.
 	OptionServer = "server"
 	// OptionServerURI is serverURI option.
 	OptionServerURI = "server-uri"
 	// OptionSkipDefaultRegistries is skipDefaultRegistries option. Used by init.
 	OptionSkipDefaultRegistries = "skip-default-registries"
+	// OptionSkipConfirm is skipConfirm option. Used to skip confirmation prompts.
+	OptionSkipConfirm = "skip-confirm"
 	// OptionSkipGc is skipGc option.
 	OptionSkipGc = "skip-gc"
 	// OptionSpecFlag is specFlag option. Used for setting k8s spec.
@@ -110,6 +140,8 @@ const (
 	OptionSrc1 = "src-1"
 	// OptionSrc2 is src2 option.
 	OptionSrc2 = "src-2"
+	// OptionTimeout is timeout option.
+	OptionTimeout = "timeout"
 	// OptionTlaVarFiles is jsonnet tla var files.
 	OptionTlaVarFiles = "tla-var-files"
 	// OptionTlaVars is jsonnet tla vars.
@@ -126,6 +158,10 @@ const (
 	OptionValue = "value"
 	// OptionVersion is version option.
 	OptionVersion = "version"
+	// OptionWait is wait option.
+	OptionWait = "wait"
+	// OptionWatch is watch option.
+	OptionWatch = "watch"
 )
 
 const (
@@ -273,6 +309,21 @@ func (o *optionLoader) LoadOptionalInt(name string) int {
 	return a
 }
 
+func (o *optionLoader) LoadDuration(name string) time.Duration {
+	i := o.load(name)
+	if i == nil {
+		return 0
+	}
+
+	a, ok := i.(time.Duration)
+	if !ok {
+		o.err = newInvalidOptionError(name)
+		return 0
+	}
+
+	return a
+}
+
 func (o *optionLoader) LoadString(name string) string {
 	i := o.load(name)
 	if i == nil {
@@ -16,12 +16,18 @@
 package actions
 
 import (
+	"io"
+	"os"
+	"time"
+
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
 	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
 )
 
-type runApplyFn func(cluster.ApplyConfig, ...cluster.ApplyOpts) error
+type runApplyFn func(cluster.ApplyConfig, ...cluster.ApplyOpts) (*cluster.ApplyResult, error)
 
 // RunApply runs `apply`.
 func RunApply(m map[string]interface{}) error {
@@ -40,13 +46,18 @@ type Apply struct {
 	app            app.App
 	clientConfig   *client.Config
 	componentNames []string
+	concurrency    int
 	create         bool
 	dryRun         bool
 	envName        string
 	gcTag          string
+	outputType     string
 	skipGc         bool
+	wait           bool
+	waitTimeout    time.Duration
 
 	runApplyFn runApplyFn
+	out        io.Writer
 }
 
 // RunApply runs `apply`
@@ -57,12 +68,17 @@ func newApply(m map[string]interface{}, opts ...applyOpt) (*Apply, error) {
 		app:            ol.LoadApp(),
 		clientConfig:   ol.LoadClientConfig(),
 		componentNames: ol.LoadStringSlice(OptionComponentNames),
+		concurrency:    ol.LoadOptionalInt(OptionConcurrency),
 		create:         ol.LoadBool(OptionCreate),
 		dryRun:         ol.LoadBool(OptionDryRun),
 		gcTag:          ol.LoadString(OptionGcTag),
+		outputType:     ol.LoadOptionalString(OptionOutput),
 		skipGc:         ol.LoadBool(OptionSkipGc),
+		wait:           ol.LoadBool(OptionWait),
+		waitTimeout:    ol.LoadDuration(OptionTimeout),
 
 		runApplyFn: cluster.RunApply,
+		out:        os.Stdout,
 	}
 
 	if ol.err != nil {
@@ -81,18 +97,59 @@ func newApply(m map[string]interface{}, opts ...applyOpt) (*Apply, error) {
 }
 
 func (a *Apply) run() error {
-	config := cluster.ApplyConfig{
-		App:            a.app,
-		ClientConfig:   a.clientConfig,
-		ComponentNames: a.componentNames,
-		Create:         a.create,
-		DryRun:         a.dryRun,
-		EnvName:        a.envName,
-		GcTag:          a.gcTag,
-		SkipGc:         a.skipGc,
+	f, err := table.DetectFormat(a.outputType)
+	if err != nil {
+		return errors.Wrap(err, "detecting output format")
+	}
+
+	return runDestinations(a.app, a.envName, a.clientConfig, a.out, f, func(envName string, clientConfig *client.Config) error {
+		config := cluster.ApplyConfig{
+			App:            a.app,
+			ClientConfig:   clientConfig,
+			ComponentNames: a.componentNames,
+			Concurrency:    a.concurrency,
+			Create:         a.create,
+			DryRun:         a.dryRun,
+			EnvName:        envName,
+			GcTag:          a.gcTag,
+			SkipGc:         a.skipGc,
+			Wait:           a.wait,
+			WaitTimeout:    a.waitTimeout,
+		}
+
+		result, err := a.runApplyFn(config)
+		if err != nil {
+			return err
+		}
+
+		// Dry runs print a preview of the changes instead.
+		if a.dryRun {
+			return nil
+		}
+
+		return printApplyResult(a.out, result, f)
+	})
+}
+
+// printApplyResult prints the result of applying each object as a table.
+func printApplyResult(w io.Writer, result *cluster.ApplyResult, f table.Format) error {
+	t := table.New("apply", w)
+	t.SetFormat(f)
+	t.SetHeader([]string{"apiversion", "kind", "namespace", "name", "action", "uid", "duration"})
+
+	for _, r := range result.Objects {
+		t.Append([]string{
+			r.GroupVersionKind.GroupVersion().String(),
+			r.GroupVersionKind.Kind,
+			r.Namespace,
+			r.Name,
+			string(r.Action),
+			r.UID,
+			r.Duration.Round(time.Millisecond).String(),
+		})
 	}
 
-	return a.runApplyFn(config)
+	return t.Render()
 }
 
 func (a *Apply) setCurrentEnv(name string) {
@@ -16,13 +16,17 @@
 package actions
 
 import (
+	"bytes"
 	"testing"
+	"time"
 
+	"github.com/ksonnet/ksonnet/pkg/app"
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/client"
 	"github.com/ksonnet/ksonnet/pkg/cluster"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestApply(t *testing.T) {
@@ -50,33 +54,40 @@ func TestApply(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			withApp(t, func(appMock *amocks.App) {
 				appMock.On("CurrentEnvironment").Return(tc.currentName)
+				appMock.On("Environment", "default").Return(&app.EnvironmentConfig{Name: "default"}, nil)
 
 				in := map[string]interface{}{
 					OptionApp:            appMock,
 					OptionClientConfig:   &client.Config{},
 					OptionComponentNames: []string{},
+					OptionConcurrency:    4,
 					OptionCreate:         true,
 					OptionDryRun:         true,
 					OptionEnvName:        tc.envName,
 					OptionGcTag:          "gc-tag",
 					OptionSkipGc:         true,
+					OptionWait:           true,
+					OptionTimeout:        time.Minute,
 				}
 
 				expected := cluster.ApplyConfig{
 					App:            appMock,
 					ClientConfig:   &client.Config{},
 					ComponentNames: []string{},
+					Concurrency:    4,
 					Create:         true,
 					DryRun:         true,
 					EnvName:        "default",
 					GcTag:          "gc-tag",
 					SkipGc:         true,
+					Wait:           true,
+					WaitTimeout:    time.Minute,
 				}
 
 				runApplyOpt := func(a *Apply) {
-					a.runApplyFn = func(config cluster.ApplyConfig, opts ...cluster.ApplyOpts) error {
+					a.runApplyFn = func(config cluster.ApplyConfig, opts ...cluster.ApplyOpts) (*cluster.ApplyResult, error) {
 						assert.Equal(t, expected, config)
-						return nil
+						return &cluster.ApplyResult{}, nil
 					}
 				}
 
@@ -94,6 +105,121 @@ func TestApply(t *testing.T) {
 	}
 }
 
+func TestApply_output(t *testing.T) {
+	cases := []struct {
+		name       string
+		outputType string
+		expected   string
+		isErr      bool
+	}{
+		{
+			name: "table",
+			expected: `APIVERSION KIND       NAMESPACE NAME    ACTION     UID   DURATION
+========== ====       ========= ====    ======     ===   ========
+apps/v1    Deployment default   guiroot configured 12345 1.5s
+v1         ConfigMap  default   old     pruned     67890 10ms
+`,
+		},
+		{
+			name:       "json",
+			outputType: "json",
+			expected: `{
+	"kind": "apply",
+	"data": [
+		{
+			"action": "configured",
+			"apiversion": "apps/v1",
+			"duration": "1.5s",
+			"kind": "Deployment",
+			"name": "guiroot",
+			"namespace": "default",
+			"uid": "12345"
+		},
+		{
+			"action": "pruned",
+			"apiversion": "v1",
+			"duration": "10ms",
+			"kind": "ConfigMap",
+			"name": "old",
+			"namespace": "default",
+			"uid": "67890"
+		}
+	]
+}
+`,
+		},
+		{
+			name:       "invalid output",
+			outputType: "yaml",
+			isErr:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				appMock.On("Environment", "default").Return(&app.EnvironmentConfig{Name: "default"}, nil)
+
+				in := map[string]interface{}{
+					OptionApp:            appMock,
+					OptionClientConfig:   &client.Config{},
+					OptionComponentNames: []string{},
+					OptionCreate:         true,
+					OptionDryRun:         false,
+					OptionEnvName:        "default",
+					OptionGcTag:          "gc-tag",
+					OptionOutput:         tc.outputType,
+					OptionSkipGc:         false,
+					OptionWait:           false,
+					OptionTimeout:        time.Duration(0),
+				}
+
+				result := &cluster.ApplyResult{
+					Objects: []cluster.ObjectResult{
+						{
+							GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+							Namespace:        "default",
+							Name:             "guiroot",
+							Action:           cluster.ApplyActionConfigured,
+							UID:              "12345",
+							Duration:         1500 * time.Millisecond,
+						},
+						{
+							GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+							Namespace:        "default",
+							Name:             "old",
+							Action:           cluster.ApplyActionPruned,
+							UID:              "67890",
+							Duration:         10 * time.Millisecond,
+						},
+					},
+				}
+
+				var buf bytes.Buffer
+
+				runApplyOpt := func(a *Apply) {
+					a.out = &buf
+					a.runApplyFn = func(config cluster.ApplyConfig, opts ...cluster.ApplyOpts) (*cluster.ApplyResult, error) {
+						return result, nil
+					}
+				}
+
+				a, err := newApply(in, runApplyOpt)
+				require.NoError(t, err)
+
+				err = a.run()
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected, buf.String())
+			})
+		})
+	}
+}
+
 func TestApply_invalid_input(t *testing.T) {
 	withApp(t, func(appMock *amocks.App) {
 		in := map[string]interface{}{
@@ -16,9 +16,14 @@
 package actions
 
 import (
+	"io"
+	"os"
+	"time"
+
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
 	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
 )
 
 type runDeleteFn func(cluster.DeleteConfig, ...cluster.DeleteOpts) error
@@ -40,10 +45,19 @@ type Delete struct {
 	app            app.App
 	clientConfig   *client.Config
 	componentNames []string
+	dryRun         bool
 	envName        string
 	gracePeriod    int64
+	kinds          []string
+	names          []string
+	orphan         bool
+	selector       string
+	skipConfirm    bool
+	wait           bool
+	waitTimeout    time.Duration
 
 	runDeleteFn runDeleteFn
+	out         io.Writer
 }
 
 // RunDelete runs `apply`
@@ -54,9 +68,18 @@ func newDelete(m map[string]interface{}, opts ...deleteOpt) (*Delete, error) {
 		app:            ol.LoadApp(),
 		clientConfig:   ol.LoadClientConfig(),
 		componentNames: ol.LoadStringSlice(OptionComponentNames),
+		dryRun:         ol.LoadBool(OptionDryRun),
 		gracePeriod:    ol.LoadInt64(OptionGracePeriod),
+		kinds:          ol.LoadStringSlice(OptionKinds),
+		names:          ol.LoadStringSlice(OptionNames),
+		orphan:         ol.LoadBool(OptionOrphan),
+		selector:       ol.LoadString(OptionSelector),
+		skipConfirm:    ol.LoadBool(OptionSkipConfirm),
+		wait:           ol.LoadBool(OptionWait),
+		waitTimeout:    ol.LoadDuration(OptionTimeout),
 
 		runDeleteFn: cluster.RunDelete,
+		out:         os.Stdout,
 	}
 
 	if ol.err != nil {
@@ -75,15 +98,25 @@ func newDelete(m map[string]interface{}, opts ...deleteOpt) (*Delete, error) {
 }
 
 func (d *Delete) run() error {
-	config := cluster.DeleteConfig{
-		App:            d.app,
-		ClientConfig:   d.clientConfig,
-		ComponentNames: d.componentNames,
-		EnvName:        d.envName,
-		GracePeriod:    d.gracePeriod,
-	}
-
-	return d.runDeleteFn(config)
+	return runDestinations(d.app, d.envName, d.clientConfig, d.out, table.DefaultFormat, func(envName string, clientConfig *client.Config) error {
+		config := cluster.DeleteConfig{
+			App:            d.app,
+			ClientConfig:   clientConfig,
+			ComponentNames: d.componentNames,
+			DryRun:         d.dryRun,
+			EnvName:        envName,
+			GracePeriod:    d.gracePeriod,
+			Kinds:          d.kinds,
+			Names:          d.names,
+			Orphan:         d.orphan,
+			Selector:       d.selector,
+			SkipConfirm:    d.skipConfirm,
+			Wait:           d.wait,
+			WaitTimeout:    d.waitTimeout,
+		}
+
+		return d.runDeleteFn(config)
+	})
 }
 
 func (d *Delete) setCurrentEnv(name string) {
@@ -17,7 +17,9 @@ package actions
 
 import (
 	"testing"
+	"time"
 
+	"github.com/ksonnet/ksonnet/pkg/app"
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/client"
 	"github.com/ksonnet/ksonnet/pkg/cluster"
@@ -50,21 +52,38 @@ func TestDelete(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			withApp(t, func(appMock *amocks.App) {
 				appMock.On("CurrentEnvironment").Return(tc.currentName)
+				appMock.On("Environment", "default").Return(&app.EnvironmentConfig{Name: "default"}, nil)
 
 				in := map[string]interface{}{
 					OptionApp:            appMock,
 					OptionClientConfig:   &client.Config{},
 					OptionComponentNames: []string{},
+					OptionDryRun:         true,
 					OptionEnvName:        tc.envName,
 					OptionGracePeriod:    int64(3),
+					OptionKinds:          []string{"Deployment"},
+					OptionNames:          []string{"guestbook"},
+					OptionOrphan:         true,
+					OptionSelector:       "app=guestbook",
+					OptionSkipConfirm:    true,
+					OptionTimeout:        time.Minute,
+					OptionWait:           true,
 				}
 
 				expected := cluster.DeleteConfig{
 					App:            appMock,
 					ClientConfig:   &client.Config{},
 					ComponentNames: []string{},
+					DryRun:         true,
 					EnvName:        "default",
 					GracePeriod:    3,
+					Kinds:          []string{"Deployment"},
+					Names:          []string{"guestbook"},
+					Orphan:         true,
+					Selector:       "app=guestbook",
+					SkipConfirm:    true,
+					Wait:           true,
+					WaitTimeout:    time.Minute,
 				}
 
 				runDeleteOpt := func(a *Delete) {
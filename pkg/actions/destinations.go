@@ -0,0 +1,93 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"io"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// destinationFn runs an action against one destination of an environment.
+// envName selects the destination.
+type destinationFn func(envName string, clientConfig *client.Config) error
+
+// runDestinations runs fn against every destination of an environment. An
+// environment without a list of destinations, or a name which selects one
+// destination, runs fn once. Otherwise fn runs for each destination with its
+// own copy of the client config, even if an earlier destination failed, and
+// the result for each destination is written to w as a table. Other formats
+// leave w to the documents written by fn, so the results are logged instead.
+func runDestinations(a app.App, envName string, clientConfig *client.Config, w io.Writer, f table.Format, fn destinationFn) error {
+	env, err := app.ResolveEnvironment(a, envName)
+	if err != nil {
+		return err
+	}
+
+	if len(env.Destinations) == 0 {
+		return fn(envName, clientConfig)
+	}
+
+	t := table.New("destinations", w)
+	t.SetFormat(f)
+	t.SetHeader([]string{"destination", "server", "namespace", "result"})
+
+	var failed int
+	var found error
+
+	for _, d := range env.Destinations {
+		log.Infof("Running against destination %q of environment %q (server %s, namespace %s)",
+			d.Name, envName, d.Server, d.Namespace)
+
+		result := "ok"
+
+		err := fn(app.DestinationEnvName(envName, d.Name), clientConfig.Copy())
+		switch err {
+		case nil:
+		case ErrDiffFound, ErrDriftFound:
+			// Differences are reported, but do not fail the destination.
+			found = err
+			result = err.Error()
+		default:
+			log.Errorf("Destination %q failed: %v", d.Name, err)
+			failed++
+			result = err.Error()
+		}
+
+		if f != table.FormatTable {
+			log.Infof("Destination %q (server %s, namespace %s): %s", d.Name, d.Server, d.Namespace, result)
+			continue
+		}
+
+		t.Append([]string{d.Name, d.Server, d.Namespace, result})
+	}
+
+	if f == table.FormatTable {
+		if err = t.Render(); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%d of %d destinations of environment %q failed", failed, len(env.Destinations), envName)
+	}
+
+	return found
+}
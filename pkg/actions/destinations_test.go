@@ -0,0 +1,141 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_runDestinations(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		env := &app.EnvironmentConfig{
+			Name: "prod",
+			Destinations: []*app.EnvironmentDestinationSpec{
+				{Name: "us-east", Server: "http://east.example.com", Namespace: "prod"},
+				{Name: "us-west", Server: "http://west.example.com", Namespace: "prod"},
+				{Name: "eu-west", Server: "http://eu.example.com", Namespace: "prod"},
+			},
+		}
+		appMock.On("Environment", "prod").Return(env, nil)
+
+		results := map[string]error{
+			"prod@us-east": nil,
+			"prod@us-west": ErrDiffFound,
+			"prod@eu-west": errors.New("connection refused"),
+		}
+
+		clientConfig := &client.Config{}
+
+		var envNames []string
+		fn := func(envName string, c *client.Config) error {
+			assert.False(t, c == clientConfig, "expected a copy of the client config")
+			envNames = append(envNames, envName)
+			return results[envName]
+		}
+
+		var buf bytes.Buffer
+		err := runDestinations(appMock, "prod", clientConfig, &buf, table.DefaultFormat, fn)
+		require.Error(t, err)
+		assert.Equal(t, `1 of 3 destinations of environment "prod" failed`, err.Error())
+
+		assert.Equal(t, []string{"prod@us-east", "prod@us-west", "prod@eu-west"}, envNames)
+		assertOutput(t, "destinations/output.txt", buf.String())
+	})
+}
+
+func Test_runDestinations_found(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		env := &app.EnvironmentConfig{
+			Name: "prod",
+			Destinations: []*app.EnvironmentDestinationSpec{
+				{Name: "us-east"},
+				{Name: "us-west"},
+			},
+		}
+		appMock.On("Environment", "prod").Return(env, nil)
+
+		fn := func(envName string, c *client.Config) error {
+			if envName == "prod@us-west" {
+				return ErrDiffFound
+			}
+			return nil
+		}
+
+		var buf bytes.Buffer
+		err := runDestinations(appMock, "prod", &client.Config{}, &buf, table.DefaultFormat, fn)
+		require.Equal(t, ErrDiffFound, err)
+	})
+}
+
+func Test_runDestinations_json(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		env := &app.EnvironmentConfig{
+			Name: "prod",
+			Destinations: []*app.EnvironmentDestinationSpec{
+				{Name: "us-east"},
+				{Name: "us-west"},
+			},
+		}
+		appMock.On("Environment", "prod").Return(env, nil)
+
+		var buf bytes.Buffer
+		fn := func(envName string, c *client.Config) error {
+			buf.WriteString(`{"kind": "apply"}` + "\n")
+			return nil
+		}
+
+		err := runDestinations(appMock, "prod", &client.Config{}, &buf, table.FormatJSON, fn)
+		require.NoError(t, err)
+
+		assert.Equal(t, `{"kind": "apply"}`+"\n"+`{"kind": "apply"}`+"\n", buf.String())
+	})
+}
+
+func Test_runDestinations_single(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		env := &app.EnvironmentConfig{
+			Name:        "default",
+			Destination: &app.EnvironmentDestinationSpec{Name: "default"},
+		}
+		appMock.On("Environment", "default").Return(env, nil)
+
+		clientConfig := &client.Config{}
+
+		var calls int
+		fn := func(envName string, c *client.Config) error {
+			calls++
+			assert.Equal(t, "default", envName)
+			assert.True(t, c == clientConfig)
+			return nil
+		}
+
+		var buf bytes.Buffer
+		err := runDestinations(appMock, "default", clientConfig, &buf, table.DefaultFormat, fn)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, buf.String())
+	})
+}
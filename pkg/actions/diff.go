@@ -27,6 +27,7 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
 	"github.com/ksonnet/ksonnet/pkg/diff"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
 	"github.com/pkg/errors"
 )
 
@@ -55,8 +56,10 @@ type Diff struct {
 	src1         string
 	src2         string
 	components   []string
+	output       string
 
-	diffFn func(app.App, *client.Config, []string, *diff.Location, *diff.Location) (io.Reader, error)
+	diffFn           func(app.App, *client.Config, []string, *diff.Location, *diff.Location) (io.Reader, error)
+	structuralDiffFn func(app.App, *client.Config, []string, *diff.Location, *diff.Location) (*diff.Result, error)
 
 	out io.Writer
 }
@@ -71,8 +74,10 @@ func NewDiff(m map[string]interface{}) (*Diff, error) {
 		src1:         ol.LoadString(OptionSrc1),
 		src2:         ol.LoadOptionalString(OptionSrc2),
 		components:   ol.LoadStringSlice(OptionComponentNames),
+		output:       ol.LoadOptionalString(OptionOutput),
 
-		diffFn: diff.DefaultDiff,
+		diffFn:           diff.DefaultDiff,
+		structuralDiffFn: diff.DefaultStructuralDiff,
 
 		out: os.Stdout,
 	}
@@ -93,34 +98,59 @@ func (d *Diff) Run() error {
 	}
 	location2 := diff.NewLocation(d.src2)
 
-	r, err := d.diffFn(d.app, d.clientConfig, d.components, location1, location2)
-	if err != nil {
+	if err := location1.Err(); err != nil {
 		return err
 	}
 
-	var buf bytes.Buffer
+	f := table.DefaultFormat
+	if d.output == diff.OutputJSON {
+		f = table.FormatJSON
+	}
 
-	scanner := bufio.NewScanner(r)
-	for scanner.Scan() {
-		t := scanner.Text()
+	return runDestinations(d.app, location1.EnvName(), d.clientConfig, d.out, f, func(envName string, clientConfig *client.Config) error {
+		return d.runLocations(clientConfig, location1.WithEnvName(envName), d.pairLocation(location2, envName))
+	})
+}
 
-		switch {
-		case strings.HasPrefix(t, "+"):
-			_, err = diffAddColor.Fprintln(&buf, t)
-			if err != nil {
-				return err
-			}
-		case strings.HasPrefix(t, "-"):
-			_, err = diffRemoveColor.Fprintln(&buf, t)
-			if err != nil {
-				return err
-			}
-		default:
-			fmt.Fprintln(&buf, t)
+// pairLocation qualifies location with the destination selected by envName
+// if the location's environment declares a destination with the same name.
+func (d *Diff) pairLocation(location *diff.Location, envName string) *diff.Location {
+	_, destName := app.SplitDestination(envName)
+	if destName == "" || location.Err() != nil {
+		return location
+	}
+
+	baseName, selected := app.SplitDestination(location.EnvName())
+	if selected != "" {
+		return location
+	}
+
+	env, err := d.app.Environment(baseName)
+	if err != nil {
+		return location
+	}
+
+	for _, dest := range env.Destinations {
+		if dest.Name == destName {
+			return location.WithEnvName(app.DestinationEnvName(baseName, destName))
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
+	return location
+}
+
+func (d *Diff) runLocations(clientConfig *client.Config, location1, location2 *diff.Location) error {
+	if d.output != "" {
+		return d.runStructural(clientConfig, location1, location2)
+	}
+
+	r, err := d.diffFn(d.app, clientConfig, d.components, location1, location2)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err = colorizeDiff(&buf, r, false); err != nil {
 		return err
 	}
 
@@ -131,3 +161,63 @@ func (d *Diff) Run() error {
 
 	return nil
 }
+
+// runStructural compares the locations object by object.
+func (d *Diff) runStructural(clientConfig *client.Config, location1, location2 *diff.Location) error {
+	result, err := d.structuralDiffFn(d.app, clientConfig, d.components, location1, location2)
+	if err != nil {
+		return err
+	}
+
+	var rendered bytes.Buffer
+	if err = result.Render(&rendered, d.output); err != nil {
+		return err
+	}
+
+	switch d.output {
+	case diff.OutputText:
+		if err = colorizeDiff(d.out, &rendered, true); err != nil {
+			return err
+		}
+	default:
+		if _, err = rendered.WriteTo(d.out); err != nil {
+			return err
+		}
+	}
+
+	if result.HasDifferences() {
+		return ErrDiffFound
+	}
+
+	return nil
+}
+
+// colorizeDiff colors added and removed lines. If indented is true, leading
+// whitespace is ignored when detecting changes.
+func colorizeDiff(w io.Writer, r io.Reader, indented bool) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		t := scanner.Text()
+
+		prefix := t
+		if indented {
+			prefix = strings.TrimLeft(t, " ")
+		}
+
+		var err error
+		switch {
+		case strings.HasPrefix(prefix, "+"):
+			_, err = diffAddColor.Fprintln(w, t)
+		case strings.HasPrefix(prefix, "-"):
+			_, err = diffRemoveColor.Fprintln(w, t)
+		default:
+			_, err = fmt.Fprintln(w, t)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -25,7 +25,9 @@ import (
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/client"
 	"github.com/ksonnet/ksonnet/pkg/diff"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -67,6 +69,8 @@ func TestDiff(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			withApp(t, func(appMock *amocks.App) {
+				appMock.On("Environment", mock.Anything).Return(&app.EnvironmentConfig{}, nil)
+
 				in := map[string]interface{}{
 					OptionApp:            appMock,
 					OptionClientConfig:   &client.Config{},
@@ -107,6 +111,84 @@ func TestDiff(t *testing.T) {
 	}
 }
 
+func TestDiff_structural(t *testing.T) {
+	cases := []struct {
+		name       string
+		output     string
+		result     *diff.Result
+		expected   string
+		isRunError bool
+	}{
+		{
+			name:   "no differences",
+			output: diff.OutputJSON,
+			result: &diff.Result{},
+			expected: "{\n  \"source1\": \"\",\n  \"source2\": \"\",\n  \"differences\": false,\n  \"summary\": {\n" +
+				"    \"added\": 0,\n    \"removed\": 0,\n    \"changed\": 0,\n    \"unchanged\": 0\n  },\n" +
+				"  \"objects\": []\n}\n",
+		},
+		{
+			name:   "differences found",
+			output: diff.OutputText,
+			result: &diff.Result{
+				Summary: diff.Summary{Added: 1},
+				Objects: []diff.ObjectDiff{
+					{Kind: "ConfigMap", Namespace: "default", Name: "cm", Status: diff.ObjectAdded},
+				},
+			},
+			expected:   "+ configmap default.cm\n",
+			isRunError: true,
+		},
+		{
+			name:       "invalid output",
+			output:     "yaml",
+			result:     &diff.Result{},
+			isRunError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				appMock.On("Environment", mock.Anything).Return(&app.EnvironmentConfig{}, nil)
+
+				in := map[string]interface{}{
+					OptionApp:            appMock,
+					OptionClientConfig:   &client.Config{},
+					OptionComponentNames: []string{},
+					OptionSrc1:           "default",
+					OptionOutput:         tc.output,
+				}
+
+				d, err := NewDiff(in)
+				require.NoError(t, err)
+
+				var buf bytes.Buffer
+				d.out = &buf
+
+				d.diffFn = func(a app.App, c *client.Config, components []string, l1 *diff.Location, l2 *diff.Location) (io.Reader, error) {
+					return nil, errors.New("unexpected text diff")
+				}
+
+				d.structuralDiffFn = func(a app.App, c *client.Config, components []string, l1 *diff.Location, l2 *diff.Location) (*diff.Result, error) {
+					assert.Equal(t, "local:default", l1.String(), "location1")
+					assert.Equal(t, "remote:default", l2.String(), "location2")
+					return tc.result, nil
+				}
+
+				err = d.Run()
+				if tc.isRunError {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+
+				require.Equal(t, tc.expected, buf.String())
+			})
+		})
+	}
+}
+
 func TestDiff_requires_app(t *testing.T) {
 	in := make(map[string]interface{})
 	_, err := NewDiff(in)
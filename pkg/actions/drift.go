@@ -0,0 +1,220 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrDriftFound is an error returned when objects have drifted from what
+// was applied.
+var ErrDriftFound = errors.New("drift found")
+
+type runDriftFn func(cluster.DriftConfig, ...cluster.DriftOpts) (*cluster.DriftResult, error)
+
+// RunDrift runs `drift`.
+func RunDrift(m map[string]interface{}) error {
+	d, err := newDrift(m)
+	if err != nil {
+		return err
+	}
+
+	return d.run()
+}
+
+type driftOpt func(*Drift)
+
+// Drift collects options for reporting changes made to an environment's
+// objects outside of ksonnet.
+type Drift struct {
+	app            app.App
+	clientConfig   *client.Config
+	componentNames []string
+	envName        string
+	outputType     string
+
+	runDriftFn runDriftFn
+	out        io.Writer
+}
+
+func newDrift(m map[string]interface{}, opts ...driftOpt) (*Drift, error) {
+	ol := newOptionLoader(m)
+
+	d := &Drift{
+		app:            ol.LoadApp(),
+		clientConfig:   ol.LoadClientConfig(),
+		componentNames: ol.LoadStringSlice(OptionComponentNames),
+		outputType:     ol.LoadOptionalString(OptionOutput),
+
+		runDriftFn: cluster.RunDrift,
+		out:        os.Stdout,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	if err := setCurrentEnv(d.app, d, ol); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (d *Drift) run() error {
+	f, err := table.DetectFormat(d.outputType)
+	if err != nil {
+		return errors.Wrap(err, "detecting output format")
+	}
+
+	config := cluster.DriftConfig{
+		App:            d.app,
+		ClientConfig:   d.clientConfig,
+		ComponentNames: d.componentNames,
+		EnvName:        d.envName,
+	}
+
+	result, err := d.runDriftFn(config)
+	if err != nil {
+		return err
+	}
+
+	if !result.HasDrift() {
+		log.Infof("Objects in environment %q have not drifted", d.envName)
+		return nil
+	}
+
+	var changed, deleted [][]string
+
+	for _, od := range result.Changed {
+		for _, field := range od.Fields {
+			changed = append(changed, []string{
+				od.GroupVersionKind.GroupVersion().String(),
+				od.GroupVersionKind.Kind,
+				od.Namespace,
+				od.Name,
+				field.Path,
+				formatDriftValue(field.Applied),
+				formatDriftValue(field.Live),
+			})
+		}
+	}
+
+	for _, od := range result.Deleted {
+		deleted = append(deleted, []string{
+			od.GroupVersionKind.GroupVersion().String(),
+			od.GroupVersionKind.Kind,
+			od.Namespace,
+			od.Name,
+		})
+	}
+
+	if f == table.FormatTable {
+		err = d.renderTables(changed, deleted)
+	} else {
+		err = d.renderDocument(f, changed, deleted)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return ErrDriftFound
+}
+
+// renderTables prints changed and deleted objects in separate tables.
+func (d *Drift) renderTables(changed, deleted [][]string) error {
+	if len(changed) > 0 {
+		t := table.New("changed", d.out)
+		t.SetHeader([]string{"apiversion", "kind", "namespace", "name", "field", "applied", "live"})
+		t.AppendBulk(changed)
+
+		if err := t.Render(); err != nil {
+			return err
+		}
+	}
+
+	if len(deleted) > 0 {
+		if len(changed) > 0 {
+			fmt.Fprintln(d.out)
+		}
+
+		t := table.New("deleted", d.out)
+		t.SetHeader([]string{"apiversion", "kind", "namespace", "name"})
+		t.AppendBulk(deleted)
+
+		if err := t.Render(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderDocument prints changed and deleted objects as a single document, so
+// machine readable output can be parsed in one go. The state column is
+// "changed" or "deleted".
+func (d *Drift) renderDocument(f table.Format, changed, deleted [][]string) error {
+	t := table.New("drift", d.out)
+	t.SetFormat(f)
+	t.SetHeader([]string{"state", "apiversion", "kind", "namespace", "name", "field", "applied", "live"})
+
+	for _, row := range changed {
+		t.Append(append([]string{"changed"}, row...))
+	}
+
+	for _, row := range deleted {
+		t.Append(append(append([]string{"deleted"}, row...), "", "", ""))
+	}
+
+	return t.Render()
+}
+
+// formatDriftValue formats a field value for a table cell. Values which are
+// not strings are formatted as JSON.
+func formatDriftValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		return t
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(b)
+}
+
+func (d *Drift) setCurrentEnv(name string) {
+	d.envName = name
+}
@@ -0,0 +1,132 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestDrift(t *testing.T) {
+	deployment := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	service := schema.GroupVersionKind{Version: "v1", Kind: "Service"}
+
+	changedAndDeleted := &cluster.DriftResult{
+		Changed: []cluster.ObjectDrift{
+			{
+				GroupVersionKind: deployment,
+				Namespace:        "default",
+				Name:             "web",
+				Fields: []cluster.FieldDrift{
+					{Path: "spec.replicas", Applied: float64(2), Live: int64(5)},
+					{Path: "spec.template.spec.containers[name=web].image", Applied: "nginx:1.15", Live: "nginx:1.16"},
+					{Path: "metadata.labels.app", Applied: "web"},
+				},
+			},
+		},
+		Deleted: []cluster.ObjectDrift{
+			{GroupVersionKind: service, Namespace: "default", Name: "web"},
+		},
+	}
+
+	cases := []struct {
+		name       string
+		outputType string
+		result     *cluster.DriftResult
+		expected   string
+		isErr      bool
+	}{
+		{
+			name:   "no drift",
+			result: &cluster.DriftResult{},
+		},
+		{
+			name:     "changed and deleted objects",
+			result:   changedAndDeleted,
+			expected: "drift/output.txt",
+			isErr:    true,
+		},
+		{
+			name:       "json output",
+			outputType: "json",
+			result:     changedAndDeleted,
+			expected:   "drift/output.json",
+			isErr:      true,
+		},
+		{
+			name:       "csv output",
+			outputType: "csv",
+			result:     changedAndDeleted,
+			expected:   "drift/output.csv",
+			isErr:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:            appMock,
+					OptionClientConfig:   &client.Config{},
+					OptionComponentNames: []string{"web"},
+					OptionEnvName:        "default",
+					OptionOutput:         tc.outputType,
+				}
+
+				expected := cluster.DriftConfig{
+					App:            appMock,
+					ClientConfig:   &client.Config{},
+					ComponentNames: []string{"web"},
+					EnvName:        "default",
+				}
+
+				var buf bytes.Buffer
+
+				runDriftOpt := func(d *Drift) {
+					d.out = &buf
+					d.runDriftFn = func(config cluster.DriftConfig, opts ...cluster.DriftOpts) (*cluster.DriftResult, error) {
+						assert.Equal(t, expected, config)
+						return tc.result, nil
+					}
+				}
+
+				d, err := newDrift(in, runDriftOpt)
+				require.NoError(t, err)
+
+				err = d.run()
+				if tc.isErr {
+					require.Equal(t, ErrDriftFound, err)
+				} else {
+					require.NoError(t, err)
+				}
+
+				if tc.expected == "" {
+					require.Empty(t, buf.String())
+					return
+				}
+
+				assertOutput(t, tc.expected, buf.String())
+			})
+		})
+	}
+}
@@ -87,13 +87,15 @@ func (el *EnvList) Run() error {
 			override = "*"
 		}
 
-		rows = append(rows, []string{
-			name,
-			override,
-			env.KubernetesVersion,
-			env.Destination.Namespace,
-			env.Destination.Server,
-		})
+		if len(env.Destinations) == 0 {
+			rows = append(rows, envListRow(name, override, env.KubernetesVersion, env.Destination))
+			continue
+		}
+
+		// Environments with several destinations list each of them.
+		for _, d := range env.Destinations {
+			rows = append(rows, envListRow(app.DestinationEnvName(name, d.Name), override, env.KubernetesVersion, d))
+		}
 	}
 
 	sort.Slice(rows, func(i, j int) bool {
@@ -105,3 +107,13 @@ func (el *EnvList) Run() error {
 	return t.Render()
 
 }
+
+func envListRow(name, override, k8sVersion string, d *app.EnvironmentDestinationSpec) []string {
+	var namespace, server string
+	if d != nil {
+		namespace = d.Namespace
+		server = d.Server
+	}
+
+	return []string{name, override, k8sVersion, namespace, server}
+}
@@ -54,6 +54,30 @@ func TestEnvList(t *testing.T) {
 		appMock.On("Environments").Return(envs, nil)
 	}
 
+	setupDestinationsApp := func(appMock *amocks.App) {
+		prodEnv := &app.EnvironmentConfig{
+			KubernetesVersion: "v1.7.0",
+			Destinations: []*app.EnvironmentDestinationSpec{
+				{
+					Name:      "us-east",
+					Namespace: "prod",
+					Server:    "http://east.example.com",
+				},
+				{
+					Name:      "us-west",
+					Namespace: "prod",
+					Server:    "http://west.example.com",
+				},
+			},
+		}
+
+		envs := app.EnvironmentConfigs{
+			"prod": prodEnv,
+		}
+
+		appMock.On("Environments").Return(envs, nil)
+	}
+
 	envListFail := func(appMock *amocks.App) {
 		appMock.On("Environments").Return(nil, errors.New("failed"))
 	}
@@ -82,6 +106,11 @@ func TestEnvList(t *testing.T) {
 			outputType:   "json",
 			expectedFile: filepath.Join("env", "list", "output.json"),
 		},
+		{
+			name:         "environment with destinations",
+			initApp:      setupDestinationsApp,
+			expectedFile: filepath.Join("env", "list", "destinations.txt"),
+		},
 		{
 			name:       "invalid output format",
 			initApp:    setupValidApp,
@@ -134,6 +134,10 @@ func (es *EnvSet) updateEnvConfig(env app.EnvironmentConfig, namespace, server,
 
 	newEnv := env
 
+	if len(env.Destinations) > 0 && (server != "" || namespace != "") {
+		return errors.Errorf("environment %q has several destinations; update them in app.yaml", env.Name)
+	}
+
 	var destination *app.EnvironmentDestinationSpec
 	if env.Destination != nil {
 		var destCopy app.EnvironmentDestinationSpec
@@ -152,6 +156,10 @@ func (es *EnvSet) updateEnvConfig(env app.EnvironmentConfig, namespace, server,
 
 	newEnv.Destination = destination
 
+	return es.save(newEnv, k8sAPISpec, isOverride)
+}
+
+func (es *EnvSet) save(newEnv app.EnvironmentConfig, k8sAPISpec string, isOverride bool) error {
 	// isOverride will be set by app.AddEnvironment
 	if isOverride {
 		// Libraries will always derive from the primary app.yaml
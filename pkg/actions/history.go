@@ -0,0 +1,118 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+)
+
+type runHistoryFn func(cluster.HistoryConfig, ...cluster.HistoryOpts) ([]cluster.Revision, error)
+
+// RunHistory runs `history`.
+func RunHistory(m map[string]interface{}) error {
+	h, err := newHistory(m)
+	if err != nil {
+		return err
+	}
+
+	return h.run()
+}
+
+type historyOpt func(*History)
+
+// History collects options for listing the revisions of an environment.
+type History struct {
+	app          app.App
+	clientConfig *client.Config
+	envName      string
+	outputType   string
+
+	runHistoryFn runHistoryFn
+	out          io.Writer
+}
+
+func newHistory(m map[string]interface{}, opts ...historyOpt) (*History, error) {
+	ol := newOptionLoader(m)
+
+	h := &History{
+		app:          ol.LoadApp(),
+		clientConfig: ol.LoadClientConfig(),
+		outputType:   ol.LoadOptionalString(OptionOutput),
+
+		runHistoryFn: cluster.RunHistory,
+		out:          os.Stdout,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if err := setCurrentEnv(h.app, h, ol); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+func (h *History) run() error {
+	f, err := table.DetectFormat(h.outputType)
+	if err != nil {
+		return errors.Wrap(err, "detecting output format")
+	}
+
+	config := cluster.HistoryConfig{
+		App:          h.app,
+		ClientConfig: h.clientConfig,
+		EnvName:      h.envName,
+	}
+
+	revisions, err := h.runHistoryFn(config)
+	if err != nil {
+		return err
+	}
+
+	t := table.New("history", h.out)
+	t.SetFormat(f)
+	t.SetHeader([]string{"revision", "applied", "description", "objects"})
+
+	for _, revision := range revisions {
+		t.Append([]string{
+			strconv.Itoa(revision.Number),
+			revision.AppliedAt.Format(time.RFC3339),
+			revision.Description,
+			strconv.Itoa(len(revision.Objects)),
+		})
+	}
+
+	return t.Render()
+}
+
+func (h *History) setCurrentEnv(name string) {
+	h.envName = name
+}
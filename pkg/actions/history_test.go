@@ -0,0 +1,123 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestHistory(t *testing.T) {
+	cases := []struct {
+		name        string
+		isSetupErr  bool
+		currentName string
+		envName     string
+	}{
+		{
+			name:    "with a supplied env",
+			envName: "default",
+		},
+		{
+			name:        "with a current env",
+			currentName: "default",
+		},
+		{
+			name:       "without supplied or current env",
+			isSetupErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				appMock.On("CurrentEnvironment").Return(tc.currentName)
+
+				in := map[string]interface{}{
+					OptionApp:          appMock,
+					OptionClientConfig: &client.Config{},
+					OptionEnvName:      tc.envName,
+				}
+
+				expected := cluster.HistoryConfig{
+					App:          appMock,
+					ClientConfig: &client.Config{},
+					EnvName:      "default",
+				}
+
+				var buf bytes.Buffer
+
+				runHistoryOpt := func(h *History) {
+					h.out = &buf
+					h.runHistoryFn = func(config cluster.HistoryConfig, opts ...cluster.HistoryOpts) ([]cluster.Revision, error) {
+						assert.Equal(t, expected, config)
+
+						return []cluster.Revision{
+							{
+								Number:      1,
+								AppliedAt:   time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC),
+								Description: "apply",
+								Objects:     []*unstructured.Unstructured{{}, {}},
+							},
+							{
+								Number:      2,
+								AppliedAt:   time.Date(2018, 6, 2, 12, 0, 0, 0, time.UTC),
+								Description: "rollback to 1",
+								Objects:     []*unstructured.Unstructured{{}},
+							},
+						}, nil
+					}
+				}
+
+				h, err := newHistory(in, runHistoryOpt)
+				if tc.isSetupErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				err = h.run()
+				require.NoError(t, err)
+
+				expectedOutput := `REVISION APPLIED              DESCRIPTION   OBJECTS
+======== =======              ===========   =======
+1        2018-06-01T12:00:00Z apply         2
+2        2018-06-02T12:00:00Z rollback to 1 1
+`
+				require.Equal(t, expectedOutput, buf.String())
+			})
+		})
+	}
+}
+
+func TestHistory_invalid_input(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionClientConfig: "invalid",
+		}
+
+		_, err := newHistory(in)
+		require.Error(t, err)
+	})
+}
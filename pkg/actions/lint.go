@@ -0,0 +1,141 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/pkg/lint"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+)
+
+// RunLint runs `lint`.
+func RunLint(m map[string]interface{}) error {
+	l, err := NewLint(m)
+	if err != nil {
+		return err
+	}
+
+	return l.Run()
+}
+
+type lintModuleFn func(a app.App, m component.Module, components []component.Component) ([]lint.Problem, error)
+
+// Lint lints the component sources of an app.
+type Lint struct {
+	app    app.App
+	module string
+	output string
+	cm     component.Manager
+	out    io.Writer
+
+	lintModuleFn lintModuleFn
+}
+
+// NewLint creates an instance of Lint.
+func NewLint(m map[string]interface{}) (*Lint, error) {
+	ol := newOptionLoader(m)
+
+	l := &Lint{
+		app:    ol.LoadApp(),
+		module: ol.LoadOptionalString(OptionModule),
+		output: ol.LoadOptionalString(OptionOutput),
+
+		cm:           component.DefaultManager,
+		out:          os.Stdout,
+		lintModuleFn: lintModule,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return l, nil
+}
+
+// Run lints the components of every module, or of a single module if one was
+// specified. It fails if any problems are found.
+func (l *Lint) Run() error {
+	var modules []component.Module
+	if l.module != "" {
+		m, err := l.cm.Module(l.app, l.module)
+		if err != nil {
+			return err
+		}
+		modules = append(modules, m)
+	} else {
+		var err error
+		modules, err = l.cm.Modules(l.app, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	var rows [][]string
+
+	for _, m := range modules {
+		components, err := l.cm.Components(l.app, m.Name())
+		if err != nil {
+			return err
+		}
+
+		problems, err := l.lintModuleFn(l.app, m, components)
+		if err != nil {
+			return errors.Wrapf(err, "linting module %q", m.Name())
+		}
+
+		for _, p := range problems {
+			rows = append(rows, []string{p.Component, l.location(p), p.Message})
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	t := table.New("lint", l.out)
+	f, err := table.DetectFormat(l.output)
+	if err != nil {
+		return errors.Wrap(err, "detecting output format")
+	}
+
+	t.SetFormat(f)
+	t.SetHeader([]string{"component", "location", "problem"})
+	t.AppendBulk(rows)
+	if err = t.Render(); err != nil {
+		return err
+	}
+
+	return errors.Errorf("found %d problem(s)", len(rows))
+}
+
+// location returns the location of a problem relative to the app root.
+func (l *Lint) location(p lint.Problem) string {
+	if rel, err := filepath.Rel(l.app.Root(), p.Path); err == nil {
+		p.Path = rel
+	}
+
+	return p.Location()
+}
+
+func lintModule(a app.App, m component.Module, components []component.Component) ([]lint.Problem, error) {
+	return lint.Module(a.Fs(), m, components)
+}
@@ -0,0 +1,129 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	cmocks "github.com/ksonnet/ksonnet/pkg/component/mocks"
+	"github.com/ksonnet/ksonnet/pkg/lint"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	cases := []struct {
+		name     string
+		module   string
+		problems map[string][]lint.Problem
+		output   string
+		isErr    bool
+	}{
+		{
+			name: "without problems",
+		},
+		{
+			name: "with problems",
+			problems: map[string][]lint.Problem{
+				"/": {
+					{
+						Component: "guestbook",
+						Path:      "/components/guestbook.jsonnet",
+						Line:      4,
+						Message:   `param "nmae" of component "guestbook" is not defined`,
+					},
+				},
+				"nested": {
+					{
+						Component: "nested.redis",
+						Path:      "/components/nested/params.libsonnet",
+						Line:      6,
+						Message:   `param "port" of component "redis" is not used`,
+					},
+				},
+			},
+			output: "lint/output.txt",
+			isErr:  true,
+		},
+		{
+			name:   "with a module",
+			module: "nested",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:    appMock,
+					OptionModule: tc.module,
+				}
+
+				a, err := NewLint(in)
+				require.NoError(t, err)
+
+				root := &cmocks.Module{}
+				root.On("Name").Return("/")
+				nested := &cmocks.Module{}
+				nested.On("Name").Return("nested")
+
+				cm := &cmocks.Manager{}
+				cm.On("Modules", appMock, "").Return([]component.Module{root, nested}, nil)
+				cm.On("Module", appMock, "nested").Return(nested, nil)
+				cm.On("Components", appMock, mock.Anything).Return([]component.Component{}, nil)
+				a.cm = cm
+
+				var linted []string
+				a.lintModuleFn = func(a app.App, m component.Module, components []component.Component) ([]lint.Problem, error) {
+					linted = append(linted, m.Name())
+					return tc.problems[m.Name()], nil
+				}
+
+				var buf bytes.Buffer
+				a.out = &buf
+
+				err = a.Run()
+				if tc.module != "" {
+					require.Equal(t, []string{tc.module}, linted)
+				} else {
+					require.Equal(t, []string{"/", "nested"}, linted)
+				}
+
+				if tc.output != "" {
+					assertOutput(t, tc.output, buf.String())
+				} else {
+					require.Empty(t, buf.String())
+				}
+
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+			})
+		})
+	}
+}
+
+func TestLint_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewLint(in)
+	require.Error(t, err)
+}
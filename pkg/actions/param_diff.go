@@ -16,8 +16,11 @@
 package actions
 
 import (
+	"encoding/json"
 	"io"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/component"
@@ -35,11 +38,21 @@ func RunParamDiff(m map[string]interface{}) error {
 	return pd.Run()
 }
 
-// ParamDiff shows difference between params in two environments.
+const (
+	// paramStatusDiffers marks a param whose value is not the same in every environment.
+	paramStatusDiffers = "differs"
+	// paramStatusMissing marks a param which isn't set in every environment.
+	paramStatusMissing = "missing"
+)
+
+// ParamDiff shows difference between params in two environments. In matrix
+// mode, it compares params across any number of environments.
 type ParamDiff struct {
 	app           app.App
 	envName1      string
 	envName2      string
+	envNames      []string
+	matrix        bool
 	componentName string
 	outputType    string
 
@@ -53,8 +66,7 @@ func NewParamDiff(m map[string]interface{}) (*ParamDiff, error) {
 
 	pd := &ParamDiff{
 		app:           ol.LoadApp(),
-		envName1:      ol.LoadString(OptionEnvName1),
-		envName2:      ol.LoadString(OptionEnvName2),
+		matrix:        ol.LoadOptionalBool(OptionMatrix),
 		componentName: ol.LoadOptionalString(OptionComponentName),
 		outputType:    ol.LoadOptionalString(OptionOutput),
 
@@ -62,6 +74,13 @@ func NewParamDiff(m map[string]interface{}) (*ParamDiff, error) {
 		out:              os.Stdout,
 	}
 
+	if pd.matrix {
+		pd.envNames = ol.LoadStringSlice(OptionEnvNames)
+	} else {
+		pd.envName1 = ol.LoadString(OptionEnvName1)
+		pd.envName2 = ol.LoadString(OptionEnvName2)
+	}
+
 	if ol.err != nil {
 		return nil, ol.err
 	}
@@ -71,6 +90,10 @@ func NewParamDiff(m map[string]interface{}) (*ParamDiff, error) {
 
 // Run runs the action.
 func (pd *ParamDiff) Run() error {
+	if pd.matrix {
+		return pd.runMatrix()
+	}
+
 	env1Params, err := pd.moduleParams(pd.envName1)
 	if err != nil {
 		return err
@@ -145,6 +168,178 @@ func (pd *ParamDiff) checkMissing(env1, env2 []component.ModuleParameter) [][]st
 	return rows
 }
 
+// runMatrix prints a row for each component param with a column for every
+// environment. The status column flags params which differ between
+// environments or are missing from some of them.
+func (pd *ParamDiff) runMatrix() error {
+	envNames, err := pd.matrixEnvNames()
+	if err != nil {
+		return err
+	}
+
+	type paramKey struct {
+		component string
+		key       string
+	}
+
+	values := make(map[paramKey]map[string]string)
+	var keys []paramKey
+
+	for _, envName := range envNames {
+		params, err := pd.moduleParams(envName)
+		if err != nil {
+			return err
+		}
+
+		for _, mp := range params {
+			if pd.componentName != "" && pd.componentName != mp.Component {
+				continue
+			}
+
+			k := paramKey{component: mp.Component, key: mp.Key}
+			if _, ok := values[k]; !ok {
+				values[k] = make(map[string]string)
+				keys = append(keys, k)
+			}
+			values[k][envName] = mp.Value
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].component != keys[j].component {
+			return keys[i].component < keys[j].component
+		}
+		return keys[i].key < keys[j].key
+	})
+
+	var matrix []paramMatrixRow
+	for _, k := range keys {
+		row := paramMatrixRow{
+			Component: k.component,
+			Param:     k.key,
+			Values:    make(map[string]*string),
+			Status:    paramStatus(envNames, values[k]),
+		}
+
+		for _, envName := range envNames {
+			var value *string
+			if v, ok := values[k][envName]; ok {
+				value = &v
+			}
+			row.Values[envName] = value
+		}
+
+		matrix = append(matrix, row)
+	}
+
+	f, err := table.DetectFormat(pd.outputType)
+	if err != nil {
+		return errors.Wrap(err, "detecting output format")
+	}
+
+	if f == table.FormatJSON {
+		return pd.renderMatrixJSON(envNames, matrix)
+	}
+
+	var rows [][]string
+	for _, mr := range matrix {
+		row := []string{mr.Component, mr.Param}
+		for _, envName := range envNames {
+			var v string
+			if mr.Values[envName] != nil {
+				v = *mr.Values[envName]
+			}
+			row = append(row, v)
+		}
+		row = append(row, mr.Status)
+
+		rows = append(rows, row)
+	}
+
+	header := []string{"component", "param"}
+	header = append(header, envNames...)
+	header = append(header, "status")
+
+	return pd.render("paramMatrix", header, rows)
+}
+
+// paramMatrixRow is a param's values across environments. A value is nil if
+// the param is missing from that environment.
+type paramMatrixRow struct {
+	Component string             `json:"component"`
+	Param     string             `json:"param"`
+	Values    map[string]*string `json:"values"`
+	Status    string             `json:"status"`
+}
+
+// paramMatrixOutput is the structure for printing the matrix as JSON.
+// Environment names can't be columns, because they could collide with the
+// other fields of a row.
+type paramMatrixOutput struct {
+	Kind         string           `json:"kind"`
+	Environments []string         `json:"environments"`
+	Data         []paramMatrixRow `json:"data"`
+}
+
+func (pd *ParamDiff) renderMatrixJSON(envNames []string, matrix []paramMatrixRow) error {
+	if matrix == nil {
+		matrix = make([]paramMatrixRow, 0)
+	}
+
+	encoder := json.NewEncoder(pd.out)
+	encoder.SetIndent("", "\t")
+
+	return encoder.Encode(&paramMatrixOutput{
+		Kind:         "paramMatrix",
+		Environments: envNames,
+		Data:         matrix,
+	})
+}
+
+// matrixEnvNames returns the environments to compare. If none were
+// specified, all environments in the app are compared.
+func (pd *ParamDiff) matrixEnvNames() ([]string, error) {
+	if len(pd.envNames) > 0 {
+		return pd.envNames, nil
+	}
+
+	envs, err := pd.app.Environments()
+	if err != nil {
+		return nil, errors.Wrap(err, "loading environments")
+	}
+
+	var envNames []string
+	for name := range envs {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+
+	return envNames, nil
+}
+
+// paramStatus describes how a param's values compare across environments.
+// It is blank if the param has the same value everywhere.
+func paramStatus(envNames []string, values map[string]string) string {
+	var status []string
+
+	seen := make(map[string]bool)
+	for _, envName := range envNames {
+		if v, ok := values[envName]; ok {
+			seen[v] = true
+		}
+	}
+
+	if len(seen) > 1 {
+		status = append(status, paramStatusDiffers)
+	}
+
+	if len(values) < len(envNames) {
+		status = append(status, paramStatusMissing)
+	}
+
+	return strings.Join(status, ",")
+}
+
 func (pd *ParamDiff) moduleParams(envName string) ([]component.ModuleParameter, error) {
 	modules, err := pd.modulesFromEnvFn(pd.app, envName)
 	if err != nil {
@@ -165,7 +360,11 @@ func (pd *ParamDiff) moduleParams(envName string) ([]component.ModuleParameter,
 }
 
 func (pd *ParamDiff) print(rows [][]string) error {
-	t := table.New("paramDiff", pd.out)
+	return pd.render("paramDiff", []string{"component", "param", "env1", "env2"}, rows)
+}
+
+func (pd *ParamDiff) render(name string, header []string, rows [][]string) error {
+	t := table.New(name, pd.out)
 
 	f, err := table.DetectFormat(pd.outputType)
 	if err != nil {
@@ -173,7 +372,7 @@ func (pd *ParamDiff) print(rows [][]string) error {
 	}
 	t.SetFormat(f)
 
-	t.SetHeader([]string{"component", "param", "env1", "env2"})
+	t.SetHeader(header)
 	t.AppendBulk(rows)
 
 	return t.Render()
@@ -116,3 +116,144 @@ func TestParamDiff_requires_app(t *testing.T) {
 	_, err := NewParamDiff(in)
 	require.Error(t, err)
 }
+
+func TestParamDiff_matrix(t *testing.T) {
+	cases := []struct {
+		name       string
+		envNames   []string
+		outputType string
+		outputName string
+		isErr      bool
+	}{
+		{
+			name:       "output table",
+			envNames:   []string{"env1", "env2", "env3"},
+			outputType: "table",
+			outputName: filepath.Join("param", "diff", "matrix.txt"),
+		},
+		{
+			name:       "output json",
+			envNames:   []string{"env1", "env2", "env3"},
+			outputType: "json",
+			outputName: filepath.Join("param", "diff", "matrix.json"),
+		},
+		{
+			name:       "output csv",
+			envNames:   []string{"env1", "env2", "env3"},
+			outputType: "csv",
+			outputName: filepath.Join("param", "diff", "matrix.csv"),
+		},
+		{
+			name:       "all environments",
+			outputType: "table",
+			outputName: filepath.Join("param", "diff", "matrix.txt"),
+		},
+		{
+			name:       "invalid output type",
+			envNames:   []string{"env1", "env2", "env3"},
+			outputType: "invalid",
+			isErr:      true,
+		},
+		{
+			name:     "unknown environment",
+			envNames: []string{"env1", "env4"},
+			isErr:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				envs := app.EnvironmentConfigs{
+					"env3": &app.EnvironmentConfig{},
+					"env1": &app.EnvironmentConfig{},
+					"env2": &app.EnvironmentConfig{},
+				}
+				appMock.On("Environments").Return(envs, nil)
+
+				envParams := map[string][]component.ModuleParameter{
+					"env1": {
+						{Component: "a", Key: "a", Value: "a"},
+						{Component: "a", Key: "b", Value: "b1"},
+						{Component: "c", Key: "c", Value: "c"},
+					},
+					"env2": {
+						{Component: "a", Key: "a", Value: "a"},
+						{Component: "a", Key: "b", Value: "b2"},
+						{Component: "d", Key: "d", Value: "d"},
+					},
+					"env3": {
+						{Component: "a", Key: "a", Value: "a"},
+						{Component: "a", Key: "b", Value: "b1"},
+						{Component: "c", Key: "c", Value: "c3"},
+					},
+				}
+
+				in := map[string]interface{}{
+					OptionApp:      appMock,
+					OptionEnvNames: tc.envNames,
+					OptionMatrix:   true,
+					OptionOutput:   tc.outputType,
+				}
+
+				a, err := NewParamDiff(in)
+				require.NoError(t, err)
+
+				a.modulesFromEnvFn = func(_ app.App, envName string) ([]component.Module, error) {
+					params, ok := envParams[envName]
+					if !ok {
+						return nil, errors.Errorf("unknown env %s", envName)
+					}
+
+					m := &mocks.Module{}
+					m.On("Params", envName).Return(params, nil)
+					return []component.Module{m}, nil
+				}
+
+				var buf bytes.Buffer
+				a.out = &buf
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				assertOutput(t, tc.outputName, buf.String())
+			})
+		})
+	}
+}
+
+func TestParamDiff_matrix_component(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:           appMock,
+			OptionEnvNames:      []string{"env1", "env2"},
+			OptionMatrix:        true,
+			OptionComponentName: "a",
+		}
+
+		a, err := NewParamDiff(in)
+		require.NoError(t, err)
+
+		a.modulesFromEnvFn = func(_ app.App, envName string) ([]component.Module, error) {
+			m := &mocks.Module{}
+			params := []component.ModuleParameter{
+				{Component: "a", Key: "a", Value: envName},
+				{Component: "b", Key: "b", Value: "b"},
+			}
+			m.On("Params", envName).Return(params, nil)
+			return []component.Module{m}, nil
+		}
+
+		var buf bytes.Buffer
+		a.out = &buf
+
+		err = a.Run()
+		require.NoError(t, err)
+
+		assertOutput(t, filepath.Join("param", "diff", "matrix_component.txt"), buf.String())
+	})
+}
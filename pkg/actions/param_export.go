@@ -0,0 +1,156 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/pkg/params"
+	"github.com/ksonnet/ksonnet/pkg/pipeline"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Param document formats.
+const (
+	paramDocumentYAML = "yaml"
+	paramDocumentJSON = "json"
+)
+
+// paramDocument is the params of components keyed by component name and
+// param name.
+type paramDocument map[string]map[string]interface{}
+
+// RunParamExport runs `param export`.
+func RunParamExport(m map[string]interface{}) error {
+	pe, err := NewParamExport(m)
+	if err != nil {
+		return err
+	}
+
+	return pe.Run()
+}
+
+// ParamExport exports the params of an environment's components.
+type ParamExport struct {
+	app        app.App
+	envName    string
+	outputType string
+
+	out             io.Writer
+	modulesFn       func() ([]component.Module, error)
+	envParametersFn func(moduleName string, inherited bool) (string, error)
+}
+
+// NewParamExport creates an instance of ParamExport.
+func NewParamExport(m map[string]interface{}) (*ParamExport, error) {
+	ol := newOptionLoader(m)
+
+	pe := &ParamExport{
+		app:        ol.LoadApp(),
+		outputType: ol.LoadOptionalString(OptionOutput),
+
+		out: os.Stdout,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	if err := setCurrentEnv(pe.app, pe, ol); err != nil {
+		return nil, err
+	}
+
+	p := pipeline.New(pe.app, pe.envName)
+	pe.modulesFn = p.Modules
+	pe.envParametersFn = p.EnvParameters
+
+	return pe, nil
+}
+
+// Run runs the ParamExport action.
+func (pe *ParamExport) Run() error {
+	modules, err := pe.modulesFn()
+	if err != nil {
+		return err
+	}
+
+	doc, err := envParamDocument(modules, pe.envParametersFn)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch pe.outputType {
+	case "", paramDocumentYAML:
+		data, err = yaml.Marshal(doc)
+	case paramDocumentJSON:
+		data, err = json.MarshalIndent(doc, "", "  ")
+		data = append(data, '\n')
+	default:
+		return errors.Errorf("unsupported output format %q; use %s or %s",
+			pe.outputType, paramDocumentYAML, paramDocumentJSON)
+	}
+	if err != nil {
+		return errors.Wrap(err, "encoding params")
+	}
+
+	_, err = pe.out.Write(data)
+	return err
+}
+
+// envParamDocument creates a document with the evaluated params which an
+// environment sets for each of its components. Params inherited from modules
+// are left out. Components in nested modules are named with their module.
+func envParamDocument(modules []component.Module, envParametersFn func(string, bool) (string, error)) (paramDocument, error) {
+	doc := paramDocument{}
+
+	for _, m := range modules {
+		source, err := envParametersFn(m.Name(), false)
+		if err != nil {
+			return nil, err
+		}
+
+		values, err := params.DecodeModuleComponents(m.Name(), source)
+		if err != nil {
+			return nil, errors.Wrapf(err, "module %q", m.Name())
+		}
+
+		components, err := m.Components()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range components {
+			componentParams, ok := values[c.Name(false)].(map[string]interface{})
+			if !ok {
+				componentParams = make(map[string]interface{})
+			}
+
+			doc[c.Name(true)] = componentParams
+		}
+	}
+
+	return doc, nil
+}
+
+func (pe *ParamExport) setCurrentEnv(name string) {
+	pe.envName = name
+}
@@ -0,0 +1,144 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	cmocks "github.com/ksonnet/ksonnet/pkg/component/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockParamModule(name string, componentNames ...string) *cmocks.Module {
+	m := &cmocks.Module{}
+	m.On("Name").Return(name)
+
+	var components []component.Component
+	for _, componentName := range componentNames {
+		c := &cmocks.Component{}
+		c.On("Name", false).Return(componentName)
+		if name == "/" {
+			c.On("Name", true).Return(componentName)
+		} else {
+			c.On("Name", true).Return(name + "." + componentName)
+		}
+		components = append(components, c)
+	}
+	m.On("Components").Return(components, nil)
+
+	return m
+}
+
+func fakeParamModules() ([]component.Module, error) {
+	modules := []component.Module{
+		mockParamModule("/", "deployment"),
+		mockParamModule("nested", "web"),
+	}
+
+	return modules, nil
+}
+
+func fakeParamEnvParameters(moduleName string, inherited bool) (string, error) {
+	if moduleName == "nested" {
+		return `{"components": {"web": {"port": 80}, "nested.web": {"port": 8080}}}`, nil
+	}
+
+	if !inherited {
+		return `{"components": {"deployment": {"replicas": 2}, "nested.web": {"port": 8080}}}`, nil
+	}
+
+	return `{"components": {"deployment": {"image": "nginx", "replicas": 2, "labels": {"tier": "web"}}, "nested.web": {"port": 8080}}}`, nil
+}
+
+func TestParamExport(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		cases := []struct {
+			name       string
+			outputType string
+			outputFile string
+			isErr      bool
+		}{
+			{
+				name:       "default",
+				outputFile: filepath.Join("param", "export", "output.yaml"),
+			},
+			{
+				name:       "yaml",
+				outputType: "yaml",
+				outputFile: filepath.Join("param", "export", "output.yaml"),
+			},
+			{
+				name:       "json",
+				outputType: "json",
+				outputFile: filepath.Join("param", "export", "output.json"),
+			},
+			{
+				name:       "invalid output type",
+				outputType: "table",
+				isErr:      true,
+			},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				in := map[string]interface{}{
+					OptionApp:     appMock,
+					OptionEnvName: "envName",
+					OptionOutput:  tc.outputType,
+				}
+
+				a, err := NewParamExport(in)
+				require.NoError(t, err)
+
+				a.modulesFn = fakeParamModules
+				a.envParametersFn = func(moduleName string, inherited bool) (string, error) {
+					assert.False(t, inherited, "should not request inherited parameters")
+					return fakeParamEnvParameters(moduleName, inherited)
+				}
+
+				var buf bytes.Buffer
+				a.out = &buf
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				assertOutput(t, tc.outputFile, buf.String())
+			})
+		}
+	})
+}
+
+func TestParamExport_requires_env(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		appMock.On("CurrentEnvironment").Return("")
+
+		in := map[string]interface{}{
+			OptionApp: appMock,
+		}
+
+		_, err := NewParamExport(in)
+		require.Error(t, err)
+	})
+}
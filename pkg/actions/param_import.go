@@ -0,0 +1,195 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/ksonnet/ksonnet/pkg/pipeline"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// RunParamImport runs `param import`.
+func RunParamImport(m map[string]interface{}) error {
+	pi, err := NewParamImport(m)
+	if err != nil {
+		return err
+	}
+
+	return pi.Run()
+}
+
+// ParamImport sets the params of an environment's components from a YAML or
+// JSON document, as created by `param export`.
+type ParamImport struct {
+	app      app.App
+	envName  string
+	filename string
+
+	in              io.Reader
+	modulesFn       func() ([]component.Module, error)
+	envParametersFn func(moduleName string, inherited bool) (string, error)
+	validateParamFn func(ksApp app.App, name string, path []string, value interface{}) error
+	setParamsFn     func(ksApp app.App, envName, name string, values map[string]interface{}) error
+}
+
+// NewParamImport creates an instance of ParamImport.
+func NewParamImport(m map[string]interface{}) (*ParamImport, error) {
+	ol := newOptionLoader(m)
+
+	pi := &ParamImport{
+		app:      ol.LoadApp(),
+		filename: ol.LoadString(OptionFilename),
+
+		in:              os.Stdin,
+		validateParamFn: component.ValidateParam,
+		setParamsFn:     setEnvParamValues,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	if err := setCurrentEnv(pi.app, pi, ol); err != nil {
+		return nil, err
+	}
+
+	p := pipeline.New(pi.app, pi.envName)
+	pi.modulesFn = p.Modules
+	pi.envParametersFn = p.EnvParameters
+
+	return pi, nil
+}
+
+// Run runs the ParamImport action. Only params whose values differ from the
+// ones the environment sets are set, and every value is validated before any
+// params file is rewritten.
+func (pi *ParamImport) Run() error {
+	if envName, destName := app.SplitDestination(pi.envName); destName != "" {
+		return errors.Errorf("params can not be imported into destination %q; import them into environment %q", destName, envName)
+	}
+
+	doc, err := pi.read()
+	if err != nil {
+		return err
+	}
+
+	modules, err := pi.modulesFn()
+	if err != nil {
+		return err
+	}
+
+	current, err := envParamDocument(modules, pi.envParametersFn)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for name := range doc {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	changes := make(map[string]map[string]interface{})
+	for _, name := range names {
+		currentParams, ok := current[name]
+		if !ok {
+			return errors.Errorf("component %q is not in environment %q", name, pi.envName)
+		}
+
+		for paramName, value := range doc[name] {
+			if value == nil {
+				return errors.Errorf("param %q of component %q is null; use `ks param delete` to remove params", paramName, name)
+			}
+
+			if reflect.DeepEqual(currentParams[paramName], value) {
+				continue
+			}
+
+			if err = pi.validateParamFn(pi.app, name, []string{paramName}, value); err != nil {
+				return err
+			}
+
+			if changes[name] == nil {
+				changes[name] = make(map[string]interface{})
+			}
+			changes[name][paramName] = value
+		}
+	}
+
+	if len(changes) == 0 {
+		logrus.Info("No params were changed")
+		return nil
+	}
+
+	for _, name := range names {
+		values, ok := changes[name]
+		if !ok {
+			continue
+		}
+
+		if err = pi.setParamsFn(pi.app, pi.envName, name, values); err != nil {
+			return errors.Wrapf(err, "setting params for component %q", name)
+		}
+
+		logrus.Infof("Set %d params for component %q in environment %q", len(values), name, pi.envName)
+	}
+
+	return nil
+}
+
+// read reads the document to import. A filename of "-" reads standard input.
+func (pi *ParamImport) read() (paramDocument, error) {
+	var data []byte
+	var err error
+	if pi.filename == "-" {
+		data, err = ioutil.ReadAll(pi.in)
+	} else {
+		data, err = afero.ReadFile(pi.app.Fs(), pi.filename)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", pi.filename)
+	}
+
+	doc := paramDocument{}
+	if err = yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrapf(err, "decoding %s", pi.filename)
+	}
+
+	return doc, nil
+}
+
+func setEnvParamValues(ksApp app.App, envName, name string, values map[string]interface{}) error {
+	spc := env.SetParamsConfig{
+		App: ksApp,
+	}
+
+	return env.SetParamValues(envName, name, values, spc)
+}
+
+func (pi *ParamImport) setCurrentEnv(name string) {
+	pi.envName = name
+}
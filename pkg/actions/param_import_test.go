@@ -0,0 +1,217 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamImport(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		stageFile(t, appMock.Fs(), "param/import/params.yaml", "/params.yaml")
+
+		in := map[string]interface{}{
+			OptionApp:      appMock,
+			OptionEnvName:  "envName",
+			OptionFilename: "/params.yaml",
+		}
+
+		a, err := NewParamImport(in)
+		require.NoError(t, err)
+
+		a.modulesFn = fakeParamModules
+		a.envParametersFn = fakeParamEnvParameters
+
+		var validated []string
+		a.validateParamFn = func(ksApp app.App, name string, path []string, value interface{}) error {
+			validated = append(validated, name+"."+strings.Join(path, "."))
+			return nil
+		}
+
+		set := make(map[string]map[string]interface{})
+		a.setParamsFn = func(ksApp app.App, envName, name string, values map[string]interface{}) error {
+			assert.Equal(t, "envName", envName)
+			set[name] = values
+			return nil
+		}
+
+		err = a.Run()
+		require.NoError(t, err)
+
+		expected := map[string]map[string]interface{}{
+			"deployment": {
+				"image":    "nginx:1.15",
+				"labels":   map[string]interface{}{"tier": "web"},
+				"replicas": "2",
+			},
+			"nested.web": {
+				"path": "/",
+			},
+		}
+		assert.Equal(t, expected, set)
+		sort.Strings(validated)
+		assert.Equal(t, []string{"deployment.image", "deployment.labels", "deployment.replicas", "nested.web.path"}, validated)
+	})
+}
+
+func TestParamImport_stdin(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:      appMock,
+			OptionEnvName:  "envName",
+			OptionFilename: "-",
+		}
+
+		a, err := NewParamImport(in)
+		require.NoError(t, err)
+
+		a.in = strings.NewReader(`{"deployment": {"replicas": 3}}`)
+		a.modulesFn = fakeParamModules
+		a.envParametersFn = fakeParamEnvParameters
+		a.validateParamFn = func(app.App, string, []string, interface{}) error {
+			return nil
+		}
+
+		var set map[string]interface{}
+		a.setParamsFn = func(ksApp app.App, envName, name string, values map[string]interface{}) error {
+			assert.Equal(t, "deployment", name)
+			set = values
+			return nil
+		}
+
+		err = a.Run()
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]interface{}{"replicas": float64(3)}, set)
+	})
+}
+
+func TestParamImport_invalid(t *testing.T) {
+	cases := []struct {
+		name          string
+		doc           string
+		validateErr   error
+		expectedError string
+	}{
+		{
+			name:          "unknown component",
+			doc:           `{"service": {"port": 80}}`,
+			expectedError: `component "service" is not in environment "envName"`,
+		},
+		{
+			name:          "null value",
+			doc:           `{"deployment": {"replicas": null}}`,
+			expectedError: "param \"replicas\" of component \"deployment\" is null; use `ks param delete` to remove params",
+		},
+		{
+			name:          "invalid value",
+			doc:           `{"deployment": {"image": "nginx:1.15"}, "nested.web": {"port": "http"}}`,
+			validateErr:   errors.New("invalid"),
+			expectedError: "invalid",
+		},
+		{
+			name:          "invalid document",
+			doc:           `[`,
+			expectedError: "decoding -",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:      appMock,
+					OptionEnvName:  "envName",
+					OptionFilename: "-",
+				}
+
+				a, err := NewParamImport(in)
+				require.NoError(t, err)
+
+				a.in = strings.NewReader(tc.doc)
+				a.modulesFn = fakeParamModules
+				a.envParametersFn = fakeParamEnvParameters
+				a.validateParamFn = func(ksApp app.App, name string, path []string, value interface{}) error {
+					if name == "nested.web" {
+						return tc.validateErr
+					}
+					return nil
+				}
+				a.setParamsFn = func(app.App, string, string, map[string]interface{}) error {
+					return errors.New("params should not be set")
+				}
+
+				err = a.Run()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			})
+		})
+	}
+}
+
+func TestParamImport_destination(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:      appMock,
+			OptionEnvName:  "envName@us-east",
+			OptionFilename: "-",
+		}
+
+		a, err := NewParamImport(in)
+		require.NoError(t, err)
+
+		a.in = strings.NewReader(`{"deployment": {"replicas": 3}}`)
+		a.setParamsFn = func(app.App, string, string, map[string]interface{}) error {
+			return errors.New("params should not be set")
+		}
+
+		err = a.Run()
+		require.Error(t, err)
+	})
+}
+
+func TestParamImport_unchanged(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		require.NoError(t, afero.WriteFile(appMock.Fs(), "/params.json", []byte(`{"nested.web": {"port": 8080}}`), 0644))
+
+		in := map[string]interface{}{
+			OptionApp:      appMock,
+			OptionEnvName:  "envName",
+			OptionFilename: "/params.json",
+		}
+
+		a, err := NewParamImport(in)
+		require.NoError(t, err)
+
+		a.modulesFn = fakeParamModules
+		a.envParametersFn = fakeParamEnvParameters
+		a.setParamsFn = func(app.App, string, string, map[string]interface{}) error {
+			return errors.New("params should not be set")
+		}
+
+		err = a.Run()
+		require.NoError(t, err)
+	})
+}
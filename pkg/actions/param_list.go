@@ -18,6 +18,7 @@ package actions
 import (
 	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -26,6 +27,7 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/component"
 	"github.com/ksonnet/ksonnet/pkg/params"
 	"github.com/ksonnet/ksonnet/pkg/pipeline"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
 	"github.com/ksonnet/ksonnet/pkg/util/table"
 )
 
@@ -53,15 +55,19 @@ type ParamList struct {
 	moduleName     string
 	componentName  string
 	envName        string
+	explain        bool
 	outputType     string
 	withoutModules bool
 
 	out          io.Writer
 	findModuleFn findModuleFn
 
-	modulesFn       func() ([]component.Module, error)
-	envParametersFn func(moduleName string, inherited bool) (string, error)
-	lister          paramsLister
+	modulesFn        func() ([]component.Module, error)
+	envParametersFn  func(moduleName string, inherited bool) (string, error)
+	loadSecretsFn    func(a app.App, envName string) (*secrets.Secrets, error)
+	explainFn        func(params.ExplainConfig) ([]params.Provenance, error)
+	validateParamsFn func(m component.Module, source string) error
+	lister           paramsLister
 }
 
 // NewParamList creates an instances of ParamList.
@@ -73,20 +79,28 @@ func NewParamList(m map[string]interface{}) (*ParamList, error) {
 		moduleName:     ol.LoadOptionalString(OptionModule),
 		componentName:  ol.LoadOptionalString(OptionComponentName),
 		envName:        ol.LoadOptionalString(OptionEnvName),
+		explain:        ol.LoadOptionalBool(OptionExplain),
 		outputType:     ol.LoadOptionalString(OptionOutput),
 		withoutModules: ol.LoadOptionalBool(OptionWithoutModules),
 
-		out:          os.Stdout,
-		findModuleFn: component.GetModule,
+		out:           os.Stdout,
+		findModuleFn:  component.GetModule,
+		loadSecretsFn: secrets.Load,
+		explainFn:     params.Explain,
 	}
 
 	if ol.err != nil {
 		return nil, ol.err
 	}
 
+	if pl.explain && pl.envName == "" {
+		return nil, errors.New("explaining parameters requires an environment")
+	}
+
 	p := pipeline.New(pl.app, pl.envName)
 	pl.modulesFn = p.Modules
 	pl.envParametersFn = p.EnvParameters
+	pl.validateParamsFn = pl.validateParams
 
 	dest := app.EnvironmentDestinationSpec{}
 	pl.lister = params.NewLister(pl.app.Root(), dest)
@@ -143,12 +157,18 @@ func (pl *ParamList) handleEnvParams() error {
 	}
 
 	var entries []params.Entry
+	var provenances []params.Provenance
+	var invalid []string
 	for _, m := range modules {
 		source, err := pl.envParametersFn(m.Name(), !pl.withoutModules)
 		if err != nil {
 			return err
 		}
 
+		if err = pl.validateParamsFn(m, source); err != nil {
+			invalid = append(invalid, err.Error())
+		}
+
 		r := strings.NewReader(source)
 
 		moduleEntries, err := pl.lister.List(r, pl.componentName)
@@ -157,7 +177,166 @@ func (pl *ParamList) handleEnvParams() error {
 		}
 
 		entries = append(entries, moduleEntries...)
+
+		if !pl.explain {
+			continue
+		}
+
+		config := params.ExplainConfig{
+			App:              pl.app,
+			EnvName:          pl.envName,
+			ModuleName:       m.Name(),
+			ModuleParamsPath: m.ParamsPath(),
+		}
+
+		moduleProvenances, err := pl.explainFn(config)
+		if err != nil {
+			return errors.Wrapf(err, "explaining parameters for module %q", m.Name())
+		}
+
+		provenances = append(provenances, moduleProvenances...)
 	}
 
-	return pl.print(entries)
+	s, err := pl.loadSecretsFn(pl.app, pl.envName)
+	if err != nil {
+		return err
+	}
+
+	entries = maskSecrets(entries, s, pl.componentName)
+
+	if pl.explain {
+		err = pl.printExplain(entries, provenances)
+	} else {
+		err = pl.print(entries)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Invalid params are reported after they are listed, so they can be
+	// inspected.
+	if len(invalid) > 0 {
+		return errors.New(strings.Join(invalid, "\n"))
+	}
+
+	return nil
+}
+
+// validateParams validates the params of a module in the environment against
+// the module's param schemas.
+func (pl *ParamList) validateParams(m component.Module, source string) error {
+	s, err := params.LoadSchemas(pl.app.Fs(), m.Dir())
+	if err != nil {
+		return err
+	}
+
+	if pl.componentName != "" {
+		s = params.Schemas{pl.componentName: s[pl.componentName]}
+	}
+
+	if err = s.ValidateJSON(m.Name(), source); err != nil {
+		return errors.Wrapf(err, "validating params for module %q in environment %q", m.Name(), pl.envName)
+	}
+
+	return nil
+}
+
+// printExplain prints each param's final value with the layers that define it.
+// The layer whose value wins is marked.
+func (pl *ParamList) printExplain(entries []params.Entry, provenances []params.Provenance) error {
+	t := table.New("paramExplain", pl.out)
+
+	f, err := table.DetectFormat(pl.outputType)
+	if err != nil {
+		return errors.Wrap(err, "detecting output format")
+	}
+	t.SetFormat(f)
+
+	t.SetHeader([]string{"component", "param", "value", "layer", "source", "layer-value", "winner"})
+
+	type paramKey struct {
+		component string
+		param     string
+	}
+
+	values := make(map[paramKey]string)
+	var keys []paramKey
+	for _, entry := range entries {
+		key := paramKey{component: entry.ComponentName, param: entry.ParamName}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = entry.Value
+	}
+
+	definitions := make(map[paramKey]params.Provenance)
+	for _, p := range provenances {
+		if pl.componentName != "" && p.ComponentName != pl.componentName {
+			continue
+		}
+
+		key := paramKey{component: p.ComponentName, param: p.ParamName}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+			values[key] = ""
+		}
+		definitions[key] = p
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].component != keys[j].component {
+			return keys[i].component < keys[j].component
+		}
+		return keys[i].param < keys[j].param
+	})
+
+	for _, key := range keys {
+		p, ok := definitions[key]
+		if !ok || len(p.Definitions) == 0 {
+			t.Append([]string{key.component, key.param, values[key], "", "", "", ""})
+			continue
+		}
+
+		winner := p.Winner()
+		for i := range p.Definitions {
+			d := &p.Definitions[i]
+
+			mark := ""
+			if d == winner {
+				mark = "*"
+			}
+
+			t.Append([]string{key.component, key.param, values[key], d.Layer, d.Source(), d.Value, mark})
+		}
+	}
+
+	return t.Render()
+}
+
+// maskSecrets masks the values of secret params. Secret params which are not
+// set in params.libsonnet are added to the end of the entries.
+func maskSecrets(entries []params.Entry, s *secrets.Secrets, componentName string) []params.Entry {
+	for _, secret := range s.Entries() {
+		if componentName != "" && secret.ComponentName != componentName {
+			continue
+		}
+
+		found := false
+		for i := range entries {
+			if entries[i].ComponentName == secret.ComponentName && entries[i].ParamName == secret.ParamName {
+				entries[i].Value = secrets.Mask
+				found = true
+			}
+		}
+
+		if !found {
+			entries = append(entries, params.Entry{
+				ComponentName: secret.ComponentName,
+				ParamName:     secret.ParamName,
+				Value:         secrets.Mask,
+			})
+		}
+	}
+
+	return entries
 }
@@ -28,6 +28,8 @@ import (
 	cmocks "github.com/ksonnet/ksonnet/pkg/component/mocks"
 	"github.com/ksonnet/ksonnet/pkg/params"
 	paramsTesting "github.com/ksonnet/ksonnet/pkg/params/testing"
+	"github.com/ksonnet/ksonnet/pkg/prototype"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -39,6 +41,7 @@ func TestParamList(t *testing.T) {
 	}
 
 	module := &cmocks.Module{}
+	module.On("Dir").Return("/components")
 
 	p := `{components:{deployment:{key:"value"}}}`
 	paramsFile := ioutil.NopCloser(strings.NewReader(p))
@@ -206,6 +209,174 @@ func TestParamList(t *testing.T) {
 	})
 }
 
+func TestParamList_env_secrets(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		module := &cmocks.Module{}
+		module.On("Name").Return("/")
+		module.On("Dir").Return("/components")
+
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionEnvName: "envName",
+		}
+
+		a, err := NewParamList(in)
+		require.NoError(t, err)
+
+		a.lister = &paramsTesting.FakeLister{
+			Entries: []params.Entry{
+				{ComponentName: "deployment", ParamName: "key", Value: `'value'`},
+				{ComponentName: "deployment", ParamName: "password", Value: `'changeme'`},
+			},
+		}
+		a.modulesFn = func() ([]component.Module, error) {
+			return []component.Module{module}, nil
+		}
+		a.envParametersFn = func(string, bool) (string, error) {
+			return "{}", nil
+		}
+		a.loadSecretsFn = func(a app.App, envName string) (*secrets.Secrets, error) {
+			assert.Equal(t, "envName", envName)
+			s := &secrets.Secrets{
+				Provider: secrets.DefaultProvider,
+				Components: map[string]map[string]string{
+					"deployment": {"password": "encrypted", "token": "encrypted"},
+				},
+			}
+			return s, nil
+		}
+
+		var buf bytes.Buffer
+		a.out = &buf
+
+		err = a.Run()
+		require.NoError(t, err)
+
+		assertOutput(t, filepath.Join("param", "list", "env_secrets.txt"), buf.String())
+	})
+}
+
+func TestParamList_env_invalid_params(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		module := &cmocks.Module{}
+		module.On("Name").Return("/")
+		module.On("Dir").Return("/components")
+
+		schemas := params.Schemas{
+			"deployment": params.ComponentSchema{
+				"replicas": {Type: prototype.Number},
+			},
+		}
+		require.NoError(t, params.SaveSchemas(appMock.Fs(), "/components", schemas))
+
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionEnvName: "envName",
+		}
+
+		a, err := NewParamList(in)
+		require.NoError(t, err)
+
+		a.lister = &paramsTesting.FakeLister{
+			Entries: []params.Entry{
+				{ComponentName: "deployment", ParamName: "key", Value: `'value'`},
+			},
+		}
+		a.modulesFn = func() ([]component.Module, error) {
+			return []component.Module{module}, nil
+		}
+		a.envParametersFn = func(string, bool) (string, error) {
+			return `{"components": {"deployment": {"key": "value", "replicas": "three"}}}`, nil
+		}
+		a.loadSecretsFn = func(a app.App, envName string) (*secrets.Secrets, error) {
+			return &secrets.Secrets{}, nil
+		}
+
+		var buf bytes.Buffer
+		a.out = &buf
+
+		err = a.Run()
+		require.Error(t, err)
+
+		expected := `validating params for module "/" in environment "envName": invalid params:
+  param "replicas" of component "deployment" must be a number, got string "three"`
+		assert.Equal(t, expected, err.Error())
+
+		assertOutput(t, filepath.Join("param", "list", "env.txt"), buf.String())
+	})
+}
+
+func TestParamList_explain(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		module := &cmocks.Module{}
+		module.On("Name").Return("/")
+		module.On("Dir").Return("/components")
+		module.On("ParamsPath").Return("/components/params.libsonnet")
+
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionEnvName: "envName",
+			OptionExplain: true,
+		}
+
+		a, err := NewParamList(in)
+		require.NoError(t, err)
+
+		a.lister = &paramsTesting.FakeLister{
+			Entries: []params.Entry{
+				{ComponentName: "deployment", ParamName: "name", Value: `'deployment'`},
+				{ComponentName: "deployment", ParamName: "replicas", Value: `3`},
+			},
+		}
+		a.modulesFn = func() ([]component.Module, error) {
+			return []component.Module{module}, nil
+		}
+		a.envParametersFn = func(string, bool) (string, error) {
+			return "{}", nil
+		}
+		a.loadSecretsFn = func(a app.App, envName string) (*secrets.Secrets, error) {
+			return &secrets.Secrets{}, nil
+		}
+		a.explainFn = func(config params.ExplainConfig) ([]params.Provenance, error) {
+			assert.Equal(t, "envName", config.EnvName)
+			assert.Equal(t, "/", config.ModuleName)
+			assert.Equal(t, "/components/params.libsonnet", config.ModuleParamsPath)
+
+			provenances := []params.Provenance{
+				{
+					ComponentName: "deployment",
+					ParamName:     "replicas",
+					Definitions: []params.Definition{
+						{Layer: params.LayerModule, Path: "components/params.libsonnet", Line: 4, Value: "1"},
+						{Layer: params.LayerEnv, Path: "environments/envName/params.libsonnet", Line: 7, Value: "3"},
+					},
+				},
+			}
+			return provenances, nil
+		}
+
+		var buf bytes.Buffer
+		a.out = &buf
+
+		err = a.Run()
+		require.NoError(t, err)
+
+		assertOutput(t, filepath.Join("param", "list", "explain.txt"), buf.String())
+	})
+}
+
+func TestParamList_explain_requires_env(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionExplain: true,
+		}
+
+		_, err := NewParamList(in)
+		require.Error(t, err)
+	})
+}
+
 func TestParamList_requires_app(t *testing.T) {
 	in := make(map[string]interface{})
 	_, err := NewParamList(in)
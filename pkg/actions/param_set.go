@@ -22,6 +22,7 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/component"
 	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
 	"github.com/ksonnet/ksonnet/pkg/util/dockerregistry"
 	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
 	"github.com/pkg/errors"
@@ -47,12 +48,15 @@ type ParamSet struct {
 	envName      string
 	asString     bool
 	resolveImage bool
-
-	getModuleFn    getModuleFn
-	resolvePathFn  func(a app.App, path string) (component.Module, component.Component, error)
-	setEnvFn       func(ksApp app.App, envName, name, pName, value string) error
-	setGlobalEnvFn func(ksApp app.App, envName, pName, value string) error
-	resolveImageFn func(image string) (string, error)
+	secret       bool
+
+	getModuleFn     getModuleFn
+	resolvePathFn   func(a app.App, path string) (component.Module, component.Component, error)
+	setEnvFn        func(ksApp app.App, envName, name, pName, value string) error
+	setGlobalEnvFn  func(ksApp app.App, envName, pName, value string) error
+	setSecretFn     func(ksApp app.App, envName, name, pName, value string) error
+	validateParamFn func(ksApp app.App, name string, path []string, value interface{}) error
+	resolveImageFn  func(image string) (string, error)
 }
 
 // NewParamSet creates an instance of ParamSet.
@@ -68,12 +72,15 @@ func NewParamSet(m map[string]interface{}) (*ParamSet, error) {
 		envName:      ol.LoadOptionalString(OptionEnvName),
 		asString:     ol.LoadOptionalBool(OptionAsString),
 		resolveImage: ol.LoadOptionalBool(OptionResolveImage),
+		secret:       ol.LoadOptionalBool(OptionSecret),
 
-		getModuleFn:    component.GetModule,
-		resolvePathFn:  component.ResolvePath,
-		setEnvFn:       setEnv,
-		setGlobalEnvFn: setGlobalEnv,
-		resolveImageFn: dockerregistry.ResolveImage,
+		getModuleFn:     component.GetModule,
+		resolvePathFn:   component.ResolvePath,
+		setEnvFn:        setEnv,
+		setGlobalEnvFn:  setGlobalEnv,
+		setSecretFn:     setSecret,
+		validateParamFn: component.ValidateParam,
+		resolveImageFn:  dockerregistry.ResolveImage,
 	}
 
 	if ol.err != nil {
@@ -84,6 +91,10 @@ func NewParamSet(m map[string]interface{}) (*ParamSet, error) {
 		return nil, errors.New("unable to set global param for environments")
 	}
 
+	if ps.secret && (ps.envName == "" || ps.name == "") {
+		return nil, errors.New("secret params can only be set for a component in an environment")
+	}
+
 	return ps, nil
 }
 
@@ -92,7 +103,8 @@ func (ps *ParamSet) Run() error {
 	var value interface{}
 	var err error
 
-	if ps.asString {
+	// Secrets are always strings.
+	if ps.asString || ps.secret {
 		value = ps.rawValue
 	} else {
 		value, err = jsonnet.DecodeValue(ps.rawValue)
@@ -102,20 +114,32 @@ func (ps *ParamSet) Run() error {
 	}
 
 	if ps.envName != "" {
-		value := ps.rawValue
+		envValue := ps.rawValue
 		if ps.resolveImage {
-			digest, err := ps.resolveImageFn(value)
+			digest, err := ps.resolveImageFn(envValue)
 			if err != nil {
 				return errors.Wrap(err, "resolving docker image reference")
 			}
 
+			envValue = digest
 			value = digest
 		}
 
 		if ps.name != "" {
-			return ps.setEnvFn(ps.app, ps.envName, ps.name, ps.rawPath, value)
+			path := strings.Split(ps.rawPath, ".")
+			if err := ps.validateParamFn(ps.app, ps.name, path, value); err != nil {
+				return err
+			}
+		}
+
+		if ps.secret {
+			return ps.setSecretFn(ps.app, ps.envName, ps.name, ps.rawPath, envValue)
+		}
+
+		if ps.name != "" {
+			return ps.setEnvFn(ps.app, ps.envName, ps.name, ps.rawPath, envValue)
 		}
-		return ps.setGlobalEnvFn(ps.app, ps.envName, ps.rawPath, value)
+		return ps.setGlobalEnvFn(ps.app, ps.envName, ps.rawPath, envValue)
 	}
 
 	path := strings.Split(ps.rawPath, ".")
@@ -191,3 +215,12 @@ func setGlobalEnv(ksApp app.App, envName, pName, value string) error {
 
 	return env.SetGlobalParams(ksApp, envName, p)
 }
+
+// setSecret encrypts a string param for a component in an environment.
+func setSecret(ksApp app.App, envName, name, pName, value string) error {
+	if _, err := ksApp.Environment(envName); err != nil {
+		return err
+	}
+
+	return secrets.Set(ksApp, envName, name, pName, value)
+}
@@ -22,6 +22,7 @@ import (
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/component"
 	cmocks "github.com/ksonnet/ksonnet/pkg/component/mocks"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -195,12 +196,105 @@ func TestParamSet_env(t *testing.T) {
 			return nil
 		}
 		a.setEnvFn = envSetter
+		a.validateParamFn = func(ksApp app.App, name string, path []string, value interface{}) error {
+			assert.Equal(t, "deployment", name)
+			assert.Equal(t, []string{"replicas"}, path)
+			assert.Equal(t, 3, value)
+			return nil
+		}
 
 		err = a.Run()
 		require.NoError(t, err)
 	})
 }
 
+func TestParamSet_env_invalid(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionName:    "deployment",
+			OptionPath:    "replicas",
+			OptionValue:   "three",
+			OptionEnvName: "default",
+		}
+
+		a, err := NewParamSet(in)
+		require.NoError(t, err)
+
+		a.setEnvFn = func(ksApp app.App, envName, name, pName, value string) error {
+			return errors.New("unexpected env param")
+		}
+		a.validateParamFn = func(ksApp app.App, name string, path []string, value interface{}) error {
+			return errors.New(`param "replicas" of component "deployment" must be a number, got string "three"`)
+		}
+
+		err = a.Run()
+		require.EqualError(t, err, `param "replicas" of component "deployment" must be a number, got string "three"`)
+	})
+}
+
+func TestParamSet_env_secret(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionName:    "deployment",
+			OptionPath:    "password",
+			OptionValue:   "[s3cret",
+			OptionEnvName: "default",
+			OptionSecret:  true,
+		}
+
+		a, err := NewParamSet(in)
+		require.NoError(t, err)
+
+		a.setEnvFn = func(ksApp app.App, envName, name, pName, value string) error {
+			return errors.New("unexpected env param")
+		}
+
+		a.validateParamFn = func(ksApp app.App, name string, path []string, value interface{}) error {
+			assert.Equal(t, "[s3cret", value)
+			return nil
+		}
+
+		a.setSecretFn = func(ksApp app.App, envName, name, pName, value string) error {
+			assert.Equal(t, "default", envName)
+			assert.Equal(t, "deployment", name)
+			assert.Equal(t, "password", pName)
+			assert.Equal(t, "[s3cret", value)
+			return nil
+		}
+
+		err = a.Run()
+		require.NoError(t, err)
+	})
+}
+
+func TestParamSet_secret_requires_env_component(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		cases := []map[string]interface{}{
+			{
+				OptionApp:    appMock,
+				OptionName:   "deployment",
+				OptionPath:   "password",
+				OptionValue:  "s3cret",
+				OptionSecret: true,
+			},
+			{
+				OptionApp:     appMock,
+				OptionPath:    "password",
+				OptionValue:   "s3cret",
+				OptionEnvName: "default",
+				OptionSecret:  true,
+			},
+		}
+
+		for _, in := range cases {
+			_, err := NewParamSet(in)
+			require.Error(t, err)
+		}
+	})
+}
+
 func TestParamSet_env_resolveImage(t *testing.T) {
 	withApp(t, func(appMock *amocks.App) {
 		name := "deployment"
@@ -230,6 +324,10 @@ func TestParamSet_env_resolveImage(t *testing.T) {
 		a.resolveImageFn = func(string) (string, error) {
 			return "foo/bar@sha256:abcde", nil
 		}
+		a.validateParamFn = func(ksApp app.App, name string, path []string, value interface{}) error {
+			assert.Equal(t, "foo/bar@sha256:abcde", value)
+			return nil
+		}
 
 		err = a.Run()
 		require.NoError(t, err)
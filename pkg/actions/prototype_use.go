@@ -23,6 +23,7 @@ import (
 	param "github.com/ksonnet/ksonnet/metadata/params"
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/pkg/params"
 	"github.com/ksonnet/ksonnet/pkg/prototype"
 	"github.com/ksonnet/ksonnet/pkg/registry"
 	"github.com/pkg/errors"
@@ -48,6 +49,7 @@ type PrototypeUse struct {
 	out                 io.Writer
 	packageManager      registry.PackageManager
 	createComponentFn   func(app.App, string, string, string, param.Params, prototype.TemplateType) (string, error)
+	writeParamSchemaFn  func(a app.App, moduleName, name string, cs params.ComponentSchema) error
 	bindFlagsFn         func(p *prototype.Prototype) (*pflag.FlagSet, error)
 	extractParametersFn func(fs afero.Fs, p *prototype.Prototype, f *pflag.FlagSet) (map[string]string, error)
 }
@@ -66,6 +68,7 @@ func NewPrototypeUse(m map[string]interface{}) (*PrototypeUse, error) {
 		out:                 os.Stdout,
 		packageManager:      registry.NewPackageManager(app, httpClientOpt),
 		createComponentFn:   component.Create,
+		writeParamSchemaFn:  component.WriteParamSchema,
 		bindFlagsFn:         prototype.BindFlags,
 		extractParametersFn: prototype.ExtractParameters,
 	}
@@ -179,5 +182,9 @@ func (pl *PrototypeUse) Run() error {
 		return errors.Wrap(err, "create component")
 	}
 
+	if err = pl.writeParamSchemaFn(pl.app, moduleName, prototypeName, params.SchemaFromPrototype(p)); err != nil {
+		return errors.Wrap(err, "write component param schema")
+	}
+
 	return nil
 }
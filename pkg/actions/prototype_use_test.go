@@ -21,6 +21,7 @@ import (
 	param "github.com/ksonnet/ksonnet/metadata/params"
 	"github.com/ksonnet/ksonnet/pkg/app"
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/params"
 	"github.com/ksonnet/ksonnet/pkg/prototype"
 	registrymocks "github.com/ksonnet/ksonnet/pkg/registry/mocks"
 	"github.com/pkg/errors"
@@ -73,6 +74,21 @@ func TestPrototypeUse(t *testing.T) {
 			return "", nil
 		}
 
+		a.writeParamSchemaFn = func(_ app.App, moduleName, name string, cs params.ComponentSchema) error {
+			assert.Equal(t, "", moduleName)
+			assert.Equal(t, "deployment", name)
+
+			expected := params.ComponentSchema{
+				"name":          {Type: prototype.String, Required: true, Description: "Name of the deployment"},
+				"image":         {Type: prototype.String, Required: true, Description: "Container image to deploy"},
+				"replicas":      {Type: prototype.Number, Description: "Number of replicas"},
+				"containerPort": {Type: prototype.Number, Description: "Port to expose"},
+			}
+			assert.Equal(t, expected, cs)
+
+			return nil
+		}
+
 		err = a.Run()
 		require.NoError(t, err)
 	})
@@ -0,0 +1,93 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+)
+
+type runPruneFn func(cluster.PruneConfig, ...cluster.PruneOpts) error
+
+// RunPrune runs `prune`.
+func RunPrune(m map[string]interface{}) error {
+	p, err := newPrune(m)
+	if err != nil {
+		return err
+	}
+
+	return p.run()
+}
+
+type pruneOpt func(*Prune)
+
+// Prune collects options for pruning objects from a cluster.
+type Prune struct {
+	app          app.App
+	clientConfig *client.Config
+	dryRun       bool
+	envName      string
+	gcTag        string
+	skipConfirm  bool
+
+	runPruneFn runPruneFn
+}
+
+func newPrune(m map[string]interface{}, opts ...pruneOpt) (*Prune, error) {
+	ol := newOptionLoader(m)
+
+	p := &Prune{
+		app:          ol.LoadApp(),
+		clientConfig: ol.LoadClientConfig(),
+		dryRun:       ol.LoadBool(OptionDryRun),
+		gcTag:        ol.LoadString(OptionGcTag),
+		skipConfirm:  ol.LoadBool(OptionSkipConfirm),
+
+		runPruneFn: cluster.RunPrune,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if err := setCurrentEnv(p.app, p, ol); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (p *Prune) run() error {
+	config := cluster.PruneConfig{
+		App:          p.app,
+		ClientConfig: p.clientConfig,
+		DryRun:       p.dryRun,
+		EnvName:      p.envName,
+		GcTag:        p.gcTag,
+		SkipConfirm:  p.skipConfirm,
+	}
+
+	return p.runPruneFn(config)
+}
+
+func (p *Prune) setCurrentEnv(name string) {
+	p.envName = name
+}
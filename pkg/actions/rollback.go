@@ -0,0 +1,106 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"io"
+	"os"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+)
+
+type runRollbackFn func(cluster.RollbackConfig, ...cluster.RollbackOpts) (*cluster.ApplyResult, error)
+
+// RunRollback runs `rollback`.
+func RunRollback(m map[string]interface{}) error {
+	r, err := newRollback(m)
+	if err != nil {
+		return err
+	}
+
+	return r.run()
+}
+
+type rollbackOpt func(*Rollback)
+
+// Rollback collects options for rolling back an environment to a previous revision.
+type Rollback struct {
+	app          app.App
+	clientConfig *client.Config
+	envName      string
+	outputType   string
+	revision     int
+
+	runRollbackFn runRollbackFn
+	out           io.Writer
+}
+
+func newRollback(m map[string]interface{}, opts ...rollbackOpt) (*Rollback, error) {
+	ol := newOptionLoader(m)
+
+	r := &Rollback{
+		app:          ol.LoadApp(),
+		clientConfig: ol.LoadClientConfig(),
+		outputType:   ol.LoadOptionalString(OptionOutput),
+		revision:     ol.LoadInt(OptionRevision),
+
+		runRollbackFn: cluster.RunRollback,
+		out:           os.Stdout,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if err := setCurrentEnv(r.app, r, ol); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *Rollback) run() error {
+	f, err := table.DetectFormat(r.outputType)
+	if err != nil {
+		return errors.Wrap(err, "detecting output format")
+	}
+
+	config := cluster.RollbackConfig{
+		App:          r.app,
+		ClientConfig: r.clientConfig,
+		EnvName:      r.envName,
+		Revision:     r.revision,
+	}
+
+	result, err := r.runRollbackFn(config)
+	if err != nil {
+		return err
+	}
+
+	return printApplyResult(r.out, result, f)
+}
+
+func (r *Rollback) setCurrentEnv(name string) {
+	r.envName = name
+}
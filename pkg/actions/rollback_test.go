@@ -0,0 +1,94 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestRollback(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:          appMock,
+			OptionClientConfig: &client.Config{},
+			OptionEnvName:      "default",
+			OptionRevision:     3,
+		}
+
+		expected := cluster.RollbackConfig{
+			App:          appMock,
+			ClientConfig: &client.Config{},
+			EnvName:      "default",
+			Revision:     3,
+		}
+
+		var buf bytes.Buffer
+
+		runRollbackOpt := func(r *Rollback) {
+			r.out = &buf
+			r.runRollbackFn = func(config cluster.RollbackConfig, opts ...cluster.RollbackOpts) (*cluster.ApplyResult, error) {
+				assert.Equal(t, expected, config)
+
+				return &cluster.ApplyResult{
+					Objects: []cluster.ObjectResult{
+						{
+							GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+							Namespace:        "default",
+							Name:             "removed",
+							Action:           cluster.ApplyActionPruned,
+							Duration:         5 * time.Millisecond,
+						},
+					},
+				}, nil
+			}
+		}
+
+		r, err := newRollback(in, runRollbackOpt)
+		require.NoError(t, err)
+
+		err = r.run()
+		require.NoError(t, err)
+
+		expectedOutput := `APIVERSION KIND      NAMESPACE NAME    ACTION UID DURATION
+========== ====      ========= ====    ====== === ========
+v1         ConfigMap default   removed pruned     5ms
+`
+		require.Equal(t, expectedOutput, buf.String())
+	})
+}
+
+func TestRollback_invalid_input(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:          appMock,
+			OptionClientConfig: &client.Config{},
+			OptionEnvName:      "default",
+			OptionRevision:     "3",
+		}
+
+		_, err := newRollback(in)
+		require.Error(t, err)
+	})
+}
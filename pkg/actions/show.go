@@ -45,6 +45,7 @@ type Show struct {
 	componentNames []string
 	envName        string
 	format         string
+	outputDir      string
 
 	out       io.Writer
 	runShowFn runShowFn
@@ -58,6 +59,7 @@ func newShow(m map[string]interface{}, opts ...showOpt) (*Show, error) {
 		app:            ol.LoadApp(),
 		componentNames: ol.LoadStringSlice(OptionComponentNames),
 		format:         ol.LoadString(OptionFormat),
+		outputDir:      ol.LoadOptionalString(OptionOutputDir),
 
 		out:       os.Stdout,
 		runShowFn: cluster.RunShow,
@@ -84,6 +86,7 @@ func (s *Show) run() error {
 		ComponentNames: s.componentNames,
 		EnvName:        s.envName,
 		Format:         s.format,
+		OutputDir:      s.outputDir,
 		Out:            s.out,
 	}
 
@@ -55,14 +55,16 @@ func TestShow(t *testing.T) {
 					OptionApp:            appMock,
 					OptionComponentNames: []string{},
 					OptionEnvName:        tc.envName,
-					OptionFormat:         "yaml",
+					OptionFormat:         "dir",
+					OptionOutputDir:      "/out",
 				}
 
 				expected := cluster.ShowConfig{
 					App:            appMock,
 					ComponentNames: []string{},
 					EnvName:        "default",
-					Format:         "yaml",
+					Format:         "dir",
+					OutputDir:      "/out",
 					Out:            os.Stdout,
 				}
 
@@ -0,0 +1,212 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+const (
+	// defaultStatusPollInterval is how often the cluster is checked when
+	// watching the status of an environment.
+	defaultStatusPollInterval = 2 * time.Second
+)
+
+// statusFetcher fetches the status of an environment's objects.
+type statusFetcher interface {
+	Status() (*cluster.StatusResult, error)
+}
+
+type newStatusFn func(cluster.StatusConfig) (statusFetcher, error)
+
+func newClusterStatus(config cluster.StatusConfig) (statusFetcher, error) {
+	return cluster.NewStatus(config)
+}
+
+// RunStatus runs `status`.
+func RunStatus(m map[string]interface{}) error {
+	s, err := newStatus(m)
+	if err != nil {
+		return err
+	}
+
+	return s.run()
+}
+
+type statusOpt func(*Status)
+
+// Status collects options for showing the status of an environment's objects.
+type Status struct {
+	app            app.App
+	clientConfig   *client.Config
+	componentNames []string
+	envName        string
+	outputType     string
+	watch          bool
+
+	newStatusFn  newStatusFn
+	nowFn        func() time.Time
+	pollInterval time.Duration
+	// stop ends watching when it is closed.
+	stop <-chan struct{}
+	out  io.Writer
+}
+
+func newStatus(m map[string]interface{}, opts ...statusOpt) (*Status, error) {
+	ol := newOptionLoader(m)
+
+	s := &Status{
+		app:            ol.LoadApp(),
+		clientConfig:   ol.LoadClientConfig(),
+		componentNames: ol.LoadStringSlice(OptionComponentNames),
+		outputType:     ol.LoadOptionalString(OptionOutput),
+		watch:          ol.LoadOptionalBool(OptionWatch),
+
+		newStatusFn:  newClusterStatus,
+		nowFn:        time.Now,
+		pollInterval: defaultStatusPollInterval,
+		out:          os.Stdout,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := setCurrentEnv(s.app, s, ol); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Status) run() error {
+	f, err := table.DetectFormat(s.outputType)
+	if err != nil {
+		return errors.Wrap(err, "detecting output format")
+	}
+
+	config := cluster.StatusConfig{
+		App:            s.app,
+		ClientConfig:   s.clientConfig,
+		ComponentNames: s.componentNames,
+		EnvName:        s.envName,
+	}
+
+	// The environment is only rendered once. Watching re-fetches the live
+	// state of its objects.
+	fetcher, err := s.newStatusFn(config)
+	if err != nil {
+		return err
+	}
+
+	var (
+		last    string
+		printed bool
+	)
+
+	for {
+		result, err := fetcher.Status()
+		if err != nil {
+			if !s.watch {
+				return err
+			}
+
+			// The cluster can be briefly unavailable, so a failed poll
+			// doesn't end the watch.
+			log.Warnf("Unable to fetch status of environment %q: %v", s.envName, err)
+		} else if key := statusKey(result); !printed || key != last {
+			// When watching, the status is only printed again after it changes.
+			if printed && f == table.FormatTable {
+				fmt.Fprintln(s.out)
+			}
+
+			if err = s.print(result, f); err != nil {
+				return err
+			}
+
+			last = key
+			printed = true
+		}
+
+		if !s.watch {
+			return nil
+		}
+
+		select {
+		case <-s.stop:
+			return nil
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+func (s *Status) print(result *cluster.StatusResult, f table.Format) error {
+	t := table.New("status", s.out)
+	t.SetFormat(f)
+	t.SetHeader([]string{"component", "kind", "name", "exists", "ready", "age", "status"})
+
+	for _, obj := range result.Objects {
+		age := ""
+		if obj.Exists {
+			age = duration.ShortHumanDuration(s.nowFn().Sub(obj.Created))
+		}
+
+		t.Append([]string{
+			obj.Component,
+			obj.GroupVersionKind.Kind,
+			obj.Name,
+			strconv.FormatBool(obj.Exists),
+			strconv.FormatBool(obj.Ready),
+			age,
+			obj.Message,
+		})
+	}
+
+	return t.Render()
+}
+
+// statusKey summarizes a status result without the age of the objects, so
+// changes to the status can be detected.
+func statusKey(result *cluster.StatusResult) string {
+	var parts []string
+	for _, obj := range result.Objects {
+		parts = append(parts, fmt.Sprintf("%s/%s/%s/%s/%t/%t/%s",
+			obj.Component, obj.GroupVersionKind, obj.Namespace, obj.Name, obj.Exists, obj.Ready, obj.Message))
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+func (s *Status) setCurrentEnv(name string) {
+	s.envName = name
+}
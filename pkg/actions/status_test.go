@@ -0,0 +1,174 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestStatus(t *testing.T) {
+	now := time.Date(2018, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	genResult := func(ready bool, message string) *cluster.StatusResult {
+		return &cluster.StatusResult{
+			Objects: []cluster.ObjectStatus{
+				{
+					Component:        "guestbook",
+					GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+					Namespace:        "default",
+					Name:             "guestbook-ui",
+					Exists:           true,
+					Ready:            ready,
+					Message:          message,
+					Created:          now.Add(-90 * time.Second),
+				},
+				{
+					Component:        "guestbook",
+					GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Service"},
+					Namespace:        "default",
+					Name:             "guestbook-ui",
+					Message:          "object does not exist",
+				},
+			},
+		}
+	}
+
+	// A nil result is a failed poll.
+	cases := []struct {
+		name     string
+		watch    bool
+		results  []*cluster.StatusResult
+		expected string
+		isErr    bool
+	}{
+		{
+			name:     "status",
+			results:  []*cluster.StatusResult{genResult(true, "rolled out")},
+			expected: "status/output.txt",
+		},
+		{
+			name:  "watch",
+			watch: true,
+			results: []*cluster.StatusResult{
+				genResult(false, "1 of 2 replicas updated"),
+				genResult(false, "1 of 2 replicas updated"),
+				genResult(true, "rolled out"),
+			},
+			expected: "status/watch.txt",
+		},
+		{
+			name:    "failed poll",
+			results: []*cluster.StatusResult{nil},
+			isErr:   true,
+		},
+		{
+			name:  "watch continues after a failed poll",
+			watch: true,
+			results: []*cluster.StatusResult{
+				genResult(false, "1 of 2 replicas updated"),
+				nil,
+				genResult(true, "rolled out"),
+			},
+			expected: "status/watch.txt",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:            appMock,
+					OptionClientConfig:   &client.Config{},
+					OptionComponentNames: []string{"guestbook"},
+					OptionEnvName:        "default",
+					OptionWatch:          tc.watch,
+				}
+
+				expected := cluster.StatusConfig{
+					App:            appMock,
+					ClientConfig:   &client.Config{},
+					ComponentNames: []string{"guestbook"},
+					EnvName:        "default",
+				}
+
+				var buf bytes.Buffer
+				stop := make(chan struct{})
+				created, calls := 0, 0
+
+				fetcher := &fakeStatusFetcher{
+					statusFn: func() (*cluster.StatusResult, error) {
+						result := tc.results[calls]
+						calls++
+						if calls == len(tc.results) {
+							close(stop)
+						}
+
+						if result == nil {
+							return nil, errors.New("unavailable")
+						}
+						return result, nil
+					},
+				}
+
+				statusOpt := func(s *Status) {
+					s.out = &buf
+					s.nowFn = func() time.Time { return now }
+					s.pollInterval = time.Millisecond
+					s.stop = stop
+					s.newStatusFn = func(config cluster.StatusConfig) (statusFetcher, error) {
+						assert.Equal(t, expected, config)
+						created++
+						return fetcher, nil
+					}
+				}
+
+				s, err := newStatus(in, statusOpt)
+				require.NoError(t, err)
+
+				err = s.run()
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				require.Equal(t, 1, created, "environment is only rendered once")
+				require.Equal(t, len(tc.results), calls)
+				assertOutput(t, tc.expected, buf.String())
+			})
+		})
+	}
+}
+
+type fakeStatusFetcher struct {
+	statusFn func() (*cluster.StatusResult, error)
+}
+
+var _ statusFetcher = (*fakeStatusFetcher)(nil)
+
+func (f *fakeStatusFetcher) Status() (*cluster.StatusResult, error) {
+	return f.statusFn()
+}
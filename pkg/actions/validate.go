@@ -19,15 +19,20 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
 	"github.com/ksonnet/ksonnet/pkg/openapi"
 	"github.com/ksonnet/ksonnet/pkg/pipeline"
+	"github.com/ksonnet/ksonnet/pkg/policy"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
 	"github.com/ksonnet/ksonnet/utils"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 )
 
@@ -43,10 +48,21 @@ func RunValidate(m map[string]interface{}) error {
 
 type discoveryFn func(a app.App, clientConfig *client.Config, envName string) (discovery.DiscoveryInterface, error)
 
-type validateObjectFn func(
-	a app.App,
-	obj *unstructured.Unstructured,
-	envName string) []error
+// objectValidator validates objects.
+type objectValidator interface {
+	Validate(obj *unstructured.Unstructured) []error
+	AddCRD(crd *unstructured.Unstructured) error
+	HasCRD(gvk schema.GroupVersionKind) bool
+}
+
+type newValidatorFn func(a app.App, envName, schemaDir string) (objectValidator, error)
+
+// policyChecker checks objects against policy rules.
+type policyChecker interface {
+	Check(obj *unstructured.Unstructured, env *policy.Environment) ([]policy.Violation, error)
+}
+
+type newPolicyFn func(a app.App) (policyChecker, error)
 
 type findObjectsFn func(a app.App, envName string,
 	componentNames []string) ([]*unstructured.Unstructured, error)
@@ -58,11 +74,14 @@ type Validate struct {
 	module         string
 	componentNames []string
 	clientConfig   *client.Config
+	schemaDir      string
+	useDiscovery   bool
 	out            io.Writer
 
-	discoveryFn      discoveryFn
-	validateObjectFn validateObjectFn
-	findObjectsFn    findObjectsFn
+	discoveryFn    discoveryFn
+	newValidatorFn newValidatorFn
+	newPolicyFn    newPolicyFn
+	findObjectsFn  findObjectsFn
 }
 
 // NewValidate creates an instance of Validate.
@@ -75,11 +94,14 @@ func NewValidate(m map[string]interface{}) (*Validate, error) {
 		module:         ol.LoadString(OptionModule),
 		componentNames: ol.LoadStringSlice(OptionComponentNames),
 		clientConfig:   ol.LoadClientConfig(),
+		schemaDir:      ol.LoadOptionalString(OptionSchemaDir),
+		useDiscovery:   ol.LoadOptionalBool(OptionDiscovery),
 
-		out:              os.Stdout,
-		discoveryFn:      loadDiscovery,
-		validateObjectFn: openapi.ValidateAgainstSchema,
-		findObjectsFn:    findObjects,
+		out:            os.Stdout,
+		discoveryFn:    loadDiscovery,
+		newValidatorFn: newValidator,
+		newPolicyFn:    newPolicy,
+		findObjectsFn:  findObjects,
 	}
 
 	if ol.err != nil {
@@ -93,28 +115,98 @@ func NewValidate(m map[string]interface{}) (*Validate, error) {
 	return v, nil
 }
 
-// Run lists namespaces.
+// Run validates the objects rendered by an environment. Objects are validated
+// against the schemas recorded in the app, so a cluster is only contacted if
+// discovery is requested. Custom resources are validated against the schemas of
+// the CustomResourceDefinitions rendered with them. Objects are then checked
+// against the policy rules configured for the app. Validation fails if an object
+// is invalid or violates a rule with error severity. An environment with several
+// destinations is validated once for each destination.
 func (v *Validate) Run() error {
-	objects, err := v.findObjectsFn(v.app, v.envName, v.componentNames)
+	return runDestinations(v.app, v.envName, v.clientConfig, v.out, table.DefaultFormat, v.validate)
+}
+
+// validate validates the objects rendered for one destination of an environment.
+func (v *Validate) validate(envName string, clientConfig *client.Config) error {
+	objects, err := v.findObjectsFn(v.app, envName, v.componentNames)
+	if err != nil {
+		return err
+	}
+
+	var disc discovery.DiscoveryInterface
+	if v.useDiscovery {
+		disc, err = v.discoveryFn(v.app, clientConfig, envName)
+		if err != nil {
+			return err
+		}
+	}
+
+	validator, err := v.newValidatorFn(v.app, envName, v.schemaDir)
+	if err != nil {
+		return err
+	}
+
+	checker, err := v.newPolicyFn(v.app)
 	if err != nil {
 		return err
 	}
 
-	disc, err := v.discoveryFn(v.app, v.clientConfig, v.envName)
+	env, err := v.policyEnvironment(envName)
 	if err != nil {
 		return err
 	}
 
+	for _, obj := range objects {
+		if !openapi.IsCRD(obj) {
+			continue
+		}
+
+		if err = validator.AddCRD(obj); err != nil {
+			return err
+		}
+	}
+
 	var hasError bool
 
+	var rows [][]string
+
 	for _, obj := range objects {
-		desc := fmt.Sprintf("%s %s", utils.ResourceNameFor(disc, obj), utils.FqName(obj))
+		desc := v.describe(disc, obj)
 		log.Info("Validating ", desc)
 
-		errs := v.validateObjectFn(v.app, obj, v.envName)
-		for _, err := range errs {
-			log.Errorf("Error in %s: %v", desc, err)
-			hasError = true
+		if openapi.IsCustomResource(obj) && !validator.HasCRD(obj.GroupVersionKind()) {
+			if !served(disc, obj.GroupVersionKind()) {
+				log.Warnf("%s was not validated: its CustomResourceDefinition was not rendered or found in the cluster", desc)
+			}
+		} else {
+			errs := validator.Validate(obj)
+			for _, err := range errs {
+				log.Errorf("Error in %s: %v", desc, err)
+				hasError = true
+			}
+		}
+
+		violations, err := checker.Check(obj, env)
+		if err != nil {
+			return errors.Wrapf(err, "checking %s", desc)
+		}
+
+		for _, violation := range violations {
+			if violation.Severity == policy.SeverityError {
+				hasError = true
+			}
+
+			component := obj.GetLabels()[metadata.LabelComponent]
+			rows = append(rows, []string{component, desc, string(violation.Severity), violation.Rule, violation.Message})
+		}
+	}
+
+	if len(rows) > 0 {
+		t := table.New("validate", v.out)
+		t.SetHeader([]string{"component", "object", "severity", "rule", "message"})
+		t.AppendBulk(rows)
+		if err = t.Render(); err != nil {
+			return err
 		}
 	}
 
@@ -125,6 +217,63 @@ func (v *Validate) Run() error {
 	return nil
 }
 
+// policyEnvironment describes the environment for policy rules.
+func (v *Validate) policyEnvironment(envName string) (*policy.Environment, error) {
+	env := &policy.Environment{Name: envName}
+
+	envConfig, err := app.ResolveEnvironment(v.app, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	if envConfig.Destination != nil {
+		env.Namespace = envConfig.Destination.Namespace
+		env.Server = envConfig.Destination.Server
+	}
+
+	return env, nil
+}
+
+// describe describes an object. Resource names are looked up with discovery if it
+// is available.
+func (v *Validate) describe(disc discovery.DiscoveryInterface, obj *unstructured.Unstructured) string {
+	name := strings.ToLower(obj.GetKind())
+	if disc != nil {
+		name = utils.ResourceNameFor(disc, obj)
+	}
+
+	return fmt.Sprintf("%s %s", name, utils.FqName(obj))
+}
+
+// served returns true if a cluster serves a kind. It returns false if discovery
+// is not available.
+func served(disc discovery.DiscoveryInterface, gvk schema.GroupVersionKind) bool {
+	if disc == nil {
+		return false
+	}
+
+	resources, err := disc.ServerResourcesForGroupVersion(gvk.GroupVersion().String())
+	if err != nil {
+		return false
+	}
+
+	for _, resource := range resources.APIResources {
+		if resource.Kind == gvk.Kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newValidator(a app.App, envName, schemaDir string) (objectValidator, error) {
+	return openapi.NewValidator(a, envName, schemaDir)
+}
+
+func newPolicy(a app.App) (policyChecker, error) {
+	return policy.New(a)
+}
+
 func loadDiscovery(a app.App, clientConfig *client.Config, envName string) (discovery.DiscoveryInterface, error) {
 	_, d, _, err := clientConfig.RestClient(a, &envName)
 	return d, err
@@ -16,6 +16,7 @@
 package actions
 
 import (
+	"bytes"
 	"testing"
 
 	swagger "github.com/emicklei/go-restful-swagger12"
@@ -23,6 +24,9 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/app"
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/policy"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -36,15 +40,48 @@ import (
 
 func TestValidate(t *testing.T) {
 	cases := []struct {
-		name        string
-		isSetupErr  bool
-		currentName string
-		envName     string
+		name         string
+		isSetupErr   bool
+		currentName  string
+		envName      string
+		useDiscovery bool
+		errs         []error
+		violations   []policy.Violation
+		output       string
+		isErr        bool
 	}{
 		{
 			name:    "with a supplied env",
 			envName: "default",
 		},
+		{
+			name:         "with discovery",
+			envName:      "default",
+			useDiscovery: true,
+		},
+		{
+			name:    "with validation errors",
+			envName: "default",
+			errs:    []error{errors.New("invalid")},
+			isErr:   true,
+		},
+		{
+			name:    "with policy warnings",
+			envName: "default",
+			violations: []policy.Violation{
+				{Rule: "no-latest-tag", Severity: policy.SeverityWarning, Message: "uses the latest tag"},
+			},
+			output: "validate/warnings.txt",
+		},
+		{
+			name:    "with policy errors",
+			envName: "default",
+			violations: []policy.Violation{
+				{Rule: "resource-limits", Severity: policy.SeverityError, Message: "has no cpu limit"},
+			},
+			output: "validate/errors.txt",
+			isErr:  true,
+		},
 		{
 			name:        "with a current env",
 			currentName: "default",
@@ -73,6 +110,8 @@ func TestValidate(t *testing.T) {
 					OptionModule:         aModuleName,
 					OptionComponentNames: aComponentNames,
 					OptionClientConfig:   aClientConfig,
+					OptionDiscovery:      tc.useDiscovery,
+					OptionSchemaDir:      "/schemas",
 				}
 
 				a, err := NewValidate(in)
@@ -83,13 +122,18 @@ func TestValidate(t *testing.T) {
 				require.NoError(t, err)
 
 				a.discoveryFn = func(a app.App, clientConfig *client.Config, envName string) (discovery.DiscoveryInterface, error) {
+					require.True(t, tc.useDiscovery, "discovery was not requested")
 					assert.Equal(t, "default", envName)
 					return &stubDiscovery{}, nil
 				}
 
-				objects := []*unstructured.Unstructured{
-					{},
-				}
+				obj := &unstructured.Unstructured{}
+				obj.SetKind("Deployment")
+				obj.SetName("web")
+				obj.SetNamespace("default")
+				obj.SetLabels(map[string]string{metadata.LabelComponent: "web"})
+
+				objects := []*unstructured.Unstructured{obj}
 				a.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
 					assert.Equal(t, "default", envName)
 					assert.Equal(t, aComponentNames, componentNames)
@@ -97,24 +141,158 @@ func TestValidate(t *testing.T) {
 					return objects, nil
 				}
 
-				a.validateObjectFn = func(a app.App, obj *unstructured.Unstructured, envName string) []error {
-					return make([]error, 0)
+				a.newValidatorFn = func(a app.App, envName, schemaDir string) (objectValidator, error) {
+					assert.Equal(t, "default", envName)
+					assert.Equal(t, "/schemas", schemaDir)
+					return &fakeValidator{errs: tc.errs}, nil
+				}
+
+				a.newPolicyFn = func(a app.App) (policyChecker, error) {
+					return &fakePolicy{violations: tc.violations}, nil
 				}
 
+				var buf bytes.Buffer
+				a.out = &buf
+
 				err = a.Run()
+				if tc.output != "" {
+					test.AssertOutput(t, tc.output, buf.String())
+				} else {
+					assert.Empty(t, buf.String())
+				}
+
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
 				require.NoError(t, err)
 			})
 		})
 	}
 }
 
+func TestValidate_custom_resources(t *testing.T) {
+	crd := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1beta1",
+			"kind":       "CustomResourceDefinition",
+			"metadata":   map[string]interface{}{"name": "crontabs.stable.example.com"},
+			"spec": map[string]interface{}{
+				"group":   "stable.example.com",
+				"version": "v1",
+				"names":   map[string]interface{}{"kind": "CronTab"},
+			},
+		},
+	}
+
+	genCR := func(apiVersion, kind, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": apiVersion,
+				"kind":       kind,
+				"metadata":   map[string]interface{}{"name": name},
+			},
+		}
+	}
+
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:            appMock,
+			OptionEnvName:        "default",
+			OptionModule:         "module",
+			OptionComponentNames: []string{},
+			OptionClientConfig:   &client.Config{},
+		}
+
+		a, err := NewValidate(in)
+		require.NoError(t, err)
+
+		a.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+			return []*unstructured.Unstructured{
+				genCR("stable.example.com/v1", "CronTab", "rendered"),
+				genCR("other.example.com/v1", "Widget", "missing"),
+				crd,
+			}, nil
+		}
+
+		appMock.On("Environment", "default").Return(&app.EnvironmentConfig{}, nil)
+
+		a.newPolicyFn = func(a app.App) (policyChecker, error) {
+			return &fakePolicy{}, nil
+		}
+
+		validator := &fakeValidator{}
+		a.newValidatorFn = func(a app.App, envName, schemaDir string) (objectValidator, error) {
+			return validator, nil
+		}
+
+		require.NoError(t, a.Run())
+		assert.Equal(t, []string{"rendered", "crontabs.stable.example.com"}, validator.validated)
+	})
+}
+
+func Test_served(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "stable.example.com", Version: "v1", Kind: "CronTab"}
+
+	assert.False(t, served(nil, gvk))
+	assert.False(t, served(&stubDiscovery{}, gvk))
+
+	disc := &stubDiscovery{
+		resources: map[string]*metav1.APIResourceList{
+			"stable.example.com/v1": {
+				APIResources: []metav1.APIResource{{Kind: "CronTab"}},
+			},
+		},
+	}
+	assert.True(t, served(disc, gvk))
+	assert.False(t, served(disc, gvk.GroupVersion().WithKind("Other")))
+}
+
 func TestValidate_requires_app(t *testing.T) {
 	in := make(map[string]interface{})
 	_, err := NewValidate(in)
 	require.Error(t, err)
 }
 
-type stubDiscovery struct{}
+type fakeValidator struct {
+	errs      []error
+	crds      map[schema.GroupVersionKind]bool
+	validated []string
+}
+
+func (v *fakeValidator) Validate(obj *unstructured.Unstructured) []error {
+	v.validated = append(v.validated, obj.GetName())
+	return v.errs
+}
+
+func (v *fakeValidator) AddCRD(crd *unstructured.Unstructured) error {
+	if v.crds == nil {
+		v.crds = make(map[schema.GroupVersionKind]bool)
+	}
+
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	version, _, _ := unstructured.NestedString(crd.Object, "spec", "version")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	v.crds[schema.GroupVersionKind{Group: group, Version: version, Kind: kind}] = true
+
+	return nil
+}
+
+func (v *fakeValidator) HasCRD(gvk schema.GroupVersionKind) bool {
+	return v.crds[gvk]
+}
+
+type fakePolicy struct {
+	violations []policy.Violation
+}
+
+func (p *fakePolicy) Check(obj *unstructured.Unstructured, env *policy.Environment) ([]policy.Violation, error) {
+	return p.violations, nil
+}
+
+type stubDiscovery struct {
+	resources map[string]*metav1.APIResourceList
+}
 
 func (d *stubDiscovery) RESTClient() restclient.Interface {
 	return nil
@@ -125,7 +303,11 @@ func (d *stubDiscovery) ServerGroups() (*metav1.APIGroupList, error) {
 }
 
 func (d *stubDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
-	return nil, errors.New("not implemented")
+	if list, ok := d.resources[groupVersion]; ok {
+		return list, nil
+	}
+
+	return nil, errors.New("not found")
 }
 
 func (d *stubDiscovery) ServerResources() ([]*metav1.APIResourceList, error) {
@@ -95,6 +95,8 @@ type App interface {
 	UpdateRegistry(spec *RegistryConfig) error
 	// Upgrade upgrades an application to the current version.
 	Upgrade(dryRun bool) error
+	// Validation returns the validation configuration.
+	Validation() (*ValidationConfig, error)
 
 	// VendorPath returns the root of the vendor path.
 	VendorPath() string
@@ -139,6 +141,43 @@ func Load(fs afero.Fs, httpClient *http.Client, cwd string, skipFindRoot bool) (
 	}
 }
 
+// ResolveEnvironment returns the spec used to render or deploy an environment.
+// A name such as `prod@us-east` selects one of the environment's destinations:
+// the spec only targets that destination and carries its parameter overrides.
+// Without a selected destination, an environment with a list of destinations
+// is rendered against its first destination, without that destination's
+// parameter overrides. Commands which change the environment should use
+// Environment instead.
+func ResolveEnvironment(a App, name string) (*EnvironmentConfig, error) {
+	envName, destName := SplitDestination(name)
+
+	env, err := a.Environment(envName)
+	if err != nil {
+		return nil, err
+	}
+
+	e := *env
+	if destName != "" {
+		d, ok := e.destination(destName)
+		if !ok {
+			return nil, errors.Errorf("environment %q does not have a destination named %q", envName, destName)
+		}
+
+		e.Name = name
+		e.Destination = d
+		e.Destinations = nil
+		return &e, nil
+	}
+
+	if e.Destination == nil && len(e.Destinations) > 0 {
+		d := *e.Destinations[0]
+		d.Params = nil
+		e.Destination = &d
+	}
+
+	return &e, nil
+}
+
 func app010LibPath(root string) string {
 	return filepath.Join(root, LibDirName)
 }
@@ -84,6 +84,10 @@ func (a *App010) AddEnvironment(newEnv *EnvironmentConfig, k8sSpecFlag string, i
 		return errors.Errorf("invalid environment name")
 	}
 
+	if strings.Contains(newEnv.Name, DestinationSeparator) {
+		return errors.Errorf("environment name %q can not contain %q", newEnv.Name, DestinationSeparator)
+	}
+
 	if isOverride && len(newEnv.Libraries) > 0 {
 		return errors.Errorf("library references not allowed in overrides")
 	}
@@ -158,7 +162,7 @@ func (a *App010) LibPath(envName string) (string, error) {
 		return lp, nil
 	}
 
-	env, err := a.Environment(envName)
+	env, err := ResolveEnvironment(a, envName)
 	if err != nil {
 		return "", err
 	}
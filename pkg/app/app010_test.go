@@ -77,6 +77,21 @@ func TestApp010_AddEnvironment(t *testing.T) {
 	})
 }
 
+func TestApp010_AddEnvironment_destination_name(t *testing.T) {
+	withApp010Fs(t, "app010_app.yaml", func(app *App010) {
+		newEnv := &EnvironmentConfig{
+			Name: "prod@us-east",
+			Path: "prod@us-east",
+		}
+
+		err := app.AddEnvironment(newEnv, "", false)
+		require.Error(t, err)
+
+		_, err = app.Environment("prod")
+		require.Error(t, err)
+	})
+}
+
 func TestApp010_AddEnvironment_empty_spec_flag(t *testing.T) {
 	withApp010Fs(t, "app010_app.yaml", func(app *App010) {
 		envs, err := app.Environments()
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -73,3 +74,111 @@ func Test_findRoot(t *testing.T) {
 	}
 
 }
+
+func TestResolveEnvironment(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	a := NewApp010(fs, "/", nil)
+	a.load = func() error {
+		return nil
+	}
+
+	east := &EnvironmentDestinationSpec{
+		Name:      "us-east",
+		Server:    "http://east.com",
+		Namespace: "prod",
+		Params: map[string]map[string]interface{}{
+			"guestbook": {"replicas": 3},
+		},
+	}
+	west := &EnvironmentDestinationSpec{
+		Name:      "us-west",
+		Server:    "http://west.com",
+		Namespace: "prod",
+	}
+
+	a.config.Environments = EnvironmentConfigs{
+		"prod": &EnvironmentConfig{
+			Name:         "prod",
+			Path:         "prod",
+			Destinations: []*EnvironmentDestinationSpec{east, west},
+		},
+		"default": &EnvironmentConfig{
+			Name: "default",
+			Path: "default",
+			Destination: &EnvironmentDestinationSpec{
+				Server:    "http://default.com",
+				Namespace: "default",
+			},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		envName  string
+		expected *EnvironmentConfig
+		isErr    bool
+	}{
+		{
+			name:    "environment without destinations",
+			envName: "default",
+			expected: &EnvironmentConfig{
+				Name: "default",
+				Path: "default",
+				Destination: &EnvironmentDestinationSpec{
+					Server:    "http://default.com",
+					Namespace: "default",
+				},
+			},
+		},
+		{
+			name:    "first destination without its params",
+			envName: "prod",
+			expected: &EnvironmentConfig{
+				Name: "prod",
+				Path: "prod",
+				Destination: &EnvironmentDestinationSpec{
+					Name:      "us-east",
+					Server:    "http://east.com",
+					Namespace: "prod",
+				},
+				Destinations: []*EnvironmentDestinationSpec{east, west},
+			},
+		},
+		{
+			name:    "select a destination",
+			envName: "prod@us-east",
+			expected: &EnvironmentConfig{
+				Name:        "prod@us-east",
+				Path:        "prod",
+				Destination: east,
+			},
+		},
+		{
+			name:    "unknown destination",
+			envName: "prod@eu-west",
+			isErr:   true,
+		},
+		{
+			name:    "unknown environment",
+			envName: "staging@us-west",
+			isErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := ResolveEnvironment(a, tc.envName)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, e)
+		})
+	}
+
+	e, err := a.Environment("prod")
+	require.NoError(t, err)
+	assert.Nil(t, e.Destination)
+}
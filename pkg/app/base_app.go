@@ -328,6 +328,10 @@ func (ba *baseApp) EnvironmentParams(envName string) (string, error) {
 	if envName == "" {
 		return "", errors.New("environment name is blank")
 	}
+
+	// Destinations of an environment share its params.
+	envName, _ = SplitDestination(envName)
+
 	envParamsPath := filepath.Join(ba.Root(), EnvironmentDirName, envName, "params.libsonnet")
 	b, err := afero.ReadFile(ba.Fs(), envParamsPath)
 	if err != nil {
@@ -347,10 +351,15 @@ func (ba *baseApp) Environment(name string) (*EnvironmentConfig, error) {
 		return nil, errors.Wrap(err, "load configuration")
 	}
 
+	if envName, destName := SplitDestination(name); destName != "" {
+		return nil, errors.Errorf("%q selects destination %q of environment %q; use the environment name", name, destName, envName)
+	}
+
 	e := ba.mergedEnvironment(name)
 	if e == nil {
 		return nil, errors.Errorf("environment %q was not found", name)
 	}
+
 	return e, nil
 }
 
@@ -367,12 +376,39 @@ func deepCopyLibraries(src LibraryConfigs) LibraryConfigs {
 	return lc
 }
 
+func deepCopyDestination(src *EnvironmentDestinationSpec) *EnvironmentDestinationSpec {
+	d := *src
+	if src.Params != nil {
+		d.Params = make(map[string]map[string]interface{})
+		for component, params := range src.Params {
+			m := make(map[string]interface{})
+			for k, v := range params {
+				m[k] = v
+			}
+			d.Params[component] = m
+		}
+	}
+
+	return &d
+}
+
+func deepCopyDestinations(src []*EnvironmentDestinationSpec) []*EnvironmentDestinationSpec {
+	destinations := make([]*EnvironmentDestinationSpec, len(src))
+	for i := range src {
+		destinations[i] = deepCopyDestination(src[i])
+	}
+
+	return destinations
+}
+
 func deepCopyEnvironmentConfig(src EnvironmentConfig) *EnvironmentConfig {
 	e := src
 
 	if src.Destination != nil {
-		d := *src.Destination
-		e.Destination = &d
+		e.Destination = deepCopyDestination(src.Destination)
+	}
+	if src.Destinations != nil {
+		e.Destinations = deepCopyDestinations(src.Destinations)
 	}
 	if src.Targets != nil {
 		t := make([]string, len(src.Targets))
@@ -419,8 +455,10 @@ func (ba *baseApp) mergedEnvironment(name string) *EnvironmentConfig {
 		combined.KubernetesVersion = override.KubernetesVersion
 		combined.Path = override.Path
 		if override.Destination != nil {
-			d := *override.Destination
-			combined.Destination = &d
+			combined.Destination = deepCopyDestination(override.Destination)
+		}
+		if override.Destinations != nil {
+			combined.Destinations = deepCopyDestinations(override.Destinations)
 		}
 		if override.Targets != nil {
 			t := make([]string, len(override.Targets))
@@ -438,6 +476,20 @@ func (ba *baseApp) mergedEnvironment(name string) *EnvironmentConfig {
 	}
 }
 
+// Validation returns the validation configuration. It is empty if the app does
+// not configure validation.
+func (ba *baseApp) Validation() (*ValidationConfig, error) {
+	if err := ba.load(); err != nil {
+		return nil, errors.Wrap(err, "load configuration")
+	}
+
+	if ba.config.Validation == nil {
+		return &ValidationConfig{}, nil
+	}
+
+	return ba.config.Validation, nil
+}
+
 // Environments returns all environment specs, merged with any corresponding overrides.
 // Note overrides cannot override environment libraries.
 func (ba *baseApp) Environments() (EnvironmentConfigs, error) {
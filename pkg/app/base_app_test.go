@@ -339,6 +339,44 @@ func Test_baseApp_environment_override_is_merged(t *testing.T) {
 	assert.Equal(t, expected, e)
 }
 
+func Test_baseApp_environment_destinations(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	ba := newBaseApp(fs, "/", nil)
+	ba.load = func() error {
+		return nil
+	}
+
+	east := &EnvironmentDestinationSpec{
+		Name:      "us-east",
+		Server:    "http://east.com",
+		Namespace: "prod",
+		Params: map[string]map[string]interface{}{
+			"guestbook": {"replicas": 3},
+		},
+	}
+
+	ba.config.Environments = EnvironmentConfigs{
+		"prod": &EnvironmentConfig{
+			Name:         "prod",
+			Path:         "prod",
+			Destinations: []*EnvironmentDestinationSpec{east},
+		},
+	}
+
+	e, err := ba.Environment("prod")
+	require.NoError(t, err)
+
+	expected := &EnvironmentConfig{
+		Name:         "prod",
+		Path:         "prod",
+		Destinations: []*EnvironmentDestinationSpec{east},
+	}
+	assert.Equal(t, expected, e)
+
+	_, err = ba.Environment("prod@us-east")
+	require.Error(t, err)
+}
+
 func Test_baseApp_environment_just_override(t *testing.T) {
 	fs := afero.NewMemMapFs()
 	ba := newBaseApp(fs, "/", nil)
@@ -375,3 +413,33 @@ func Test_baseApp_environment_just_override(t *testing.T) {
 
 	assert.Equal(t, expected, e)
 }
+
+func Test_baseApp_Validation(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	stageFile(t, fs, "validation_app.yaml", "/app.yaml")
+	ba := newBaseApp(fs, "/", nil)
+
+	config, err := ba.Validation()
+	require.NoError(t, err)
+
+	expected := &ValidationConfig{
+		Rules: map[string]*RuleConfig{
+			"no-latest-tag":   {},
+			"resource-limits": {Severity: "warning"},
+		},
+		CustomRules: []*CustomRuleConfig{
+			{Path: "rules/team.jsonnet", Severity: "error"},
+		},
+	}
+	require.Equal(t, expected, config)
+}
+
+func Test_baseApp_Validation_not_configured(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	stageFile(t, fs, "app010_app.yaml", "/app.yaml")
+	ba := newBaseApp(fs, "/", nil)
+
+	config, err := ba.Validation()
+	require.NoError(t, err)
+	require.Equal(t, &ValidationConfig{}, config)
+}
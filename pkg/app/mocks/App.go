@@ -367,6 +367,29 @@ func (_m *App) Upgrade(dryRun bool) error {
 	return r0
 }
 
+// Validation provides a mock function with given fields:
+func (_m *App) Validation() (*app.ValidationConfig, error) {
+	ret := _m.Called()
+
+	var r0 *app.ValidationConfig
+	if rf, ok := ret.Get(0).(func() *app.ValidationConfig); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*app.ValidationConfig)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // VendorPath provides a mock function with given fields:
 func (_m *App) VendorPath() string {
 	ret := _m.Called()
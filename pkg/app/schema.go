@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/blang/semver"
 	"github.com/ghodss/yaml"
@@ -34,6 +35,9 @@ const (
 	Kind = "ksonnet.io/app"
 	// DefaultVersion is the default version of the app schema.
 	DefaultVersion = "0.0.1"
+	// DestinationSeparator separates an environment name from the name of one
+	// of its destinations, e.g. `prod@us-east`.
+	DestinationSeparator = "@"
 )
 
 var (
@@ -81,6 +85,7 @@ type Spec struct {
 	Environments EnvironmentConfigs `json:"environments,omitempty"`
 	Libraries    LibraryConfigs     `json:"libraries,omitempty"`
 	License      string             `json:"license,omitempty"`
+	Validation   *ValidationConfig  `json:"validation,omitempty"`
 }
 
 // Read will return the specification for a ksonnet application. It will navigate up directories
@@ -306,6 +311,9 @@ type EnvironmentConfig struct {
 	Path string `json:"path"`
 	// Destination stores the cluster address that this environment points to.
 	Destination *EnvironmentDestinationSpec `json:"destination"`
+	// Destinations stores the cluster addresses of an environment which is
+	// deployed to more than one cluster. Each destination must have a name.
+	Destinations []*EnvironmentDestinationSpec `json:"destinations,omitempty"`
 	// Targets contain the relative component paths that this environment
 	// wishes to deploy on it's destination.
 	Targets []string `json:"targets,omitempty"`
@@ -328,6 +336,51 @@ func (e *EnvironmentConfig) IsOverride() bool {
 	return e.isOverride
 }
 
+// DestinationEnvNames returns the environment names which select each of the
+// environment's destinations. An environment without a list of destinations
+// only has its own name.
+func (e *EnvironmentConfig) DestinationEnvNames() []string {
+	if len(e.Destinations) == 0 {
+		return []string{e.Name}
+	}
+
+	var names []string
+	for _, d := range e.Destinations {
+		names = append(names, DestinationEnvName(e.Name, d.Name))
+	}
+
+	return names
+}
+
+// destination returns the named destination of an environment.
+func (e *EnvironmentConfig) destination(name string) (*EnvironmentDestinationSpec, bool) {
+	for _, d := range e.Destinations {
+		if d.Name == name {
+			return d, true
+		}
+	}
+
+	return nil, false
+}
+
+// DestinationEnvName returns the environment name which selects one of an
+// environment's destinations.
+func DestinationEnvName(envName, destName string) string {
+	return envName + DestinationSeparator + destName
+}
+
+// SplitDestination splits an environment name into the name of the environment
+// and the name of the destination it selects. The destination name is empty
+// if the name does not select a destination.
+func SplitDestination(name string) (envName, destName string) {
+	parts := strings.SplitN(name, DestinationSeparator, 2)
+	if len(parts) == 1 {
+		return name, ""
+	}
+
+	return parts[0], parts[1]
+}
+
 // EnvironmentDestinationSpec contains the specification for the cluster
 // address that the environment points to.
 type EnvironmentDestinationSpec struct {
@@ -336,6 +389,12 @@ type EnvironmentDestinationSpec struct {
 	// Namespace is the namespace of the Kubernetes server that targets should
 	// be deployed to. This is "default", if not specified.
 	Namespace string `json:"namespace"`
+	// Name is the name of the destination. It is only used for the
+	// destinations of an environment with more than one destination.
+	Name string `json:"name,omitempty"`
+	// Params override component parameters for this destination. They are
+	// keyed by component name, as in the environment's params.libsonnet.
+	Params map[string]map[string]interface{} `json:"params,omitempty"`
 }
 
 // LibraryConfig is the specification for a library part.
@@ -417,6 +476,35 @@ func (l *LibraryConfig) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// ValidationConfig configures the policy rules `ks validate` checks rendered
+// objects against.
+type ValidationConfig struct {
+	// Rules configures built-in rules by name.
+	Rules map[string]*RuleConfig `json:"rules,omitempty"`
+	// CustomRules are rules written in Jsonnet.
+	CustomRules []*CustomRuleConfig `json:"customRules,omitempty"`
+}
+
+// RuleConfig configures a built-in rule.
+type RuleConfig struct {
+	// Severity is the severity of violations of the rule: error, warning or off.
+	// It is error if not specified.
+	Severity string `json:"severity,omitempty"`
+}
+
+// CustomRuleConfig configures a rule written in Jsonnet. The Jsonnet evaluates to
+// a function which receives a rendered object and its environment, and returns
+// an array of violations.
+type CustomRuleConfig struct {
+	// Name is the name of the rule. It is the base name of Path if not specified.
+	Name string `json:"name,omitempty"`
+	// Path is the path of the Jsonnet file, relative to the app root.
+	Path string `json:"path"`
+	// Severity is the severity of violations of the rule: error, warning or off.
+	// It is error if not specified.
+	Severity string `json:"severity,omitempty"`
+}
+
 // ContributorSpec is a specification for the project contributors.
 type ContributorSpec struct {
 	Name  string `json:"name"`
@@ -475,6 +563,12 @@ func (s *Spec) validate() error {
 		s.Environments = EnvironmentConfigs{}
 	}
 
+	for name, env := range s.Environments {
+		if err := validateDestinations(name, env); err != nil {
+			return err
+		}
+	}
+
 	if s.APIVersion == "0.0.0" {
 		return errors.New("invalid version")
 	}
@@ -501,6 +595,34 @@ func (s *Spec) validate() error {
 	return nil
 }
 
+// validateDestinations checks that every destination of an environment with
+// a list of destinations has a unique name.
+func validateDestinations(envName string, env *EnvironmentConfig) error {
+	if env == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for i, d := range env.Destinations {
+		if d == nil {
+			return errors.Errorf("environment %q: destination %d is empty", envName, i)
+		}
+
+		switch {
+		case d.Name == "":
+			return errors.Errorf("environment %q: destination %d does not have a name", envName, i)
+		case strings.Contains(d.Name, DestinationSeparator):
+			return errors.Errorf("environment %q: destination name %q can not contain %q", envName, d.Name, DestinationSeparator)
+		case seen[d.Name]:
+			return errors.Errorf("environment %q: destination %q is listed more than once", envName, d.Name)
+		}
+
+		seen[d.Name] = true
+	}
+
+	return nil
+}
+
 // GetEnvironmentConfigs returns all environment specifications.
 // TODO: Consider returning copies instead of originals
 func (s *Spec) GetEnvironmentConfigs() EnvironmentConfigs {
@@ -213,6 +213,91 @@ environments:
 	}
 }
 
+func TestEnvironmentConfig_DestinationEnvNames(t *testing.T) {
+	e := &EnvironmentConfig{Name: "prod"}
+	assert.Equal(t, []string{"prod"}, e.DestinationEnvNames())
+
+	e.Destinations = []*EnvironmentDestinationSpec{
+		{Name: "us-east"},
+		{Name: "us-west"},
+	}
+	assert.Equal(t, []string{"prod@us-east", "prod@us-west"}, e.DestinationEnvNames())
+}
+
+func TestSplitDestination(t *testing.T) {
+	cases := []struct {
+		name     string
+		envName  string
+		destName string
+	}{
+		{name: "prod", envName: "prod"},
+		{name: "prod@us-east", envName: "prod", destName: "us-east"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			envName, destName := SplitDestination(tc.name)
+			assert.Equal(t, tc.envName, envName)
+			assert.Equal(t, tc.destName, destName)
+		})
+	}
+}
+
+func Test_validateDestinations(t *testing.T) {
+	cases := []struct {
+		name         string
+		destinations []*EnvironmentDestinationSpec
+		isErr        bool
+	}{
+		{
+			name: "no destinations",
+		},
+		{
+			name: "named destinations",
+			destinations: []*EnvironmentDestinationSpec{
+				{Name: "us-east"},
+				{Name: "us-west"},
+			},
+		},
+		{
+			name:         "empty destination",
+			destinations: []*EnvironmentDestinationSpec{nil},
+			isErr:        true,
+		},
+		{
+			name:         "unnamed destination",
+			destinations: []*EnvironmentDestinationSpec{{Server: "http://example.com"}},
+			isErr:        true,
+		},
+		{
+			name:         "name contains separator",
+			destinations: []*EnvironmentDestinationSpec{{Name: "us@east"}},
+			isErr:        true,
+		},
+		{
+			name: "duplicate name",
+			destinations: []*EnvironmentDestinationSpec{
+				{Name: "us-east"},
+				{Name: "us-east"},
+			},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := &EnvironmentConfig{Destinations: tc.destinations}
+
+			err := validateDestinations("prod", env)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestGetEnvironmentSpecSuccess(t *testing.T) {
 	const (
 		env        = "dev"
@@ -28,6 +28,7 @@ const (
 	actionComponentRm
 	actionDelete
 	actionDiff
+	actionDrift
 	actionEnvAdd
 	actionEnvCurrent
 	actionEnvDescribe
@@ -36,12 +37,16 @@ const (
 	actionEnvSet
 	actionEnvTargets
 	actionEnvUpdate
+	actionHistory
 	actionImport
 	actionInit
+	actionLint
 	actionModuleCreate
 	actionModuleList
 	actionParamDelete
 	actionParamDiff
+	actionParamExport
+	actionParamImport
 	actionParamList
 	actionParamSet
 	actionParamUnset
@@ -53,11 +58,14 @@ const (
 	actionPrototypePreview
 	actionPrototypeSearch
 	actionPrototypeUse
+	actionPrune
 	actionRegistryAdd
 	actionRegistryDescribe
 	actionRegistryList
 	actionRegistrySet
+	actionRollback
 	actionShow
+	actionStatus
 	actionUpgrade
 	actionValidate
 )
@@ -71,6 +79,7 @@ var (
 		actionComponentRm:       actions.RunComponentRm,
 		actionDelete:            actions.RunDelete,
 		actionDiff:              actions.RunDiff,
+		actionDrift:             actions.RunDrift,
 		actionEnvAdd:            actions.RunEnvAdd,
 		actionEnvCurrent:        actions.RunEnvCurrent,
 		actionEnvDescribe:       actions.RunEnvDescribe,
@@ -79,12 +88,16 @@ var (
 		actionEnvSet:            actions.RunEnvSet,
 		actionEnvTargets:        actions.RunEnvTargets,
 		actionEnvUpdate:         actions.RunEnvUpdate,
+		actionHistory:           actions.RunHistory,
 		actionImport:            actions.RunImport,
 		actionInit:              actions.RunInit,
+		actionLint:              actions.RunLint,
 		actionModuleCreate:      actions.RunModuleCreate,
 		actionModuleList:        actions.RunModuleList,
 		actionParamDiff:         actions.RunParamDiff,
 		actionParamDelete:       actions.RunParamDelete,
+		actionParamExport:       actions.RunParamExport,
+		actionParamImport:       actions.RunParamImport,
 		actionParamUnset:        actions.RunParamDelete,
 		actionParamList:         actions.RunParamList,
 		actionParamSet:          actions.RunParamSet,
@@ -96,11 +109,14 @@ var (
 		actionPrototypePreview:  actions.RunPrototypePreview,
 		actionPrototypeSearch:   actions.RunPrototypeSearch,
 		actionPrototypeUse:      actions.RunPrototypeUse,
+		actionPrune:             actions.RunPrune,
 		actionRegistryAdd:       actions.RunRegistryAdd,
 		actionRegistryDescribe:  actions.RunRegistryDescribe,
 		actionRegistryList:      actions.RunRegistryList,
 		actionRegistrySet:       actions.RunRegistrySet,
+		actionRollback:          actions.RunRollback,
 		actionShow:              actions.RunShow,
+		actionStatus:            actions.RunStatus,
 		actionUpgrade:           actions.RunUpgrade,
 		actionValidate:          actions.RunValidate,
 	}
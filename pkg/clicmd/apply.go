@@ -16,6 +16,8 @@
 package clicmd
 
 import (
+	"time"
+
 	"github.com/ksonnet/ksonnet/pkg/actions"
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
@@ -25,11 +27,15 @@ import (
 )
 
 const (
-	vApplyComponent = "apply-components"
-	vApplyCreate    = "apply-create"
-	vApplyGcTag     = "apply-gc-tag"
-	vApplyDryRun    = "apply-dry-run"
-	vApplySkipGc    = "apply-skip-gc"
+	vApplyComponent   = "apply-components"
+	vApplyConcurrency = "apply-concurrency"
+	vApplyCreate      = "apply-create"
+	vApplyGcTag       = "apply-gc-tag"
+	vApplyDryRun      = "apply-dry-run"
+	vApplyOutput      = "apply-output"
+	vApplySkipGc      = "apply-skip-gc"
+	vApplyTimeout     = "apply-timeout"
+	vApplyWait        = "apply-wait"
 
 	applyShortDesc = "Apply local Kubernetes manifests (components) to remote clusters"
 	applyLong      = `
@@ -44,12 +50,43 @@ expanded using the parameters of the specified environment.
 By default, all component manifests are applied. To apply a subset of components,
 use the ` + "`--component` " + `flag, as seen in the examples below.
 
+By default, ` + "`apply`" + ` returns as soon as every object has been created or updated.
+Use the ` + "`--wait`" + ` flag to wait until the applied objects are ready: Deployments,
+StatefulSets and DaemonSets have rolled out, Jobs are complete, Services have endpoints,
+and CustomResourceDefinitions are established. If the objects are not ready within
+` + "`--timeout`" + `, apply fails and lists the objects that are not ready.
+
+Objects are applied in dependency tiers: namespaces and resource definitions first,
+then configuration, then workloads. Use the ` + "`--concurrency`" + ` flag to apply more
+than one object in a tier at a time. Applying one object at a time stops at the first
+failure. With a higher concurrency, every object in the tier is attempted. If any object
+in a tier fails, the errors for the tier are reported and later tiers are not applied.
+
+Objects annotated with ` + "`ksonnet.io/phase: pre-apply`" + ` or ` + "`ksonnet.io/phase: post-apply`" + `
+are hooks. Instead of being applied with the other objects, hooks are run before or
+after them, e.g. a Job that runs database migrations before workloads roll out. Each
+hook is created, waited on until it is ready (Jobs must complete), and then deleted
+according to its ` + "`ksonnet.io/hook-delete-policy`" + ` annotation: ` + "`succeeded`" + ` (the
+default) deletes hooks which succeed, ` + "`always`" + ` deletes every hook, and ` + "`never`" + `
+keeps them. Hooks in a phase run in order of their ` + "`ksonnet.io/hook-weight`" + `
+annotation, lowest first. If a hook fails, apply stops. Hooks from Helm charts are
+converted to ksonnet hooks.
+
+When apply finishes, a summary table lists every object with the action taken
+(created, configured, unchanged or pruned), its UID and how long it took. Use
+` + "`--output json`" + ` to print the summary as JSON, e.g. for use in CI pipelines.
+
+Applying all of the components in an environment records the applied objects as
+a new revision. Use ` + "`ks history`" + ` to list revisions and ` + "`ks rollback`" + ` to
+return to one of them. Dry runs and applies of selected components are not recorded.
+
 Note that this command needs to be run *within* a ksonnet app directory.
 
 ### Related Commands
 
 * ` + "`ks diff` " + `— ` + diffShortDesc + `
 * ` + "`ks delete` " + `— ` + deleteShortDesc + `
+* ` + "`ks history` " + `— ` + historyShortDesc + `
 
 ### Syntax
 `
@@ -63,7 +100,9 @@ Note that this command needs to be run *within* a ksonnet app directory.
 ks apply dev
 
 # Similar to the previous command, but does not immediately execute. Use this to
-# see a preview of the cluster-changing actions.
+# see a preview of the cluster-changing actions. A unified diff between the live
+# and applied version is printed for every object, followed by a summary of the
+# objects which would be created, configured, unchanged or pruned.
 ks apply dev --dry-run
 
 # Create or update the single 'guestbook-ui' component of a ksonnet app, specifically
@@ -79,6 +118,18 @@ ks apply dev -c guestbook-ui
 # This essentially deploys 'components/guestbook-ui.jsonnet' and
 # 'components/nginx-depl.jsonnet'.
 ks apply dev -c guestbook-ui -c nginx-depl --create false
+
+# Create or update all resources in the 'dev' environment and wait up to ten minutes
+# for them to become ready.
+ks apply dev --wait --timeout 10m
+
+# Create or update all resources in the 'dev' environment, applying up to ten objects
+# in each dependency tier at a time.
+ks apply dev --concurrency 10
+
+# Create or update all resources in the 'dev' environment and print the summary of
+# the applied objects as JSON.
+ks apply dev -o json
 `
 )
 
@@ -98,11 +149,15 @@ func newApplyCmd(a app.App) *cobra.Command {
 				actions.OptionApp:            a,
 				actions.OptionClientConfig:   applyClientConfig,
 				actions.OptionComponentNames: viper.GetStringSlice(vApplyComponent),
+				actions.OptionConcurrency:    viper.GetInt(vApplyConcurrency),
 				actions.OptionCreate:         viper.GetBool(vApplyCreate),
 				actions.OptionDryRun:         viper.GetBool(vApplyDryRun),
 				actions.OptionEnvName:        envName,
 				actions.OptionGcTag:          viper.GetString(vApplyGcTag),
+				actions.OptionOutput:         viper.GetString(vApplyOutput),
 				actions.OptionSkipGc:         viper.GetBool(vApplySkipGc),
+				actions.OptionTimeout:        viper.GetDuration(vApplyTimeout),
+				actions.OptionWait:           viper.GetBool(vApplyWait),
 			}
 
 			if err := extractJsonnetFlags(a, "apply"); err != nil {
@@ -117,10 +172,14 @@ func newApplyCmd(a app.App) *cobra.Command {
 
 	applyClientConfig.BindClientGoFlags(applyCmd)
 	bindJsonnetFlags(applyCmd, "apply")
+	addCmdOutput(applyCmd, vApplyOutput)
 
 	applyCmd.Flags().StringSliceP(flagComponent, shortComponent, nil, "Name of a specific component (multiple -c flags accepted, allows YAML, JSON, and Jsonnet)")
 	viper.BindPFlag(vApplyComponent, applyCmd.Flags().Lookup(flagComponent))
 
+	applyCmd.Flags().Int(flagConcurrency, 1, "Number of objects in a dependency tier to apply at a time")
+	viper.BindPFlag(vApplyConcurrency, applyCmd.Flags().Lookup(flagConcurrency))
+
 	applyCmd.Flags().Bool(flagCreate, true, "Option to create resources if they do not already exist on the cluster")
 	viper.BindPFlag(vApplyCreate, applyCmd.Flags().Lookup(flagCreate))
 
@@ -130,8 +189,14 @@ func newApplyCmd(a app.App) *cobra.Command {
 	applyCmd.Flags().String(flagGcTag, "", "A tag that's (1) added to all updated objects (2) used to garbage collect existing objects that are no longer in the manifest")
 	viper.BindPFlag(vApplyGcTag, applyCmd.Flags().Lookup(flagGcTag))
 
-	applyCmd.Flags().Bool(flagDryRun, false, "Option to preview the list of operations without changing the cluster state")
+	applyCmd.Flags().Bool(flagDryRun, false, "Option to preview the changes to each object without changing the cluster state")
 	viper.BindPFlag(vApplyDryRun, applyCmd.Flags().Lookup(flagDryRun))
 
+	applyCmd.Flags().Bool(flagWait, false, "Option to wait for applied objects to become ready")
+	viper.BindPFlag(vApplyWait, applyCmd.Flags().Lookup(flagWait))
+
+	applyCmd.Flags().Duration(flagTimeout, 5*time.Minute, "Length of time to wait for hooks, and for objects to become ready when --"+flagWait+" is specified")
+	viper.BindPFlag(vApplyTimeout, applyCmd.Flags().Lookup(flagTimeout))
+
 	return applyCmd
 }
@@ -17,6 +17,7 @@ package clicmd
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 
@@ -33,11 +34,72 @@ func Test_applyCmd(t *testing.T) {
 				actions.OptionApp:            mock.AnythingOfType("*app.App"),
 				actions.OptionEnvName:        "default",
 				actions.OptionGcTag:          "",
+				actions.OptionOutput:         "",
 				actions.OptionSkipGc:         false,
 				actions.OptionComponentNames: make([]string, 0),
+				actions.OptionConcurrency:    1,
 				actions.OptionCreate:         true,
 				actions.OptionDryRun:         false,
 				actions.OptionClientConfig:   mock.AnythingOfType("*client.Config"),
+				actions.OptionTimeout:        5 * time.Minute,
+				actions.OptionWait:           false,
+			},
+		},
+		{
+			name:   "with wait",
+			args:   []string{"apply", "default", "--wait", "--timeout", "1m"},
+			action: actionApply,
+			expected: map[string]interface{}{
+				actions.OptionApp:            mock.AnythingOfType("*app.App"),
+				actions.OptionEnvName:        "default",
+				actions.OptionGcTag:          "",
+				actions.OptionOutput:         "",
+				actions.OptionSkipGc:         false,
+				actions.OptionComponentNames: make([]string, 0),
+				actions.OptionConcurrency:    1,
+				actions.OptionCreate:         true,
+				actions.OptionDryRun:         false,
+				actions.OptionClientConfig:   mock.AnythingOfType("*client.Config"),
+				actions.OptionTimeout:        time.Minute,
+				actions.OptionWait:           true,
+			},
+		},
+		{
+			name:   "with concurrency",
+			args:   []string{"apply", "default", "--concurrency", "4"},
+			action: actionApply,
+			expected: map[string]interface{}{
+				actions.OptionApp:            mock.AnythingOfType("*app.App"),
+				actions.OptionEnvName:        "default",
+				actions.OptionGcTag:          "",
+				actions.OptionOutput:         "",
+				actions.OptionSkipGc:         false,
+				actions.OptionComponentNames: make([]string, 0),
+				actions.OptionConcurrency:    4,
+				actions.OptionCreate:         true,
+				actions.OptionDryRun:         false,
+				actions.OptionClientConfig:   mock.AnythingOfType("*client.Config"),
+				actions.OptionTimeout:        5 * time.Minute,
+				actions.OptionWait:           false,
+			},
+		},
+		{
+			name:   "with json output",
+			args:   []string{"apply", "default", "-o", "json"},
+			action: actionApply,
+			expected: map[string]interface{}{
+				actions.OptionApp:            mock.AnythingOfType("*app.App"),
+				actions.OptionEnvName:        "default",
+				actions.OptionGcTag:          "",
+				actions.OptionOutput:         "json",
+				actions.OptionSkipGc:         false,
+				actions.OptionComponentNames: make([]string, 0),
+				actions.OptionConcurrency:    1,
+				actions.OptionCreate:         true,
+				actions.OptionDryRun:         false,
+				actions.OptionClientConfig:   mock.AnythingOfType("*client.Config"),
+				actions.OptionTimeout:        5 * time.Minute,
+				actions.OptionWait:           false,
 			},
 		},
 		{
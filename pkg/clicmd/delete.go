@@ -16,6 +16,8 @@
 package clicmd
 
 import (
+	"time"
+
 	"github.com/ksonnet/ksonnet/pkg/actions"
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
@@ -26,7 +28,15 @@ import (
 
 const (
 	vDeleteComponent   = "delete-components"
+	vDeleteDryRun      = "delete-dry-run"
 	vDeleteGracePeriod = "delete-grace-period"
+	vDeleteKind        = "delete-kinds"
+	vDeleteName        = "delete-names"
+	vDeleteOrphan      = "delete-orphan"
+	vDeleteSelector    = "delete-selector"
+	vDeleteTimeout     = "delete-timeout"
+	vDeleteWait        = "delete-wait"
+	vDeleteYes         = "delete-yes"
 
 	deleteShortDesc = "Remove component-specified Kubernetes resources from remote clusters"
 	deleteLong      = `
@@ -35,7 +45,24 @@ The ` + "`delete`" + ` command removes Kubernetes resources (described in local
 ` + "`<env-name>`" + `argument.
 
 An entire ksonnet application can be removed from a cluster, or just its specific
-components.
+components. The objects can be narrowed further with a label selector (` + "`--selector`" + `),
+and by kind (` + "`--kind`" + `) and name (` + "`--name`" + `).
+
+The objects to be removed are listed, and you are asked to confirm before anything
+is removed. Use ` + "`--yes`" + ` to skip the confirmation, or ` + "`--dry-run`" + ` to list
+the objects without removing them. If stdin is not a terminal, ` + "`--yes`" + ` is required.
+
+By default, the dependents of removed objects (e.g. the pods of a deployment) are
+removed before the objects themselves. Use ` + "`--orphan`" + ` to leave the dependents
+in the cluster.
+
+Removal requests return before the objects are gone, e.g. while pods terminate. Use
+` + "`--wait`" + ` to wait until every removed object no longer exists. If objects remain
+after ` + "`--timeout`" + `, delete fails and lists them, including the finalizers which
+are holding them.
+
+Objects annotated with ` + "`ksonnet.io/phase: pre-delete`" + ` are hooks which are run
+before any resources are removed. See ` + "`ks apply`" + ` for how hooks are run.
 
 **This command can be considered the inverse of the ` + "`ks apply`" + ` command.**
 
@@ -54,14 +81,26 @@ ks delete dev
 # Delete resources described by the 'nginx' component. $KUBECONFIG is overridden by
 # the CLI-specified './kubeconfig', so these changes are deployed to the current
 # context's cluster (not the 'default' environment)
-ks delete --kubeconfig=./kubeconfig -c nginx`
+ks delete --kubeconfig=./kubeconfig -c nginx
+
+# List the deployments labeled 'tier=frontend' in the 'dev' environment, without
+# removing them.
+ks delete dev -l tier=frontend --kind deployment --dry-run
+
+# Remove the 'redis' deployment without asking for confirmation, leaving its pods
+# running.
+ks delete dev --kind deployment --name redis --orphan --yes
+
+# Remove the resources from the 'dev' environment and wait up to 10 minutes for
+# them to be gone, e.g. before applying the environment again.
+ks delete dev --yes --wait --timeout 10m`
 )
 
 func newDeleteCmd(a app.App) *cobra.Command {
 	deleteClientConfig := client.NewDefaultClientConfig(a)
 
 	deleteCmd := &cobra.Command{
-		Use:     "delete [env-name] [-c <component-name>]",
+		Use:     "delete [env-name] [-c <component-name>] [-l <selector>]",
 		Short:   deleteShortDesc,
 		Long:    deleteLong,
 		Example: deleteExample,
@@ -75,8 +114,16 @@ func newDeleteCmd(a app.App) *cobra.Command {
 				actions.OptionApp:            a,
 				actions.OptionClientConfig:   deleteClientConfig,
 				actions.OptionComponentNames: viper.GetStringSlice(vDeleteComponent),
+				actions.OptionDryRun:         viper.GetBool(vDeleteDryRun),
 				actions.OptionEnvName:        envName,
 				actions.OptionGracePeriod:    viper.GetInt64(vDeleteGracePeriod),
+				actions.OptionKinds:          viper.GetStringSlice(vDeleteKind),
+				actions.OptionNames:          viper.GetStringSlice(vDeleteName),
+				actions.OptionOrphan:         viper.GetBool(vDeleteOrphan),
+				actions.OptionSelector:       viper.GetString(vDeleteSelector),
+				actions.OptionSkipConfirm:    viper.GetBool(vDeleteYes),
+				actions.OptionTimeout:        viper.GetDuration(vDeleteTimeout),
+				actions.OptionWait:           viper.GetBool(vDeleteWait),
 			}
 
 			if err := extractJsonnetFlags(a, "delete"); err != nil {
@@ -96,5 +143,29 @@ func newDeleteCmd(a app.App) *cobra.Command {
 	deleteCmd.Flags().Int64(flagGracePeriod, -1, "Number of seconds given to resources to terminate gracefully. A negative value is ignored")
 	viper.BindPFlag(vDeleteGracePeriod, deleteCmd.Flags().Lookup(flagGracePeriod))
 
+	deleteCmd.Flags().StringP(flagSelector, shortSelector, "", "Label selector the objects must match, e.g. 'tier=frontend'")
+	viper.BindPFlag(vDeleteSelector, deleteCmd.Flags().Lookup(flagSelector))
+
+	deleteCmd.Flags().StringSlice(flagKind, nil, "Kind of the objects to remove (multiple --kind flags accepted)")
+	viper.BindPFlag(vDeleteKind, deleteCmd.Flags().Lookup(flagKind))
+
+	deleteCmd.Flags().StringSlice(flagName, nil, "Name of the objects to remove (multiple --name flags accepted)")
+	viper.BindPFlag(vDeleteName, deleteCmd.Flags().Lookup(flagName))
+
+	deleteCmd.Flags().Bool(flagOrphan, false, "Option to leave the dependents of removed objects in the cluster")
+	viper.BindPFlag(vDeleteOrphan, deleteCmd.Flags().Lookup(flagOrphan))
+
+	deleteCmd.Flags().Bool(flagDryRun, false, "Option to list the objects which would be removed without changing the cluster state")
+	viper.BindPFlag(vDeleteDryRun, deleteCmd.Flags().Lookup(flagDryRun))
+
+	deleteCmd.Flags().Bool(flagYes, false, "Option to remove objects without asking for confirmation")
+	viper.BindPFlag(vDeleteYes, deleteCmd.Flags().Lookup(flagYes))
+
+	deleteCmd.Flags().Bool(flagWait, false, "Option to wait for removed objects to no longer exist")
+	viper.BindPFlag(vDeleteWait, deleteCmd.Flags().Lookup(flagWait))
+
+	deleteCmd.Flags().Duration(flagTimeout, 5*time.Minute, "Length of time to wait for hooks, and for objects to be removed when --"+flagWait+" is specified")
+	viper.BindPFlag(vDeleteTimeout, deleteCmd.Flags().Lookup(flagTimeout))
+
 	return deleteCmd
 }
@@ -17,6 +17,7 @@ package clicmd
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ksonnet/ksonnet/pkg/actions"
 )
@@ -32,7 +33,56 @@ func Test_deleteCmd(t *testing.T) {
 				actions.OptionEnvName:        "default",
 				actions.OptionComponentNames: make([]string, 0),
 				actions.OptionClientConfig:   nil,
+				actions.OptionDryRun:         false,
 				actions.OptionGracePeriod:    int64(-1),
+				actions.OptionKinds:          make([]string, 0),
+				actions.OptionNames:          make([]string, 0),
+				actions.OptionOrphan:         false,
+				actions.OptionSelector:       "",
+				actions.OptionSkipConfirm:    false,
+				actions.OptionTimeout:        5 * time.Minute,
+				actions.OptionWait:           false,
+			},
+		},
+		{
+			name: "with selection options",
+			args: []string{"delete", "default", "-l", "tier=frontend", "--kind", "deployment",
+				"--name", "redis", "--orphan", "--dry-run", "--yes"},
+			action: actionDelete,
+			expected: map[string]interface{}{
+				actions.OptionApp:            nil,
+				actions.OptionEnvName:        "default",
+				actions.OptionComponentNames: make([]string, 0),
+				actions.OptionClientConfig:   nil,
+				actions.OptionDryRun:         true,
+				actions.OptionGracePeriod:    int64(-1),
+				actions.OptionKinds:          []string{"deployment"},
+				actions.OptionNames:          []string{"redis"},
+				actions.OptionOrphan:         true,
+				actions.OptionSelector:       "tier=frontend",
+				actions.OptionSkipConfirm:    true,
+				actions.OptionTimeout:        5 * time.Minute,
+				actions.OptionWait:           false,
+			},
+		},
+		{
+			name:   "with wait",
+			args:   []string{"delete", "default", "--wait", "--timeout", "10m"},
+			action: actionDelete,
+			expected: map[string]interface{}{
+				actions.OptionApp:            nil,
+				actions.OptionEnvName:        "default",
+				actions.OptionComponentNames: make([]string, 0),
+				actions.OptionClientConfig:   nil,
+				actions.OptionDryRun:         false,
+				actions.OptionGracePeriod:    int64(-1),
+				actions.OptionKinds:          make([]string, 0),
+				actions.OptionNames:          make([]string, 0),
+				actions.OptionOrphan:         false,
+				actions.OptionSelector:       "",
+				actions.OptionSkipConfirm:    false,
+				actions.OptionTimeout:        10 * time.Minute,
+				actions.OptionWait:           true,
 			},
 		},
 		{
@@ -29,6 +29,7 @@ import (
 
 const (
 	vDiffComponentNames = "diff-component-names"
+	vDiffOutput         = "diff-output"
 
 	diffShortDesc = "Compare manifests, based on environment or location (local or remote)"
 )
@@ -36,8 +37,8 @@ const (
 var (
 	diffLong = `
 The ` + "`diff`" + ` command displays standard file diffs, and can be used to compare manifests
-based on *environment* or location ('local' ksonnet app manifests or what's running
-on a 'remote' server).
+based on *environment* or location ('local' ksonnet app manifests, what's running
+on a 'remote' server, or the app manifests at a 'git' revision).
 
 Using this command, you can compare:
 
@@ -45,6 +46,12 @@ Using this command, you can compare:
 2. *Remote* manifests for two separate environments
 3. *Local* manifests for two separate environments
 4. A *remote* manifest in one environment and a *local* manifest in another environment
+5. *Git* manifests, rendered from the app as it exists at a revision, with any of the above
+
+A git location has the form ` + "`git:<revision>:<environment>`" + `. The app is read from the
+revision without changing the working copy, so it must be inside a git repository,
+and the files needed to render it (including ` + "`vendor/`" + ` and ` + "`lib/`" + `) must be
+committed.
 
 To see the official syntax, see the examples below. Make sure that your $KUBECONFIG
 matches what you've defined in environments.
@@ -55,6 +62,25 @@ the files in the ` + "`components/`" + ` directory.
 When a component IS specified via the ` + "`-c`" + ` flag, this command only checks
 the manifest for that particular component.
 
+By default, manifests are compared as text. When an output format is specified via
+the ` + "`-o`" + ` flag, objects are paired by group, kind, namespace and name, and the
+changed fields of each object are reported by path. Fields populated by the server
+(such as ` + "`status`" + `, ` + "`metadata.uid`" + ` and ` + "`metadata.resourceVersion`" + `)
+and annotations managed by ksonnet are ignored. Valid formats are:
+
+* ` + "`text`" + ` — added (+), removed (-) and changed (~) objects and fields
+* ` + "`json`" + ` — the same report as JSON
+* ` + "`summary`" + ` — a table listing each object which differs
+
+The JSON report contains the number of added, removed, changed and unchanged objects,
+so it can be used to detect drift in CI without parsing text.
+
+### Exit Status
+
+* ` + "`0`" + ` — no differences were found
+* ` + "`1`" + ` — the diff could not be generated
+* ` + "`10`" + ` — differences were found
+
 ### Related Commands
 
 * ` + "`ks param diff` " + `— ` + paramShortDesc["diff"] + `
@@ -82,6 +108,18 @@ ks diff local:us-west/dev remote:us-west/prod
 # Show diff between what's in the local manifest and what's actually running in the
 # 'dev' environment, but for the Redis component ONLY
 ks diff dev -c redis
+
+# Show the rendered manifest changes a branch makes to the 'prod' environment,
+# compared to 'origin/master'. No cluster is required.
+ks diff git:origin/master:prod local:prod
+
+# Show which objects and fields differ between the local and remote 'dev'
+# environments, ignoring fields populated by the server.
+ks diff dev -o text
+
+# Check the 'prod' environment for drift from a CI job. The JSON report is written
+# to stdout, and the command exits with status 10 if differences were found.
+ks diff local:prod remote:prod -o json
 `
 )
 
@@ -106,6 +144,7 @@ func newDiffCmd(a app.App) *cobra.Command {
 				actions.OptionClientConfig:   diffClientConfig,
 				actions.OptionSrc1:           args[0],
 				actions.OptionComponentNames: viper.GetStringSlice(vDiffComponentNames),
+				actions.OptionOutput:         viper.GetString(vDiffOutput),
 			}
 
 			if len(args) == 2 {
@@ -126,5 +165,8 @@ func newDiffCmd(a app.App) *cobra.Command {
 	diffCmd.Flags().StringSliceP(flagComponent, shortComponent, nil, "Name of a specific component")
 	viper.BindPFlag(vDiffComponentNames, diffCmd.Flags().Lookup(flagComponent))
 
+	diffCmd.Flags().StringP(flagOutput, shortOutput, "", "Compare objects structurally. Valid options: text|json|summary")
+	viper.BindPFlag(vDiffOutput, diffCmd.Flags().Lookup(flagOutput))
+
 	return diffCmd
 }
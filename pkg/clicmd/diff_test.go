@@ -33,6 +33,19 @@ func Test_diffCmd(t *testing.T) {
 				actions.OptionSrc1:           "env1",
 				actions.OptionSrc2:           "env2",
 				actions.OptionComponentNames: []string{},
+				actions.OptionOutput:         "",
+			},
+		},
+		{
+			name:   "structural diff",
+			args:   []string{"diff", "env1", "-o", "summary"},
+			action: actionDiff,
+			expected: map[string]interface{}{
+				actions.OptionApp:            nil,
+				actions.OptionClientConfig:   nil,
+				actions.OptionSrc1:           "env1",
+				actions.OptionComponentNames: []string{},
+				actions.OptionOutput:         "summary",
 			},
 		},
 		{
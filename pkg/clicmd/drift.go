@@ -0,0 +1,100 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vDriftComponentNames = "drift-component-names"
+	vDriftOutput         = "drift-output"
+
+	driftShortDesc = "Report changes made to an environment's objects outside of ksonnet"
+	driftLong      = `
+The ` + "`drift`" + ` command reports changes made to an environment's objects after they
+were applied, for example with ` + "`kubectl edit`" + ` or ` + "`kubectl scale`" + `.
+
+When ` + "`ks apply`" + ` applies an object, it records a copy of the object in the
+object's ` + "`ksonnet.io/managed`" + ` annotation. ` + "`ks drift`" + ` compares that copy with
+the live object and lists the fields which no longer match. Only fields set by
+the app are compared, so fields defaulted by the server, list items added by the
+server such as injected volumes or sidecar containers, and the object's status
+are not reported. Objects which the app renders but which no longer exist in the
+cluster are listed separately. With JSON or CSV output, changed and deleted objects
+are reported in a single document, and each row has a ` + "`state`" + ` of ` + "`changed`" + ` or
+` + "`deleted`" + `.
+
+` + "`ks drift`" + ` exits with status 10 if any object has drifted.
+
+### Related Commands
+
+* ` + "`ks diff` " + `— ` + diffShortDesc + `
+* ` + "`ks apply` " + `— ` + applyShortDesc + `
+
+### Syntax
+`
+	driftExample = `
+# Report objects in the 'dev' environment which were changed or deleted outside
+# of ksonnet.
+ks drift dev
+
+# Only check the objects of the 'guestbook' component.
+ks drift dev -c guestbook
+
+# Report drift as JSON.
+ks drift dev -o json
+`
+)
+
+func newDriftCmd(a app.App) *cobra.Command {
+	driftClientConfig := client.NewDefaultClientConfig(a)
+
+	driftCmd := &cobra.Command{
+		Use:     "drift [env-name]",
+		Short:   driftShortDesc,
+		Long:    driftLong,
+		Example: driftExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var envName string
+			if len(args) == 1 {
+				envName = args[0]
+			}
+
+			m := map[string]interface{}{
+				actions.OptionApp:            a,
+				actions.OptionClientConfig:   driftClientConfig,
+				actions.OptionComponentNames: viper.GetStringSlice(vDriftComponentNames),
+				actions.OptionEnvName:        envName,
+				actions.OptionOutput:         viper.GetString(vDriftOutput),
+			}
+
+			return runAction(actionDrift, m)
+		},
+	}
+
+	driftClientConfig.BindClientGoFlags(driftCmd)
+	addCmdOutput(driftCmd, vDriftOutput)
+
+	driftCmd.Flags().StringSliceP(flagComponent, shortComponent, nil, "Name of a specific component")
+	viper.BindPFlag(vDriftComponentNames, driftCmd.Flags().Lookup(flagComponent))
+
+	return driftCmd
+}
@@ -0,0 +1,55 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+)
+
+func Test_driftCmd(t *testing.T) {
+	cases := []cmdTestCase{
+		{
+			name:   "with no options",
+			args:   []string{"drift", "default"},
+			action: actionDrift,
+			expected: map[string]interface{}{
+				actions.OptionApp:            mock.AnythingOfType("*app.App"),
+				actions.OptionClientConfig:   mock.AnythingOfType("*client.Config"),
+				actions.OptionComponentNames: []string{},
+				actions.OptionEnvName:        "default",
+				actions.OptionOutput:         "",
+			},
+		},
+		{
+			name:   "with components and json output",
+			args:   []string{"drift", "default", "-c", "guestbook", "-o", "json"},
+			action: actionDrift,
+			expected: map[string]interface{}{
+				actions.OptionApp:            mock.AnythingOfType("*app.App"),
+				actions.OptionClientConfig:   mock.AnythingOfType("*client.Config"),
+				actions.OptionComponentNames: []string{"guestbook"},
+				actions.OptionEnvName:        "default",
+				actions.OptionOutput:         "json",
+			},
+		},
+	}
+
+	runTestCmd(t, cases)
+}
@@ -26,10 +26,13 @@ const (
 	flagAPISpec               = "api-spec"
 	flagAsString              = "as-string"
 	flagComponent             = "component"
+	flagConcurrency           = "concurrency"
 	flagCreate                = "create"
 	flagDir                   = "dir"
+	flagDiscovery             = "discovery"
 	flagDryRun                = "dry-run"
 	flagEnv                   = "env"
+	flagExplain               = "explain"
 	flagExtVar                = "ext-str"
 	flagExtVarFile            = "ext-str-file"
 	flagFilename              = "filename"
@@ -39,28 +42,41 @@ const (
 	flagGracePeriod           = "grace-period"
 	flagInstalled             = "installed"
 	flagJpath                 = "jpath"
+	flagKind                  = "kind"
+	flagMatrix                = "matrix"
 	flagModule                = "module"
 	flagNamespace             = "namespace"
 	flagResolveImage          = "resolve-image"
+	flagSchemaDir             = "schema-dir"
+	flagSecret                = "secret"
+	flagSelector              = "selector"
 	flagServer                = "server"
 	flagSet                   = "set"
 	flagSkipDefaultRegistries = "skip-default-registries"
 	flagSkipGc                = "skip-gc"
+	flagTimeout               = "timeout"
 	flagTlaVar                = "tla-str"
 	flagTlaVarFile            = "tla-str-file"
 	flagTLSSkipVerify         = "tls-skip-verify"
+	flagOrphan                = "orphan"
 	flagOutput                = "output"
+	flagOutputDir             = "output-dir"
 	flagOverride              = "override"
 	flagUnset                 = "unset"
 	flagVerbose               = "verbose"
 	flagVersion               = "version"
+	flagWait                  = "wait"
+	flagWatch                 = "watch"
 	flagWithoutModules        = "without-modules"
+	flagYes                   = "yes"
 
 	shortComponent = "c"
 	shortFilename  = "f"
 	shortFormat    = "o"
 	shortOutput    = "o"
 	shortOverride  = "o"
+	shortSelector  = "l"
+	shortWatch     = "w"
 )
 
 // addCmdOutput adds an output flag to a command. `name` is the name
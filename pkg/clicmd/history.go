@@ -0,0 +1,86 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vHistoryOutput = "history-output"
+
+	historyShortDesc = "List the revisions applied to an environment"
+	historyLong      = `
+The ` + "`history`" + ` command lists the revisions applied to an environment, oldest
+first. Every time ` + "`ks apply`" + ` applies all of the components in an environment,
+the applied objects are recorded in the cluster as a new revision. Hooks are not
+recorded. Applying a subset of the components with ` + "`--component`" + ` does not
+record a revision.
+
+Revisions are stored in the ` + "`ksonnet-history.<env-name>`" + ` secret in the
+environment's namespace. The latest 10 revisions are kept, as long as they fit in
+the secret. Older revisions are removed to make room for new ones.
+
+### Related Commands
+
+* ` + "`ks rollback` " + `— ` + rollbackShortDesc + `
+* ` + "`ks apply` " + `— ` + applyShortDesc + `
+
+### Syntax
+`
+	historyExample = `
+# List the revisions applied to the 'dev' environment.
+ks history dev
+
+# List the revisions as JSON.
+ks history dev -o json
+`
+)
+
+func newHistoryCmd(a app.App) *cobra.Command {
+	historyClientConfig := client.NewDefaultClientConfig(a)
+
+	historyCmd := &cobra.Command{
+		Use:     "history [env-name]",
+		Short:   historyShortDesc,
+		Long:    historyLong,
+		Example: historyExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var envName string
+			if len(args) == 1 {
+				envName = args[0]
+			}
+
+			m := map[string]interface{}{
+				actions.OptionApp:          a,
+				actions.OptionClientConfig: historyClientConfig,
+				actions.OptionEnvName:      envName,
+				actions.OptionOutput:       viper.GetString(vHistoryOutput),
+			}
+
+			return runAction(actionHistory, m)
+		},
+	}
+
+	historyClientConfig.BindClientGoFlags(historyCmd)
+	addCmdOutput(historyCmd, vHistoryOutput)
+
+	return historyCmd
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vLintModule = "lint-module"
+	vLintOutput = "lint-output"
+
+	lintShortDesc = "Check component sources for unused and undefined params"
+)
+
+var (
+	lintLong = `
+The ` + "`lint`" + ` command checks the Jsonnet sources of components for mistakes that
+would otherwise only surface when they are rendered. It reports:
+
+* params defined in a module's ` + "`params.libsonnet`" + ` that no component references
+* params for components which do not exist
+* references to component params that are not defined in the module's
+  ` + "`params.libsonnet`" + `, either for the component or globally
+* component names used by more than one file in a module, e.g.
+  ` + "`redis.jsonnet`" + ` and ` + "`redis.yaml`" + `
+
+Params are found through ` + "`std.extVar(\"__ksonnet/params\").components.<name>`" + `, or a
+local bound to it. Params of a component that are used other than by name, e.g. by
+passing them to a function, are not reported as unused. Params only set in an
+environment's ` + "`params.libsonnet`" + ` are not considered defined.
+
+The command fails if any problems are found.
+
+### Related Commands
+
+* ` + "`ks validate` " + `— ` + valShortDesc + `
+* ` + "`ks param list` " + `— ` + paramShortDesc["list"] + `
+
+### Syntax
+`
+	lintExample = `
+# Lint the components of every module.
+ks lint
+
+# Lint the components of the 'nested' module.
+ks lint --module nested
+`
+)
+
+func newLintCmd(a app.App) *cobra.Command {
+	lintCmd := &cobra.Command{
+		Use:     "lint",
+		Short:   lintShortDesc,
+		Long:    lintLong,
+		Example: lintExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("'lint' takes zero arguments")
+			}
+
+			m := map[string]interface{}{
+				actions.OptionApp:    a,
+				actions.OptionModule: viper.GetString(vLintModule),
+				actions.OptionOutput: viper.GetString(vLintOutput),
+			}
+
+			return runAction(actionLint, m)
+		},
+	}
+
+	addCmdOutput(lintCmd, vLintOutput)
+	lintCmd.Flags().String(flagModule, "", "Component module")
+	viper.BindPFlag(vLintModule, lintCmd.Flags().Lookup(flagModule))
+
+	return lintCmd
+}
@@ -26,6 +26,8 @@ var (
 		"set":    "Change component or environment parameters (e.g. replica count, name)",
 		"list":   "List known component parameters",
 		"diff":   "Display differences between the component parameters of two environments",
+		"export": "Export the component parameters of an environment as YAML or JSON",
+		"import": "Set the component parameters of an environment from a YAML or JSON file",
 	}
 	paramLong = `
 Parameters are customizable fields that are used inside ksonnet *component*
@@ -67,6 +69,8 @@ func newParamCmd(a app.App) *cobra.Command {
 
 	paramCmd.AddCommand(newParamDeleteCmd(a))
 	paramCmd.AddCommand(newParamDiffCmd(a))
+	paramCmd.AddCommand(newParamExportCmd(a))
+	paramCmd.AddCommand(newParamImportCmd(a))
 	paramCmd.AddCommand(newParamListCmd(a))
 	paramCmd.AddCommand(newParamSetCmd(a))
 
@@ -26,6 +26,7 @@ import (
 
 const (
 	vParamDiffComponent = "param-diff-component"
+	vParamDiffMatrix    = "param-diff-matrix"
 	vParamDiffOutput    = "param-diff-output"
 )
 
@@ -37,6 +38,13 @@ of two environments.
 By default, the diff is performed for all components. Diff-ing for a single component
 is supported via a component flag.
 
+To audit params across many environments at once, use the ` + "`--matrix`" + ` flag. It
+prints a row for each component param with a column for every environment given
+(or every environment in the app if none are given). The status column marks params
+which differ between environments or are missing from some of them. The matrix can
+be printed as a table, JSON, or CSV. In JSON, each row holds its values in a
+` + "`values`" + ` object keyed by environment name, with ` + "`null`" + ` for missing params.
+
 ### Related Commands
 
 * ` + "`ks param set` " + `— ` + paramShortDesc["set"] + `
@@ -50,16 +58,34 @@ ks param diff dev prod
 
 # Diff only between the parameters for the 'guestbook' component for environments
 # 'dev' and 'prod'
-ks param diff dev prod --component=guestbook`
+ks param diff dev prod --component=guestbook
+
+# Compare the parameters of every environment in the app, as CSV
+ks param diff --matrix -o csv
+
+# Compare the parameters of the 'dev', 'staging' and 'prod' environments
+ks param diff --matrix dev staging prod`
 )
 
 func newParamDiffCmd(a app.App) *cobra.Command {
 	paramDiffCmd := &cobra.Command{
-		Use:     "diff <env1> <env2> [--component <component-name>]",
+		Use:     "diff <env1> <env2> [--component <component-name>] | --matrix [<env>...]",
 		Short:   paramShortDesc["diff"],
 		Long:    paramDiffLong,
 		Example: paramDiffExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if viper.GetBool(vParamDiffMatrix) {
+				m := map[string]interface{}{
+					actions.OptionApp:           a,
+					actions.OptionEnvNames:      args,
+					actions.OptionMatrix:        true,
+					actions.OptionComponentName: viper.GetString(vParamDiffComponent),
+					actions.OptionOutput:        viper.GetString(vParamDiffOutput),
+				}
+
+				return runAction(actionParamDiff, m)
+			}
+
 			if len(args) != 2 {
 				return fmt.Errorf("'param diff' takes exactly two arguments: the respective names of the environments being diffed")
 			}
@@ -68,6 +94,7 @@ func newParamDiffCmd(a app.App) *cobra.Command {
 				actions.OptionApp:           a,
 				actions.OptionEnvName1:      args[0],
 				actions.OptionEnvName2:      args[1],
+				actions.OptionMatrix:        false,
 				actions.OptionComponentName: viper.GetString(vParamDiffComponent),
 				actions.OptionOutput:        viper.GetString(vParamDiffOutput),
 			}
@@ -76,7 +103,10 @@ func newParamDiffCmd(a app.App) *cobra.Command {
 		},
 	}
 
-	addCmdOutput(paramDiffCmd, vParamDiffOutput)
+	paramDiffCmd.Flags().StringP(flagOutput, shortOutput, "", "Output format. Valid options: table|json|csv")
+	viper.BindPFlag(vParamDiffOutput, paramDiffCmd.Flags().Lookup(flagOutput))
+	paramDiffCmd.Flags().Bool(flagMatrix, false, "Compare params across several environments")
+	viper.BindPFlag(vParamDiffMatrix, paramDiffCmd.Flags().Lookup(flagMatrix))
 	paramDiffCmd.Flags().String(flagComponent, "", "Specify the component to diff against")
 	viper.BindPFlag(vParamDiffComponent, paramDiffCmd.Flags().Lookup(flagComponent))
 
@@ -32,6 +32,7 @@ func Test_paramDiffCmd(t *testing.T) {
 				actions.OptionComponentName: "component-name",
 				actions.OptionEnvName1:      "env1",
 				actions.OptionEnvName2:      "env2",
+				actions.OptionMatrix:        false,
 				actions.OptionOutput:        "",
 			},
 		},
@@ -43,6 +44,7 @@ func Test_paramDiffCmd(t *testing.T) {
 				actions.OptionApp:           nil,
 				actions.OptionEnvName1:      "env1",
 				actions.OptionEnvName2:      "env2",
+				actions.OptionMatrix:        false,
 				actions.OptionComponentName: "",
 				actions.OptionOutput:        "",
 			},
@@ -55,10 +57,35 @@ func Test_paramDiffCmd(t *testing.T) {
 				actions.OptionApp:           nil,
 				actions.OptionEnvName1:      "env1",
 				actions.OptionEnvName2:      "env2",
+				actions.OptionMatrix:        false,
 				actions.OptionComponentName: "",
 				actions.OptionOutput:        "json",
 			},
 		},
+		{
+			name:   "matrix",
+			args:   []string{"param", "diff", "--matrix", "env1", "env2", "env3", "-o", "csv"},
+			action: actionParamDiff,
+			expected: map[string]interface{}{
+				actions.OptionApp:           nil,
+				actions.OptionEnvNames:      []string{"env1", "env2", "env3"},
+				actions.OptionMatrix:        true,
+				actions.OptionComponentName: "",
+				actions.OptionOutput:        "csv",
+			},
+		},
+		{
+			name:   "matrix of all environments",
+			args:   []string{"param", "diff", "--matrix"},
+			action: actionParamDiff,
+			expected: map[string]interface{}{
+				actions.OptionApp:           nil,
+				actions.OptionEnvNames:      []string{},
+				actions.OptionMatrix:        true,
+				actions.OptionComponentName: "",
+				actions.OptionOutput:        "",
+			},
+		},
 		{
 			name:  "invalid args",
 			args:  []string{"param", "diff"},
@@ -0,0 +1,83 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vParamImportEnv = "param-import-env"
+)
+
+var (
+	paramImportLong = `
+The ` + "`import`" + ` command sets the parameters of components in an environment from a
+YAML or JSON document, like the one created by ` + "`ks param export`" + `. The document maps
+component names to parameter names and values. Use ` + "`-`" + ` as the file name to read the
+document from standard input.
+
+Only parameters whose values differ from the ones the environment sets are set.
+They are written to ` + "`environments/:name/params.libsonnet`" + `, keeping its comments and
+formatting. Every value is checked against the component's parameter schema before
+any file is changed.
+
+### Related Commands
+
+* ` + "`ks param export` " + `— ` + paramShortDesc["export"] + `
+* ` + "`ks param set` " + `— ` + paramShortDesc["set"] + `
+
+### Syntax
+`
+	paramImportExample = `
+# Set the parameters of the environment 'prod' from params.yaml
+ks param import --env prod params.yaml
+
+# Copy the parameters of the environment 'dev' to 'staging'
+ks param export --env dev | ks param import --env staging -`
+)
+
+func newParamImportCmd(a app.App) *cobra.Command {
+	paramImportCmd := &cobra.Command{
+		Use:     "import <file> [--env <env-name>]",
+		Short:   paramShortDesc["import"],
+		Long:    paramImportLong,
+		Example: paramImportExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("'param import' takes exactly one argument, that is the file to import")
+			}
+
+			m := map[string]interface{}{
+				actions.OptionApp:      a,
+				actions.OptionEnvName:  viper.GetString(vParamImportEnv),
+				actions.OptionFilename: args[0],
+			}
+
+			return runAction(actionParamImport, m)
+		},
+	}
+
+	paramImportCmd.Flags().String(flagEnv, "", "Environment to import parameters into")
+	viper.BindPFlag(vParamImportEnv, paramImportCmd.Flags().Lookup(flagEnv))
+
+	return paramImportCmd
+}
@@ -25,6 +25,7 @@ import (
 )
 
 const (
+	vParamListExplain        = "param-list-explain"
 	vParamListOutput         = "param-list-output"
 	vParamListWithoutModules = "param-without-modules"
 )
@@ -37,6 +38,17 @@ If a component is specified, this command displays all of its specific parameter
 If a component is NOT specified, parameters for **all** components are listed.
 Furthermore, parameters can be listed on a per-environment basis.
 
+With ` + "`--explain`" + `, environment parameters are listed with every layer that defines
+them, from lowest to highest precedence: module params, module globals, environment
+params, environment globals, destination params and secrets. Each layer shows the
+file and line that defines the parameter, and the layer whose value wins is marked.
+Layers are found in the Jsonnet source, so values set by computed fields or imports
+are not attributed to a layer.
+
+Environment parameters are checked against the parameter schemas that ` + "`ks generate`" + `
+records in ` + "`params.schema.libsonnet`" + `. Parameters which are missing or invalid are
+reported after the list.
+
 ### Related Commands
 
 * ` + "`ks param set` " + `— ` + paramShortDesc["set"] + `
@@ -54,7 +66,10 @@ ks param list guestbook
 ks param list --env=dev
 
 # List all parameters for the component "guestbook" in the environment "dev"
-ks param list guestbook --env=dev`
+ks param list guestbook --env=dev
+
+# Show which files set the parameters of "guestbook" in the environment "dev"
+ks param list guestbook --env=dev --explain`
 )
 
 func newParamListCmd(a app.App) *cobra.Command {
@@ -88,6 +103,7 @@ func newParamListCmd(a app.App) *cobra.Command {
 				actions.OptionApp:            a,
 				actions.OptionComponentName:  component,
 				actions.OptionEnvName:        env,
+				actions.OptionExplain:        viper.GetBool(vParamListExplain),
 				actions.OptionModule:         module,
 				actions.OptionOutput:         viper.GetString(vParamListOutput),
 				actions.OptionWithoutModules: viper.GetBool(vParamListWithoutModules),
@@ -104,6 +120,9 @@ func newParamListCmd(a app.App) *cobra.Command {
 	paramListCmd.Flags().Bool(flagWithoutModules, false, "Exclude module defaults")
 	viper.BindPFlag(vParamListWithoutModules, paramListCmd.Flags().Lookup(flagWithoutModules))
 
+	paramListCmd.Flags().Bool(flagExplain, false, "Show the layers that define each parameter; requires --env")
+	viper.BindPFlag(vParamListExplain, paramListCmd.Flags().Lookup(flagExplain))
+
 	return paramListCmd
 
 }
@@ -30,6 +30,7 @@ func Test_paramListCmd(t *testing.T) {
 			expected: map[string]interface{}{
 				actions.OptionApp:            nil,
 				actions.OptionEnvName:        "",
+				actions.OptionExplain:        false,
 				actions.OptionModule:         "",
 				actions.OptionComponentName:  "",
 				actions.OptionOutput:         "",
@@ -43,6 +44,7 @@ func Test_paramListCmd(t *testing.T) {
 			expected: map[string]interface{}{
 				actions.OptionApp:            nil,
 				actions.OptionEnvName:        "",
+				actions.OptionExplain:        false,
 				actions.OptionModule:         "",
 				actions.OptionComponentName:  "",
 				actions.OptionOutput:         "json",
@@ -56,6 +58,7 @@ func Test_paramListCmd(t *testing.T) {
 			expected: map[string]interface{}{
 				actions.OptionApp:            nil,
 				actions.OptionEnvName:        "",
+				actions.OptionExplain:        false,
 				actions.OptionModule:         "",
 				actions.OptionComponentName:  "component",
 				actions.OptionOutput:         "",
@@ -69,6 +72,7 @@ func Test_paramListCmd(t *testing.T) {
 			expected: map[string]interface{}{
 				actions.OptionApp:            nil,
 				actions.OptionEnvName:        "",
+				actions.OptionExplain:        false,
 				actions.OptionModule:         "module",
 				actions.OptionComponentName:  "",
 				actions.OptionOutput:         "",
@@ -82,6 +86,7 @@ func Test_paramListCmd(t *testing.T) {
 			expected: map[string]interface{}{
 				actions.OptionApp:            nil,
 				actions.OptionEnvName:        "env",
+				actions.OptionExplain:        false,
 				actions.OptionModule:         "",
 				actions.OptionComponentName:  "",
 				actions.OptionOutput:         "",
@@ -95,12 +100,27 @@ func Test_paramListCmd(t *testing.T) {
 			expected: map[string]interface{}{
 				actions.OptionApp:            nil,
 				actions.OptionEnvName:        "env",
+				actions.OptionExplain:        false,
 				actions.OptionModule:         "",
 				actions.OptionComponentName:  "",
 				actions.OptionOutput:         "",
 				actions.OptionWithoutModules: true,
 			},
 		},
+		{
+			name:   "explain",
+			args:   []string{"param", "list", "--env", "env", "--explain"},
+			action: actionParamList,
+			expected: map[string]interface{}{
+				actions.OptionApp:            nil,
+				actions.OptionEnvName:        "env",
+				actions.OptionExplain:        true,
+				actions.OptionModule:         "",
+				actions.OptionComponentName:  "",
+				actions.OptionOutput:         "",
+				actions.OptionWithoutModules: false,
+			},
+		},
 	}
 
 	runTestCmd(t, cases)
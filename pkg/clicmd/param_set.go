@@ -27,6 +27,7 @@ var (
 	vParamSetEnv          = "param-set-env"
 	vParamSetAsString     = "param-set-as-string"
 	vParamSetResolveImage = "param-set-resolve-image"
+	vParamSetSecret       = "param-set-secret"
 
 	paramSetLong = `
 The ` + "`set`" + ` command sets component or environment parameters such as replica count
@@ -35,6 +36,20 @@ reflected in the ` + "`params.libsonnet`" + ` files.
 
 For more details on how parameters are organized, see ` + "`ks param --help`" + `.
 
+Secret parameters, such as passwords, are set with ` + "`--secret`" + `. They are
+encrypted into ` + "`environments/:name/secrets.json`" + ` instead of ` + "`params.libsonnet`" + `,
+and are decrypted when the environment is rendered. Secret values are always
+strings, and ` + "`ks param list`" + ` masks them. By default, values are encrypted with
+a local key file, ` + "`~/.config/ksonnet/secret.key`" + `, which is generated when the first
+secret is set and must be shared with everyone who renders the environment. Set
+` + "`KS_SECRET_KEY_FILE`" + ` to use another key file, or ` + "`KS_SECRET_PROVIDER`" + ` to select
+another key provider. Each value is bound to its environment, component and parameter,
+so it can not be copied to another parameter in ` + "`secrets.json`" + `.
+
+Values of component parameters are checked against the parameter schema that
+` + "`ks generate`" + ` records from the component's prototype in ` + "`params.schema.libsonnet`" + `.
+Values with the wrong type, or which are not in the schema's ` + "`enum`" + `, are rejected.
+
 *(If you need to customize multiple parameters at once, we suggest that you modify
 your ksonnet application's ` + " `components/params.libsonnet` " + `file directly. Likewise,
 for greater customization of environment parameters, we suggest modifying the
@@ -53,7 +68,10 @@ ks param set guestbook replicas 4
 
 # Update the replica count of the 'guestbook' component to 2, but only for the
 # 'dev' environment
-ks param set guestbook replicas 2 --env=dev`
+ks param set guestbook replicas 2 --env=dev
+
+# Set an encrypted password for the 'guestbook' component in the 'prod' environment
+ks param set guestbook password hunter2 --env=prod --secret`
 )
 
 func newParamSetCmd(a app.App) *cobra.Command {
@@ -87,6 +105,7 @@ func newParamSetCmd(a app.App) *cobra.Command {
 				actions.OptionEnvName:      viper.GetString(vParamSetEnv),
 				actions.OptionAsString:     viper.GetBool(vParamSetAsString),
 				actions.OptionResolveImage: viper.GetBool(vParamSetResolveImage),
+				actions.OptionSecret:       viper.GetBool(vParamSetSecret),
 			}
 
 			return runAction(actionParamSet, m)
@@ -102,5 +121,8 @@ func newParamSetCmd(a app.App) *cobra.Command {
 	paramSetCmd.Flags().Bool(flagResolveImage, false, "Resolve Docker image tag to reference")
 	viper.BindPFlag(vParamSetResolveImage, paramSetCmd.Flags().Lookup(flagResolveImage))
 
+	paramSetCmd.Flags().Bool(flagSecret, false, "Encrypt the value as a secret param; requires --env")
+	viper.BindPFlag(vParamSetSecret, paramSetCmd.Flags().Lookup(flagSecret))
+
 	return paramSetCmd
 }
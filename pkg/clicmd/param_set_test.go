@@ -35,6 +35,7 @@ func Test_paramSetCmd(t *testing.T) {
 				actions.OptionEnvName:      "",
 				actions.OptionAsString:     false,
 				actions.OptionResolveImage: false,
+				actions.OptionSecret:       false,
 			},
 		},
 		{
@@ -49,6 +50,7 @@ func Test_paramSetCmd(t *testing.T) {
 				actions.OptionEnvName:      "",
 				actions.OptionAsString:     false,
 				actions.OptionResolveImage: true,
+				actions.OptionSecret:       false,
 			},
 		},
 
@@ -64,6 +66,7 @@ func Test_paramSetCmd(t *testing.T) {
 				actions.OptionEnvName:      "default",
 				actions.OptionAsString:     false,
 				actions.OptionResolveImage: false,
+				actions.OptionSecret:       false,
 			},
 		},
 		{
@@ -84,6 +87,22 @@ func Test_paramSetCmd(t *testing.T) {
 				actions.OptionEnvName:      "",
 				actions.OptionAsString:     true,
 				actions.OptionResolveImage: false,
+				actions.OptionSecret:       false,
+			},
+		},
+		{
+			name:   "secret",
+			args:   []string{"param", "set", "component-name", "param-name", "param-value", "--env", "default", "--secret"},
+			action: actionParamSet,
+			expected: map[string]interface{}{
+				actions.OptionApp:          nil,
+				actions.OptionName:         "component-name",
+				actions.OptionPath:         "param-name",
+				actions.OptionValue:        "param-value",
+				actions.OptionEnvName:      "default",
+				actions.OptionAsString:     false,
+				actions.OptionResolveImage: false,
+				actions.OptionSecret:       true,
 			},
 		},
 	}
@@ -0,0 +1,108 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vPruneDryRun = "prune-dry-run"
+	vPruneGcTag  = "prune-gc-tag"
+	vPruneYes    = "prune-yes"
+
+	pruneShortDesc = "Remove garbage collected objects which are no longer part of an environment"
+	pruneLong      = `
+The ` + "`prune`" + ` command removes objects from a cluster which were previously
+applied with ` + "`--gc-tag`" + `, but are no longer rendered by the environment. This
+is useful for cleaning up after renaming or removing components without having to
+apply the whole environment.
+
+Candidate objects are listed before anything is removed, and you are asked to
+confirm. If stdin is not a terminal, ` + "`--yes`" + ` is required. Objects with the
+` + "`kubecfg.ksonnet.io/garbage-collect-strategy: ignore`" + ` annotation are listed, but
+are never removed.
+
+### Related Commands
+
+* ` + "`ks apply` " + `— ` + applyShortDesc + `
+* ` + "`ks delete` " + `— ` + deleteShortDesc + `
+
+### Syntax
+`
+	pruneExample = `
+# List the objects in the 'dev' environment which are tagged with 'dev-gc' but are
+# no longer part of the app, without removing them.
+ks prune dev --gc-tag dev-gc --dry-run
+
+# Remove the objects after confirming.
+ks prune dev --gc-tag dev-gc
+
+# Remove the objects without asking for confirmation.
+ks prune dev --gc-tag dev-gc --yes
+`
+)
+
+func newPruneCmd(a app.App) *cobra.Command {
+	pruneClientConfig := client.NewDefaultClientConfig(a)
+
+	pruneCmd := &cobra.Command{
+		Use:     "prune <env-name> --gc-tag <tag> [--dry-run]",
+		Short:   pruneShortDesc,
+		Long:    pruneLong,
+		Example: pruneExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var envName string
+			if len(args) == 1 {
+				envName = args[0]
+			}
+
+			m := map[string]interface{}{
+				actions.OptionApp:          a,
+				actions.OptionClientConfig: pruneClientConfig,
+				actions.OptionDryRun:       viper.GetBool(vPruneDryRun),
+				actions.OptionEnvName:      envName,
+				actions.OptionGcTag:        viper.GetString(vPruneGcTag),
+				actions.OptionSkipConfirm:  viper.GetBool(vPruneYes),
+			}
+
+			if err := extractJsonnetFlags(a, "prune"); err != nil {
+				return errors.Wrap(err, "handle jsonnet flags")
+			}
+
+			return runAction(actionPrune, m)
+		},
+	}
+
+	pruneClientConfig.BindClientGoFlags(pruneCmd)
+	bindJsonnetFlags(pruneCmd, "prune")
+
+	pruneCmd.Flags().String(flagGcTag, "", "The tag objects were applied with using --"+flagGcTag)
+	viper.BindPFlag(vPruneGcTag, pruneCmd.Flags().Lookup(flagGcTag))
+
+	pruneCmd.Flags().Bool(flagDryRun, false, "Option to list the objects which would be removed without changing the cluster state")
+	viper.BindPFlag(vPruneDryRun, pruneCmd.Flags().Lookup(flagDryRun))
+
+	pruneCmd.Flags().Bool(flagYes, false, "Option to remove objects without asking for confirmation")
+	viper.BindPFlag(vPruneYes, pruneCmd.Flags().Lookup(flagYes))
+
+	return pruneCmd
+}
@@ -0,0 +1,57 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+)
+
+func Test_pruneCmd(t *testing.T) {
+	cases := []cmdTestCase{
+		{
+			name:   "with no options",
+			args:   []string{"prune", "default", "--gc-tag", "gc-tag"},
+			action: actionPrune,
+			expected: map[string]interface{}{
+				actions.OptionApp:          mock.AnythingOfType("*app.App"),
+				actions.OptionClientConfig: mock.AnythingOfType("*client.Config"),
+				actions.OptionDryRun:       false,
+				actions.OptionEnvName:      "default",
+				actions.OptionGcTag:        "gc-tag",
+				actions.OptionSkipConfirm:  false,
+			},
+		},
+		{
+			name:   "dry run",
+			args:   []string{"prune", "default", "--gc-tag", "gc-tag", "--dry-run", "--yes"},
+			action: actionPrune,
+			expected: map[string]interface{}{
+				actions.OptionApp:          mock.AnythingOfType("*app.App"),
+				actions.OptionClientConfig: mock.AnythingOfType("*client.Config"),
+				actions.OptionDryRun:       true,
+				actions.OptionEnvName:      "default",
+				actions.OptionGcTag:        "gc-tag",
+				actions.OptionSkipConfirm:  true,
+			},
+		},
+	}
+
+	runTestCmd(t, cases)
+}
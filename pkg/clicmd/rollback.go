@@ -0,0 +1,89 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"strconv"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vRollbackOutput = "rollback-output"
+
+	rollbackShortDesc = "Roll back an environment to a previously applied revision"
+	rollbackLong      = `
+The ` + "`rollback`" + ` command re-applies the objects recorded in a previous revision
+of an environment. Objects in the latest revision which are not part of the previous
+revision are removed from the cluster. The objects are applied as they were recorded,
+so the app's components are not rendered.
+
+The rollback is recorded as a new revision. Use ` + "`ks history`" + ` to list the
+revisions of an environment.
+
+### Related Commands
+
+* ` + "`ks history` " + `— ` + historyShortDesc + `
+* ` + "`ks apply` " + `— ` + applyShortDesc + `
+
+### Syntax
+`
+	rollbackExample = `
+# Roll back the 'dev' environment to revision 3.
+ks rollback dev 3
+`
+)
+
+func newRollbackCmd(a app.App) *cobra.Command {
+	rollbackClientConfig := client.NewDefaultClientConfig(a)
+
+	rollbackCmd := &cobra.Command{
+		Use:     "rollback <env-name> <revision>",
+		Short:   rollbackShortDesc,
+		Long:    rollbackLong,
+		Example: rollbackExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("rollback requires an environment name and a revision")
+			}
+
+			revision, err := strconv.Atoi(args[1])
+			if err != nil {
+				return errors.Errorf("invalid revision %q", args[1])
+			}
+
+			m := map[string]interface{}{
+				actions.OptionApp:          a,
+				actions.OptionClientConfig: rollbackClientConfig,
+				actions.OptionEnvName:      args[0],
+				actions.OptionOutput:       viper.GetString(vRollbackOutput),
+				actions.OptionRevision:     revision,
+			}
+
+			return runAction(actionRollback, m)
+		},
+	}
+
+	rollbackClientConfig.BindClientGoFlags(rollbackCmd)
+	addCmdOutput(rollbackCmd, vRollbackOutput)
+
+	return rollbackCmd
+}
@@ -0,0 +1,53 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+)
+
+func Test_rollbackCmd(t *testing.T) {
+	cases := []cmdTestCase{
+		{
+			name:   "with a revision",
+			args:   []string{"rollback", "default", "3"},
+			action: actionRollback,
+			expected: map[string]interface{}{
+				actions.OptionApp:          mock.AnythingOfType("*app.App"),
+				actions.OptionClientConfig: mock.AnythingOfType("*client.Config"),
+				actions.OptionEnvName:      "default",
+				actions.OptionOutput:       "",
+				actions.OptionRevision:     3,
+			},
+		},
+		{
+			name:  "without a revision",
+			args:  []string{"rollback", "default"},
+			isErr: true,
+		},
+		{
+			name:  "with an invalid revision",
+			args:  []string{"rollback", "default", "latest"},
+			isErr: true,
+		},
+	}
+
+	runTestCmd(t, cases)
+}
@@ -214,16 +214,22 @@ func NewRoot(appFs afero.Fs, wd string, args []string) (*cobra.Command, error) {
 	rootCmd.AddCommand(newComponentCmd(a))
 	rootCmd.AddCommand(newDeleteCmd(a))
 	rootCmd.AddCommand(newDiffCmd(a))
+	rootCmd.AddCommand(newDriftCmd(a))
 	rootCmd.AddCommand(newEnvCmd(a))
 	rootCmd.AddCommand(newGenerateCmd(a))
+	rootCmd.AddCommand(newHistoryCmd(a))
 	rootCmd.AddCommand(newImportCmd(a))
 	rootCmd.AddCommand(newInitCmd(appFs, wd))
+	rootCmd.AddCommand(newLintCmd(a))
 	rootCmd.AddCommand(newModuleCmd(a))
 	rootCmd.AddCommand(newParamCmd(a))
 	rootCmd.AddCommand(newPkgCmd(a))
 	rootCmd.AddCommand(newPrototypeCmd(a))
+	rootCmd.AddCommand(newPruneCmd(a))
 	rootCmd.AddCommand(newRegistryCmd(a))
+	rootCmd.AddCommand(newRollbackCmd(a))
 	rootCmd.AddCommand(newShowCmd(a))
+	rootCmd.AddCommand(newStatusCmd(a))
 	rootCmd.AddCommand(newValidateCmd(a))
 	rootCmd.AddCommand(newUpgradeCmd(a))
 	rootCmd.AddCommand(newVersionCmd())
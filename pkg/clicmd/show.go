@@ -27,6 +27,7 @@ const (
 	showShortDesc  = "Show expanded manifests for a specific environment."
 	vShowComponent = "show-components"
 	vShowFormat    = "show-format"
+	vShowOutputDir = "show-output-dir"
 )
 
 var (
@@ -44,6 +45,20 @@ This is the YAML version of what gets deployed to your cluster with
 When a component IS specified via the ` + "`-c`" + ` flag, this command only expands the
 manifest for that particular component.
 
+The ` + "`--format`" + ` flag selects how the manifests are written:
+
+* ` + "`yaml`" + ` — a stream of YAML documents (the default)
+* ` + "`json`" + ` — a single JSON object of ` + "`kind: List`" + `
+* ` + "`dir`" + ` — one YAML file per object, named ` + "`<namespace>/<kind>-<name>.yaml`" + `, in
+  the directory given by ` + "`--output-dir`" + `. Objects without a namespace are written
+  to ` + "`_cluster/`" + `. A ` + "`kustomization.yaml`" + ` listing every file is also written,
+  so the directory can be used as a kustomize base.
+* ` + "`components`" + ` — one YAML file per component, named ` + "`<component>.yaml`" + `, in
+  the directory given by ` + "`--output-dir`" + `.
+
+Existing files with the same names are overwritten. Other files in the directory
+are left as they are.
+
 ### Related Commands
 
 * ` + "`ks validate` " + `— ` + valShortDesc + `
@@ -61,6 +76,12 @@ ks show prod -c redis -o json
 
 # Show multiple components from the 'dev' environment, in YAML
 ks show dev -c redis -c nginx-server
+
+# Write one file per object in the 'dev' environment to the 'manifests' directory
+ks show dev -o dir --output-dir manifests
+
+# Write one file per component in the 'dev' environment to the 'manifests' directory
+ks show dev -o components --output-dir manifests
 `
 )
 
@@ -81,6 +102,7 @@ func newShowCmd(a app.App) *cobra.Command {
 				actions.OptionComponentNames: viper.GetStringSlice(vShowComponent),
 				actions.OptionEnvName:        envName,
 				actions.OptionFormat:         viper.GetString(vShowFormat),
+				actions.OptionOutputDir:      viper.GetString(vShowOutputDir),
 			}
 
 			if err := extractJsonnetFlags(a, "show"); err != nil {
@@ -95,8 +117,11 @@ func newShowCmd(a app.App) *cobra.Command {
 	showCmd.Flags().StringSliceP(flagComponent, shortComponent, nil, "Name of a specific component (multiple -c flags accepted, allows YAML, JSON, and Jsonnet)")
 	viper.BindPFlag(vShowComponent, showCmd.Flags().Lookup(flagComponent))
 
-	showCmd.Flags().StringP(flagFormat, shortFormat, "yaml", "Output format.  Supported values are: json, yaml")
+	showCmd.Flags().StringP(flagFormat, shortFormat, "yaml", "Output format.  Supported values are: components, dir, json, yaml")
 	viper.BindPFlag(vShowFormat, showCmd.Flags().Lookup(flagFormat))
 
+	showCmd.Flags().String(flagOutputDir, "", "Directory to write files to for the components and dir formats")
+	viper.BindPFlag(vShowOutputDir, showCmd.Flags().Lookup(flagOutputDir))
+
 	return showCmd
 }
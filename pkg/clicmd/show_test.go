@@ -32,6 +32,19 @@ func Test_showCmd(t *testing.T) {
 				actions.OptionEnvName:        "default",
 				actions.OptionComponentNames: make([]string, 0),
 				actions.OptionFormat:         "yaml",
+				actions.OptionOutputDir:      "",
+			},
+		},
+		{
+			name:   "with an output directory",
+			args:   []string{"show", "default", "-o", "dir", "--output-dir", "manifests"},
+			action: actionShow,
+			expected: map[string]interface{}{
+				actions.OptionApp:            nil,
+				actions.OptionEnvName:        "default",
+				actions.OptionComponentNames: make([]string, 0),
+				actions.OptionFormat:         "dir",
+				actions.OptionOutputDir:      "manifests",
 			},
 		},
 		{
@@ -0,0 +1,103 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vStatusComponentNames = "status-component-names"
+	vStatusOutput         = "status-output"
+	vStatusWatch          = "status-watch"
+
+	statusShortDesc = "Show the status of every object in an environment"
+	statusLong      = `
+The ` + "`status`" + ` command renders the components of an environment and fetches each
+object from the cluster. For every object it shows whether the object exists, whether it
+is ready, and its age.
+
+Readiness uses the same checks as ` + "`ks apply --wait`" + `: deployments, daemon sets
+and stateful sets are ready when they have rolled out, jobs when they complete, services
+when they have endpoints and custom resource definitions when they are established. Other
+objects are ready as soon as they exist.
+
+With ` + "`--watch`" + `, the cluster is checked continuously and the table is printed again
+whenever the status of an object changes. The environment is only rendered once, so
+changes to the app aren't picked up while watching. Errors fetching the status are
+logged, and watching continues.
+
+### Related Commands
+
+* ` + "`ks apply` " + `— ` + applyShortDesc + `
+* ` + "`ks show` " + `— ` + showShortDesc + `
+
+### Syntax
+`
+	statusExample = `
+# Show the status of the objects in the 'dev' environment.
+ks status dev
+
+# Only show the objects of the 'guestbook' component.
+ks status dev -c guestbook
+
+# Watch the objects until interrupted.
+ks status dev --watch
+`
+)
+
+func newStatusCmd(a app.App) *cobra.Command {
+	statusClientConfig := client.NewDefaultClientConfig(a)
+
+	statusCmd := &cobra.Command{
+		Use:     "status [env-name]",
+		Short:   statusShortDesc,
+		Long:    statusLong,
+		Example: statusExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var envName string
+			if len(args) == 1 {
+				envName = args[0]
+			}
+
+			m := map[string]interface{}{
+				actions.OptionApp:            a,
+				actions.OptionClientConfig:   statusClientConfig,
+				actions.OptionComponentNames: viper.GetStringSlice(vStatusComponentNames),
+				actions.OptionEnvName:        envName,
+				actions.OptionOutput:         viper.GetString(vStatusOutput),
+				actions.OptionWatch:          viper.GetBool(vStatusWatch),
+			}
+
+			return runAction(actionStatus, m)
+		},
+	}
+
+	statusClientConfig.BindClientGoFlags(statusCmd)
+	addCmdOutput(statusCmd, vStatusOutput)
+
+	statusCmd.Flags().StringSliceP(flagComponent, shortComponent, nil, "Name of a specific component")
+	viper.BindPFlag(vStatusComponentNames, statusCmd.Flags().Lookup(flagComponent))
+
+	statusCmd.Flags().BoolP(flagWatch, shortWatch, false, "Watch the objects and print their status when it changes")
+	viper.BindPFlag(vStatusWatch, statusCmd.Flags().Lookup(flagWatch))
+
+	return statusCmd
+}
@@ -28,14 +28,35 @@ import (
 
 const (
 	vValidateComponent = "validate-component"
+	vValidateDiscovery = "validate-discovery"
+	vValidateSchemaDir = "validate-schema-dir"
 	valShortDesc       = "Check generated component manifests against the server's API"
 )
 
 var (
 	validateLong = `
 The ` + "`validate`" + ` command checks that an application or file is compliant with the
-server APIs Kubernetes specification. Note that this command actually communicates
-*with* the server for the specified ` + "`<env-name>`" + `, so it only works if your
+server APIs Kubernetes specification. Manifests are checked against the OpenAPI
+(swagger) spec recorded in the app's ` + "`lib/`" + ` directory for the Kubernetes version of
+` + "`<env-name>`" + `, so no cluster is needed, e.g. when validating in an air-gapped CI.
+
+Custom resources are checked against the ` + "`openAPIV3Schema`" + ` validation of their
+CustomResourceDefinitions. CRDs rendered by the app are used to check the custom
+resources rendered with them, and ` + "`--schema-dir`" + ` supplies a directory of YAML or
+JSON files containing more CRDs. A warning is printed for each custom resource
+whose CRD is neither rendered nor (with ` + "`--discovery`" + `) served by the cluster.
+
+Each error names the object and the JSON path of the invalid field, e.g.
+` + "`$.spec.template.spec.containers[0].image`" + `.
+
+Objects are then checked against the policy rules configured in the ` + "`validation`" + `
+section of ` + "`app.yaml`" + `. Built-in rules (` + "`resource-limits`" + `, ` + "`no-latest-tag`" + ` and
+` + "`namespace-matches-env`" + `) and custom rules written in Jsonnet report violations with
+a severity of ` + "`error`" + ` or ` + "`warning`" + `. Violations are listed with their component,
+object, severity and rule, and only errors cause validation to fail.
+
+Use ` + "`--discovery`" + ` to also contact the server for the specified environment, so
+objects are described by their server resource names. This only works if your
 $KUBECONFIG specifies a valid kubeconfig file.
 
 When NO component is specified (no ` + "`-c`" + ` flag), this command checks all of
@@ -53,15 +74,21 @@ the manifest for that particular component.
 ### Syntax
 `
 	validateExample = `
-# Validate all resources described in the ksonnet app, against the server
-# specified by the 'dev' environment.
-# NOTE: Make sure your current $KUBECONFIG matches the 'dev' cluster info
+# Validate all resources described in the ksonnet app, against the Kubernetes
+# version of the 'dev' environment.
 ksonnet validate dev
 
-# Validate resources from the 'redis' component only, against the server specified
-# by the 'prod' environment
-# NOTE: Make sure your current $KUBECONFIG matches the 'prod' cluster info
+# Validate resources from the 'redis' component only, against the Kubernetes
+# version of the 'prod' environment
 ksonnet validate prod -c redis
+
+# Validate custom resources with the CRDs in the 'crds' directory
+ksonnet validate dev --schema-dir crds
+
+# Validate all resources, looking up their resource names with the server specified
+# by the 'dev' environment.
+# NOTE: Make sure your current $KUBECONFIG matches the 'dev' cluster info
+ksonnet validate dev --discovery
 `
 )
 
@@ -85,6 +112,8 @@ func newValidateCmd(a app.App) *cobra.Command {
 				actions.OptionModule:         "",
 				actions.OptionComponentNames: viper.GetStringSlice(vValidateComponent),
 				actions.OptionClientConfig:   validateClientConfig,
+				actions.OptionDiscovery:      viper.GetBool(vValidateDiscovery),
+				actions.OptionSchemaDir:      viper.GetString(vValidateSchemaDir),
 			}
 
 			if err := extractJsonnetFlags(a, "validate"); err != nil {
@@ -101,5 +130,11 @@ func newValidateCmd(a app.App) *cobra.Command {
 
 	viper.BindPFlag(vValidateComponent, validateCmd.Flag(flagComponent))
 
+	validateCmd.Flags().String(flagSchemaDir, "", "Directory of CustomResourceDefinitions used to validate custom resources")
+	viper.BindPFlag(vValidateSchemaDir, validateCmd.Flags().Lookup(flagSchemaDir))
+
+	validateCmd.Flags().Bool(flagDiscovery, false, "Option to look up resources with the server for the environment")
+	viper.BindPFlag(vValidateDiscovery, validateCmd.Flags().Lookup(flagDiscovery))
+
 	return validateCmd
 }
@@ -33,6 +33,22 @@ func Test_validateCmd(t *testing.T) {
 				actions.OptionModule:         "",
 				actions.OptionComponentNames: make([]string, 0),
 				actions.OptionClientConfig:   nil,
+				actions.OptionDiscovery:      false,
+				actions.OptionSchemaDir:      "",
+			},
+		},
+		{
+			name:   "with a schema dir and discovery",
+			args:   []string{"validate", "env-name", "--schema-dir", "crds", "--discovery"},
+			action: actionValidate,
+			expected: map[string]interface{}{
+				actions.OptionApp:            nil,
+				actions.OptionEnvName:        "env-name",
+				actions.OptionModule:         "",
+				actions.OptionComponentNames: make([]string, 0),
+				actions.OptionClientConfig:   nil,
+				actions.OptionDiscovery:      true,
+				actions.OptionSchemaDir:      "crds",
 			},
 		},
 	}
@@ -82,6 +82,23 @@ func NewDefaultClientConfig(a app.App) *Config {
 	return NewClientConfig(a, overrides, loadingRules)
 }
 
+// Copy returns a copy of the config. Environments with several destinations
+// use a copy for each destination, because the overrides are updated with the
+// destination's cluster.
+func (c *Config) Copy() *Config {
+	var overrides clientcmd.ConfigOverrides
+	if c.Overrides != nil {
+		overrides = *c.Overrides
+	}
+
+	var loadingRules clientcmd.ClientConfigLoadingRules
+	if c.LoadingRules != nil {
+		loadingRules = *c.LoadingRules
+	}
+
+	return NewClientConfig(nil, overrides, loadingRules)
+}
+
 // InitClient initializes a new ClientConfig given the specified environment
 // spec and returns the ClientPool, DiscoveryInterface, and namespace.
 func InitClient(a app.App, env string) (dynamic.ClientPool, discovery.DiscoveryInterface, string, error) {
@@ -217,11 +234,16 @@ func (c *Config) overrideCluster(a app.App, envName string) error {
 	//
 
 	log.Debugf("Validating deployment at '%s' with server '%v'", envName, reflect.ValueOf(servers).MapKeys())
-	env, err := a.Environment(envName)
+	env, err := app.ResolveEnvironment(a, envName)
 	if err != nil {
 		return err
 	}
 
+	if len(env.Destinations) > 1 {
+		return errors.Errorf("environment %q has %d destinations; select one, e.g. %q",
+			envName, len(env.Destinations), app.DestinationEnvName(envName, env.Destinations[0].Name))
+	}
+
 	destination := env.Destination
 
 	server, err := str.NormalizeURL(destination.Server)
@@ -17,7 +17,9 @@ package cluster
 
 import (
 	"fmt"
-	"sort"
+	"io"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
@@ -31,6 +33,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
 )
@@ -55,11 +58,14 @@ type ApplyConfig struct {
 	App            app.App
 	ClientConfig   *client.Config
 	ComponentNames []string
+	Concurrency    int
 	Create         bool
 	DryRun         bool
 	EnvName        string
 	GcTag          string
 	SkipGc         bool
+	Wait           bool
+	WaitTimeout    time.Duration
 }
 
 // ApplyOpts are options for configuring Apply.
@@ -76,13 +82,27 @@ type Apply struct {
 	objectInfo            ObjectInfo
 	ksonnetObjectFactory  func() ksonnetObject
 	upserterFactory       func() Upserter
+	waiterFactory         func() objectWaiter
+	hookRunnerFactory     func() hookRunner
+	historyFactory        historyFactoryFn
 	conflictTimeout       time.Duration
+	out                   io.Writer
+
+	// dryRunReport collects the actions taken during a dry run.
+	dryRunReport *dryRunReport
+
+	// result collects the result of applying each object.
+	result *ApplyResult
+
+	// description describes the revision recorded for the apply.
+	description string
 }
 
-// RunApply runs apply against a cluster given a configuration.
-func RunApply(config ApplyConfig, opts ...ApplyOpts) error {
+// RunApply runs apply against a cluster given a configuration. It returns the
+// result of applying each object.
+func RunApply(config ApplyConfig, opts ...ApplyOpts) (*ApplyResult, error) {
 	if config.ClientConfig == nil {
-		return errors.New("ksonnet client config is required")
+		return nil, errors.New("ksonnet client config is required")
 	}
 
 	a := &Apply{
@@ -94,7 +114,10 @@ func RunApply(config ApplyConfig, opts ...ApplyOpts) error {
 			factory := cmdutil.NewFactory(config.ClientConfig.Config)
 			return newDefaultKsonnetObject(factory)
 		},
+		historyFactory:  defaultHistoryFactory,
 		conflictTimeout: 1 * time.Second,
+		out:             os.Stdout,
+		description:     "apply",
 	}
 
 	for _, opt := range opts {
@@ -104,7 +127,7 @@ func RunApply(config ApplyConfig, opts ...ApplyOpts) error {
 	if a.clientOpts == nil {
 		co, err := GenClients(a.App, a.ClientConfig, a.EnvName)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		a.clientOpts = &co
@@ -113,75 +136,238 @@ func RunApply(config ApplyConfig, opts ...ApplyOpts) error {
 	if a.upserterFactory == nil {
 		u, err := newDefaultUpserter(a.ApplyConfig, a.objectInfo, *a.clientOpts, a.resourceClientFactory)
 		if err != nil {
-			return errors.Wrap(err, "creating upserter")
+			return nil, errors.Wrap(err, "creating upserter")
 		}
 		a.upserterFactory = func() Upserter {
 			return u
 		}
 	}
 
+	if a.waiterFactory == nil {
+		a.waiterFactory = func() objectWaiter {
+			return newDefaultObjectWaiter(*a.clientOpts, a.resourceClientFactory, a.WaitTimeout)
+		}
+	}
+
+	if a.hookRunnerFactory == nil {
+		a.hookRunnerFactory = func() hookRunner {
+			return newDefaultHookRunner(*a.clientOpts, a.resourceClientFactory,
+				a.upserterFactory(), a.waiterFactory(), a.WaitTimeout)
+		}
+	}
+
 	return a.Apply()
 }
 
-// Apply applies against a cluster.
-func (a *Apply) Apply() error {
+// Apply applies against a cluster. Pre-apply hooks are run before the objects
+// are applied, and post-apply hooks are run after. During a dry run, the result
+// contains the actions which would have been taken. Applying all of the components
+// in an environment records the objects as a new revision.
+func (a *Apply) Apply() (*ApplyResult, error) {
 	apiObjects, err := a.findObjectsFn(a.App, a.EnvName, a.ComponentNames)
 	if err != nil {
-		return errors.Wrap(err, "find objects")
+		return nil, errors.Wrap(err, "find objects")
 	}
 
-	sort.Sort(utils.DependencyOrder(apiObjects))
+	apiObjects, hooks, err := splitHooks(apiObjects)
+	if err != nil {
+		return nil, err
+	}
+
+	// Objects are changed while they are applied, so the revision is copied first.
+	// Hooks aren't recorded, so rolling back doesn't run them again.
+	var revisionObjects []*unstructured.Unstructured
+	if a.recordsRevision() {
+		if revisionObjects, err = copyObjects(apiObjects); err != nil {
+			return nil, err
+		}
+	}
+
+	if a.DryRun {
+		a.dryRunReport = newDryRunReport(a.out)
+	}
+
+	a.result = &ApplyResult{}
+
+	if err = a.runHooks(metadata.PhasePreApply, hooks[metadata.PhasePreApply]); err != nil {
+		return nil, err
+	}
 
 	seenUids := sets.NewString()
+	var appliedObjects []*unstructured.Unstructured
 
-	for _, obj := range apiObjects {
-		var uid string
-		uid, err = a.handleObject(obj)
+	// Objects in a tier only depend on objects in earlier tiers. If any object
+	// in a tier fails, later tiers are not applied.
+	for _, tier := range utils.DependencyTiers(apiObjects) {
+		applied, results, err := a.applyTier(tier)
 		if err != nil {
-			return errors.Wrap(err, "handle object")
+			return nil, errors.Wrap(err, "handle object")
 		}
 
+		appliedObjects = append(appliedObjects, applied...)
+		a.result.Objects = append(a.result.Objects, results...)
+
 		// Some objects appear under multiple kinds
 		// (eg: Deployment is both extensions/v1beta1
 		// and apps/v1beta1).  UID is the only stable
 		// identifier that links these two views of
 		// the same object.
-		seenUids.Insert(uid)
+		for _, result := range results {
+			seenUids.Insert(result.UID)
+		}
 	}
 
 	if a.GcTag != "" && !a.SkipGc {
 		if err = a.runGc(seenUids); err != nil {
-			return errors.Wrap(err, "run gc")
+			return nil, errors.Wrap(err, "run gc")
 		}
 	}
 
+	if a.Wait && !a.DryRun {
+		if err = a.waiterFactory().Wait(appliedObjects); err != nil {
+			return nil, errors.Wrap(err, "wait for objects")
+		}
+	}
+
+	if err = a.runHooks(metadata.PhasePostApply, hooks[metadata.PhasePostApply]); err != nil {
+		return nil, err
+	}
+
+	if a.DryRun {
+		return a.result, a.dryRunReport.Render()
+	}
+
+	if a.recordsRevision() {
+		// The objects have already been applied, so failing to record them
+		// doesn't fail the apply.
+		revision, err := a.historyFactory(*a.clientOpts, a.EnvName).Record(a.description, revisionObjects)
+		if err != nil {
+			log.Warnf("Unable to record revision of environment %q: %v", a.EnvName, err)
+		} else {
+			log.Infof("Recorded revision %d of environment %q", revision.Number, a.EnvName)
+		}
+	}
+
+	return a.result, nil
+}
+
+// recordsRevision returns true if the apply is recorded as a revision. Applying a
+// subset of the components does not record a revision, since the objects applied
+// are not a complete set.
+func (a *Apply) recordsRevision() bool {
+	return !a.DryRun && len(a.ComponentNames) == 0
+}
+
+// runHooks runs the hooks for a phase. During a dry run, the hooks which would
+// be run are reported instead.
+func (a *Apply) runHooks(phase string, hooks []*unstructured.Unstructured) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	if a.DryRun {
+		for _, hook := range hooks {
+			a.dryRunReport.add(ApplyActionHook, fmt.Sprintf("%s (%s)", describeObject(hook), phase))
+			a.result.Objects = append(a.result.Objects, newObjectResult(hook, ApplyActionHook, "", time.Now()))
+		}
+		return nil
+	}
+
+	results, err := a.hookRunnerFactory().Run(phase, hooks)
+	if err != nil {
+		return errors.Wrapf(err, "run %s hooks", phase)
+	}
+
+	a.result.Objects = append(a.result.Objects, results...)
 	return nil
 }
 
-func (a *Apply) handleObject(obj *unstructured.Unstructured) (string, error) {
+// applyTier applies the objects in a dependency tier, up to Concurrency at a time.
+// Applying one object at a time stops at the first failure. Otherwise every
+// object in the tier is attempted, and the errors are aggregated.
+func (a *Apply) applyTier(objects []*unstructured.Unstructured) ([]*unstructured.Unstructured, []ObjectResult, error) {
+	concurrency := a.Concurrency
+	if concurrency < 1 || a.DryRun {
+		// Dry runs preview objects one at a time so their diffs are written in order.
+		concurrency = 1
+	}
+
+	applied := make([]*unstructured.Unstructured, len(objects))
+	results := make([]ObjectResult, len(objects))
+	errs := make([]error, len(objects))
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i := range objects {
+		sem <- struct{}{}
+
+		// The previous object has released sem, so its error has been recorded.
+		if concurrency == 1 && i > 0 && errs[i-1] != nil {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			applied[i], results[i], errs[i] = a.handleObject(objects[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	var tierErrs []error
+	for i, err := range errs {
+		if err != nil {
+			tierErrs = append(tierErrs, errors.Wrapf(err, "applying %s", describeObject(objects[i])))
+		}
+	}
+
+	switch len(tierErrs) {
+	case 0:
+		return applied, results, nil
+	case 1:
+		return nil, nil, tierErrs[0]
+	default:
+		return nil, nil, utilerrors.NewAggregate(tierErrs)
+	}
+}
+
+// handleObject applies an object to the cluster. It returns the object which
+// was applied and the result of applying it.
+func (a *Apply) handleObject(obj *unstructured.Unstructured) (*unstructured.Unstructured, ObjectResult, error) {
+	started := time.Now()
+
 	if err := a.preprocessObject(obj); err != nil {
-		return "", errors.Wrap(err, "preprocessing object before apply")
+		return nil, ObjectResult{}, errors.Wrap(err, "preprocessing object before apply")
 	}
 
 	mergedObject, err := a.patchFromCluster(obj)
 	if err != nil {
-		return "", errors.Wrap(err, "patching object from cluster")
+		return nil, ObjectResult{}, errors.Wrap(err, "patching object from cluster")
 	}
 
 	a.setupGC(mergedObject)
 
-	return a.upsert(mergedObject)
+	uid, action, err := a.upsert(mergedObject)
+	if err != nil {
+		return nil, ObjectResult{}, err
+	}
+
+	return mergedObject, newObjectResult(mergedObject, action, uid, started), nil
 }
 
-// preprocessObject preprocesses an object for it is applied to the cluster.
+// preprocessObject preprocesses an object for it is applied to the cluster. Objects
+// are tagged during dry runs as well so previews reflect the real patch.
 func (a *Apply) preprocessObject(obj *unstructured.Unstructured) error {
 	aa := newDefaultAnnotationApplier()
-	if !a.DryRun {
-		return errors.Wrap(aa.SetOriginalConfiguration(obj), "tagging ksonnet managed object")
-	}
-
-	log.Info("tagging ksonnet managed object", a.dryRunText())
-	return nil
+	return errors.Wrap(aa.SetOriginalConfiguration(obj), "tagging ksonnet managed object")
 }
 
 // patchFromCluster patches an object with values that may exist in the cluster.
@@ -189,20 +375,19 @@ func (a *Apply) patchFromCluster(obj *unstructured.Unstructured) (*unstructured.
 	return a.ksonnetObjectFactory().MergeFromCluster(*a.clientOpts, obj)
 }
 
-func (a *Apply) upsert(obj *unstructured.Unstructured) (string, error) {
+func (a *Apply) upsert(obj *unstructured.Unstructured) (string, ApplyAction, error) {
 	if a.DryRun {
-		log.Info("upserting object", a.dryRunText())
-		return "12345", nil
+		return a.previewUpsert(obj)
 	}
 
 	u := a.upserterFactory()
 
 	for i := applyConflictRetryCount; i > 0; i-- {
-		uid, err := u.Upsert(obj)
+		uid, action, err := u.Upsert(obj)
 		if err != nil {
 			cause := errors.Cause(err)
 			if !kerrors.IsConflict(cause) {
-				return "", err
+				return "", "", err
 			}
 			// In order for the next try to work, update the resource version on the object
 			updatedObj, err := a.getUpdatedObject(obj)
@@ -214,10 +399,53 @@ func (a *Apply) upsert(obj *unstructured.Unstructured) (string, error) {
 			continue
 		}
 
-		return uid, nil
+		return uid, action, nil
 	}
 
-	return "", errApplyConflict
+	return "", "", errApplyConflict
+}
+
+// previewUpsert shows the changes upserting an object would make without changing
+// the cluster. It returns the UID of the live object if it exists, and the action
+// which would be taken.
+func (a *Apply) previewUpsert(obj *unstructured.Unstructured) (string, ApplyAction, error) {
+	desc := describeObject(obj)
+
+	live, err := a.getUpdatedObject(obj)
+	if err != nil {
+		if !kerrors.IsNotFound(errors.Cause(err)) {
+			return "", "", errors.Wrapf(err, "retrieving %s", desc)
+		}
+
+		if !a.Create {
+			return "", "", errors.New("not creating non-existent object")
+		}
+
+		if _, err = writeObjectDiff(a.out, desc, nil, obj); err != nil {
+			return "", "", err
+		}
+
+		a.dryRunReport.add(ApplyActionCreated, desc)
+		return "", ApplyActionCreated, nil
+	}
+
+	patched, err := previewPatch(live, obj)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "previewing patch for %s", desc)
+	}
+
+	changed, err := writeObjectDiff(a.out, desc, live, patched)
+	if err != nil {
+		return "", "", err
+	}
+
+	if !changed {
+		a.dryRunReport.add(ApplyActionUnchanged, desc)
+		return string(live.GetUID()), ApplyActionUnchanged, nil
+	}
+
+	a.dryRunReport.add(ApplyActionConfigured, desc)
+	return string(live.GetUID()), ApplyActionConfigured, nil
 }
 
 func (a *Apply) getUpdatedObject(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
@@ -255,12 +483,24 @@ func (a *Apply) runGc(seenUids sets.String) error {
 		log.Debugf("Considering %v for gc", desc)
 		if eligibleForGc(metav1Object, a.GcTag) && !seenUids.Has(string(metav1Object.GetUID())) {
 			log.Info("Garbage collecting ", desc, a.dryRunText())
-			if !a.DryRun {
+			started := time.Now()
+			if a.DryRun {
+				a.dryRunReport.add(ApplyActionPruned, desc)
+			} else {
 				err = gcDelete(*co, a.resourceClientFactory, &version, o)
 				if err != nil {
 					return err
 				}
 			}
+
+			a.result.Objects = append(a.result.Objects, ObjectResult{
+				GroupVersionKind: gvk,
+				Namespace:        metav1Object.GetNamespace(),
+				Name:             metav1Object.GetName(),
+				Action:           ApplyActionPruned,
+				UID:              string(metav1Object.GetUID()),
+				Duration:         time.Since(started),
+			})
 		}
 		return nil
 	})
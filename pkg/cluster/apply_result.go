@@ -0,0 +1,67 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ApplyAction is the action taken for an object during apply.
+type ApplyAction string
+
+const (
+	// ApplyActionCreated is an object which was created.
+	ApplyActionCreated ApplyAction = "created"
+	// ApplyActionConfigured is an existing object which was changed.
+	ApplyActionConfigured ApplyAction = "configured"
+	// ApplyActionUnchanged is an existing object which did not change.
+	ApplyActionUnchanged ApplyAction = "unchanged"
+	// ApplyActionPruned is an object which was garbage collected.
+	ApplyActionPruned ApplyAction = "pruned"
+	// ApplyActionHook is a hook which was run.
+	ApplyActionHook ApplyAction = "hook"
+)
+
+// ObjectResult is the result of applying a single object.
+type ObjectResult struct {
+	GroupVersionKind schema.GroupVersionKind
+	Namespace        string
+	Name             string
+	Action           ApplyAction
+	UID              string
+	Duration         time.Duration
+}
+
+func newObjectResult(obj *unstructured.Unstructured, action ApplyAction, uid string, started time.Time) ObjectResult {
+	return ObjectResult{
+		GroupVersionKind: obj.GroupVersionKind(),
+		Namespace:        obj.GetNamespace(),
+		Name:             obj.GetName(),
+		Action:           action,
+		UID:              uid,
+		Duration:         time.Since(started),
+	}
+}
+
+// ApplyResult is the result of applying objects to a cluster.
+type ApplyResult struct {
+	// Objects are the results for each object in the order they were applied.
+	// Garbage collected objects are listed last.
+	Objects []ObjectResult
+}
@@ -16,6 +16,9 @@
 package cluster
 
 import (
+	"bytes"
+	"sort"
+	"sync"
 	"testing"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -26,6 +29,7 @@ import (
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/client"
 	"github.com/ksonnet/ksonnet/pkg/cluster/mocks"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
 	"github.com/ksonnet/ksonnet/pkg/util/test"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
@@ -56,10 +60,13 @@ func Test_Apply(t *testing.T) {
 			ClientConfig: &client.Config{},
 		}
 
+		history := &fakeHistory{}
+
 		setupApp := func(apply *Apply) {
 			obj := &unstructured.Unstructured{Object: genObject()}
 
 			apply.clientOpts = &Clients{}
+			apply.historyFactory = history.factory
 
 			apply.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
 				objects := []*unstructured.Unstructured{obj}
@@ -75,17 +82,158 @@ func Test_Apply(t *testing.T) {
 
 			apply.upserterFactory = func() Upserter {
 				return &fakeUpserter{
-					upsertID: "12345",
+					upsertID:     "12345",
+					upsertAction: ApplyActionCreated,
+				}
+			}
+		}
+
+		result, err := RunApply(applyConfig, setupApp)
+		require.NoError(t, err)
+
+		require.Len(t, result.Objects, 1)
+		r := result.Objects[0]
+		require.Equal(t, "apps/v1beta1, Kind=Deployment", r.GroupVersionKind.String())
+		require.Equal(t, "guiroot", r.Name)
+		require.Equal(t, ApplyActionCreated, r.Action)
+		require.Equal(t, "12345", r.UID)
+
+		require.Len(t, history.revisions, 1)
+		revision := history.revisions[0]
+		require.Equal(t, "apply", revision.Description)
+		require.Len(t, revision.Objects, 1)
+		_, ok := revision.Objects[0].GetAnnotations()[metadata.AnnotationManaged]
+		require.False(t, ok, "revision objects are recorded as rendered")
+	})
+}
+
+func Test_Apply_record_failure(t *testing.T) {
+	test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
+		applyConfig := ApplyConfig{
+			App:          a,
+			ClientConfig: &client.Config{},
+		}
+
+		setupApp := func(apply *Apply) {
+			apply.clientOpts = &Clients{}
+			apply.historyFactory = (&fakeHistory{err: errors.New("too large")}).factory
+
+			apply.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+				return []*unstructured.Unstructured{genHook("web", nil)}, nil
+			}
+
+			apply.ksonnetObjectFactory = func() ksonnetObject {
+				return &passthroughKsonnetObject{}
+			}
+
+			apply.upserterFactory = func() Upserter {
+				return &funcUpserter{
+					upsertFn: func(obj *unstructured.Unstructured) (string, error) {
+						return obj.GetName(), nil
+					},
 				}
 			}
 		}
 
-		err := RunApply(applyConfig, setupApp)
+		result, err := RunApply(applyConfig, setupApp)
 		require.NoError(t, err)
+		require.Len(t, result.Objects, 1)
 	})
 }
 
 func Test_Apply_dry_run(t *testing.T) {
+	cases := []struct {
+		name     string
+		live     func() *unstructured.Unstructured
+		expected []string
+	}{
+		{
+			name: "create",
+			live: func() *unstructured.Unstructured { return nil },
+			expected: []string{
+				"+++ applied/deployment guiroot",
+				"+  replicas: 1",
+				"created deployment guiroot",
+			},
+		},
+		{
+			name: "update",
+			live: func() *unstructured.Unstructured {
+				live := &unstructured.Unstructured{Object: genObject()}
+				live.Object["spec"].(map[string]interface{})["replicas"] = 3
+				return live
+			},
+			expected: []string{
+				"--- live/deployment guiroot",
+				"+++ applied/deployment guiroot",
+				"-  replicas: 3",
+				"+  replicas: 1",
+				"configured deployment guiroot",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
+				applyConfig := ApplyConfig{
+					App:          a,
+					ClientConfig: &client.Config{},
+					Create:       true,
+					DryRun:       true,
+				}
+
+				var buf bytes.Buffer
+
+				setupApp := func(apply *Apply) {
+					obj := &unstructured.Unstructured{Object: genObject()}
+
+					apply.clientOpts = &Clients{}
+
+					apply.historyFactory = (&fakeHistory{}).factory
+					apply.out = &buf
+
+					apply.resourceClientFactory = func(opts Clients, object runtime.Object) (ResourceClient, error) {
+						rc := &mocks.ResourceClient{}
+						if live := tc.live(); live != nil {
+							rc.On("Get", mock.Anything).Return(live, nil)
+						} else {
+							rc.On("Get", mock.Anything).Return(nil, &notFoundError{})
+						}
+						return rc, nil
+					}
+
+					apply.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+						objects := []*unstructured.Unstructured{obj}
+
+						return objects, nil
+					}
+
+					apply.ksonnetObjectFactory = func() ksonnetObject {
+						return &fakeKsonnetObject{
+							obj: obj,
+						}
+					}
+
+					apply.upserterFactory = func() Upserter {
+						return &fakeUpserter{
+							upsertErr: errors.New("upsert should not run"),
+						}
+					}
+				}
+
+				_, err := RunApply(applyConfig, setupApp)
+				require.NoError(t, err)
+
+				for _, s := range tc.expected {
+					require.Contains(t, buf.String(), s)
+				}
+			})
+		})
+	}
+}
+
+func Test_Apply_dry_run_unchanged(t *testing.T) {
 	test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
 		applyConfig := ApplyConfig{
 			App:          a,
@@ -93,15 +241,24 @@ func Test_Apply_dry_run(t *testing.T) {
 			DryRun:       true,
 		}
 
+		var buf bytes.Buffer
+
 		setupApp := func(apply *Apply) {
 			obj := &unstructured.Unstructured{Object: genObject()}
 
 			apply.clientOpts = &Clients{}
 
-			apply.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
-				objects := []*unstructured.Unstructured{obj}
+			apply.historyFactory = (&fakeHistory{}).factory
+			apply.out = &buf
 
-				return objects, nil
+			apply.resourceClientFactory = func(opts Clients, object runtime.Object) (ResourceClient, error) {
+				rc := &mocks.ResourceClient{}
+				rc.On("Get", mock.Anything).Return(obj, nil)
+				return rc, nil
+			}
+
+			apply.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+				return []*unstructured.Unstructured{obj}, nil
 			}
 
 			apply.ksonnetObjectFactory = func() ksonnetObject {
@@ -109,16 +266,13 @@ func Test_Apply_dry_run(t *testing.T) {
 					obj: obj,
 				}
 			}
-
-			apply.upserterFactory = func() Upserter {
-				return &fakeUpserter{
-					upsertErr: errors.New("upsert should not run"),
-				}
-			}
 		}
 
-		err := RunApply(applyConfig, setupApp)
+		_, err := RunApply(applyConfig, setupApp)
 		require.NoError(t, err)
+
+		require.NotContains(t, buf.String(), "+++")
+		require.Contains(t, buf.String(), "unchanged deployment guiroot")
 	})
 }
 
@@ -133,6 +287,8 @@ func Test_Apply_retry_on_conflict(t *testing.T) {
 			obj := &unstructured.Unstructured{Object: genObject()}
 
 			apply.clientOpts = &Clients{}
+
+			apply.historyFactory = (&fakeHistory{}).factory
 			apply.resourceClientFactory = func(opts Clients, object runtime.Object) (ResourceClient, error) {
 				rc := &mocks.ResourceClient{}
 				rc.On("Get", mock.Anything).Return(obj, nil)
@@ -160,12 +316,279 @@ func Test_Apply_retry_on_conflict(t *testing.T) {
 			apply.conflictTimeout = 0
 		}
 
-		err := RunApply(applyConfig, setupApp)
+		_, err := RunApply(applyConfig, setupApp)
 		cause := errors.Cause(err)
 		require.Equal(t, errApplyConflict, cause)
 	})
 }
 
+func Test_Apply_wait(t *testing.T) {
+	test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
+		applyConfig := ApplyConfig{
+			App:          a,
+			ClientConfig: &client.Config{},
+			Wait:         true,
+		}
+
+		var waited []*unstructured.Unstructured
+
+		setupApp := func(apply *Apply) {
+			obj := &unstructured.Unstructured{Object: genObject()}
+
+			apply.clientOpts = &Clients{}
+
+			apply.historyFactory = (&fakeHistory{}).factory
+
+			apply.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+				objects := []*unstructured.Unstructured{obj}
+
+				return objects, nil
+			}
+
+			apply.ksonnetObjectFactory = func() ksonnetObject {
+				return &fakeKsonnetObject{
+					obj: obj,
+				}
+			}
+
+			apply.upserterFactory = func() Upserter {
+				return &fakeUpserter{
+					upsertID: "12345",
+				}
+			}
+
+			apply.waiterFactory = func() objectWaiter {
+				return &fakeObjectWaiter{
+					waitFn: func(objects []*unstructured.Unstructured) error {
+						waited = objects
+						return nil
+					},
+				}
+			}
+		}
+
+		_, err := RunApply(applyConfig, setupApp)
+		require.NoError(t, err)
+
+		require.Len(t, waited, 1)
+		require.Equal(t, "guiroot", waited[0].GetName())
+	})
+}
+
+func Test_Apply_tiers(t *testing.T) {
+	genTierObject := func(apiVersion, kind, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetName(name)
+		return obj
+	}
+
+	cases := []struct {
+		name        string
+		concurrency int
+		failures    map[string]bool
+		applied     []string
+		errors      []string
+	}{
+		{
+			name:        "in general",
+			concurrency: 2,
+			applied:     []string{"ns", "cm1", "cm2", "web"},
+		},
+		{
+			name:        "failure stops later tiers",
+			concurrency: 2,
+			failures:    map[string]bool{"cm1": true, "cm2": true},
+			applied:     []string{"ns"},
+			errors:      []string{"applying configmap cm1: fail", "applying configmap cm2: fail"},
+		},
+		{
+			name:        "failure stops the tier when applying one at a time",
+			concurrency: 1,
+			failures:    map[string]bool{"cm1": true},
+			applied:     []string{"ns"},
+			errors:      []string{"applying configmap cm1: fail"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
+				applyConfig := ApplyConfig{
+					App:          a,
+					ClientConfig: &client.Config{},
+					Concurrency:  tc.concurrency,
+				}
+
+				var mu sync.Mutex
+				var applied []string
+
+				setupApp := func(apply *Apply) {
+					apply.clientOpts = &Clients{}
+					apply.historyFactory = (&fakeHistory{}).factory
+
+					apply.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+						return []*unstructured.Unstructured{
+							genTierObject("apps/v1", "Deployment", "web"),
+							genTierObject("v1", "ConfigMap", "cm1"),
+							genTierObject("v1", "ConfigMap", "cm2"),
+							genTierObject("v1", "Namespace", "ns"),
+						}, nil
+					}
+
+					apply.ksonnetObjectFactory = func() ksonnetObject {
+						return &passthroughKsonnetObject{}
+					}
+
+					apply.upserterFactory = func() Upserter {
+						return &funcUpserter{
+							upsertFn: func(obj *unstructured.Unstructured) (string, error) {
+								if tc.failures[obj.GetName()] {
+									return "", errors.New("fail")
+								}
+
+								mu.Lock()
+								defer mu.Unlock()
+								applied = append(applied, obj.GetName())
+								return obj.GetName(), nil
+							},
+						}
+					}
+				}
+
+				_, err := RunApply(applyConfig, setupApp)
+
+				// Objects within a tier are applied concurrently.
+				sort.Strings(applied[1:])
+				require.Equal(t, tc.applied, applied)
+
+				if len(tc.errors) == 0 {
+					require.NoError(t, err)
+					return
+				}
+
+				require.Error(t, err)
+				for _, s := range tc.errors {
+					require.Contains(t, err.Error(), s)
+				}
+			})
+		})
+	}
+}
+
+func Test_Apply_hooks(t *testing.T) {
+	cases := []struct {
+		name    string
+		hookErr error
+		calls   []string
+		isErr   bool
+	}{
+		{
+			name:  "in general",
+			calls: []string{"pre-apply migrate", "apply web", "post-apply notify"},
+		},
+		{
+			name:    "failed hook stops apply",
+			hookErr: errors.New("job failed"),
+			calls:   []string{"pre-apply migrate"},
+			isErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
+				applyConfig := ApplyConfig{
+					App:          a,
+					ClientConfig: &client.Config{},
+				}
+
+				var calls []string
+				history := &fakeHistory{}
+
+				setupApp := func(apply *Apply) {
+					apply.clientOpts = &Clients{}
+					apply.historyFactory = history.factory
+
+					apply.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+						return []*unstructured.Unstructured{
+							genHook("notify", map[string]string{metadata.AnnotationPhase: metadata.PhasePostApply}),
+							genHook("web", nil),
+							genHook("migrate", map[string]string{metadata.AnnotationPhase: metadata.PhasePreApply}),
+						}, nil
+					}
+
+					apply.ksonnetObjectFactory = func() ksonnetObject {
+						return &passthroughKsonnetObject{}
+					}
+
+					apply.upserterFactory = func() Upserter {
+						return &funcUpserter{
+							upsertFn: func(obj *unstructured.Unstructured) (string, error) {
+								calls = append(calls, "apply "+obj.GetName())
+								return obj.GetName(), nil
+							},
+						}
+					}
+
+					apply.hookRunnerFactory = func() hookRunner {
+						return &fakeHookRunner{
+							runFn: func(phase string, hooks []*unstructured.Unstructured) ([]ObjectResult, error) {
+								for _, hook := range hooks {
+									calls = append(calls, phase+" "+hook.GetName())
+								}
+								return nil, tc.hookErr
+							},
+						}
+					}
+				}
+
+				_, err := RunApply(applyConfig, setupApp)
+				if tc.isErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+					require.Len(t, history.revisions, 1)
+					require.Equal(t, []string{"web"}, objectNames(history.revisions[0].Objects),
+						"hooks are not recorded")
+				}
+
+				require.Equal(t, tc.calls, calls)
+			})
+		})
+	}
+}
+
+type passthroughKsonnetObject struct{}
+
+var _ ksonnetObject = (*passthroughKsonnetObject)(nil)
+
+func (ko *passthroughKsonnetObject) MergeFromCluster(co Clients, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	return obj, nil
+}
+
+type funcUpserter struct {
+	upsertFn func(*unstructured.Unstructured) (string, error)
+}
+
+var _ Upserter = (*funcUpserter)(nil)
+
+func (u *funcUpserter) Upsert(obj *unstructured.Unstructured) (string, ApplyAction, error) {
+	uid, err := u.upsertFn(obj)
+	return uid, ApplyActionConfigured, err
+}
+
+type fakeObjectWaiter struct {
+	waitFn func([]*unstructured.Unstructured) error
+}
+
+var _ objectWaiter = (*fakeObjectWaiter)(nil)
+
+func (w *fakeObjectWaiter) Wait(objects []*unstructured.Unstructured) error {
+	return w.waitFn(objects)
+}
+
 func genObject() map[string]interface{} {
 	return map[string]interface{}{
 		"apiVersion": "apps/v1beta1",
@@ -17,12 +17,15 @@ package cluster
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
 	"github.com/ksonnet/ksonnet/pkg/metadata"
 	"github.com/ksonnet/ksonnet/pkg/pipeline"
+	"github.com/ksonnet/ksonnet/pkg/util/prompt"
 	"github.com/ksonnet/ksonnet/utils"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -45,6 +48,10 @@ type discoveryFn func(a app.App, clientConfig *client.Config, envName string) (d
 type validateObjectFn func(d discovery.DiscoveryInterface,
 	obj *unstructured.Unstructured) []error
 
+type walkObjectsFn func(co Clients, listopts metav1.ListOptions, callback func(runtime.Object) error) error
+
+type serverVersionFn func(discovery.ServerVersionInterface) (utils.ServerVersion, error)
+
 type findObjectsFn func(a app.App, envName string,
 	componentNames []string) ([]*unstructured.Unstructured, error)
 
@@ -53,6 +60,28 @@ func findObjects(a app.App, envName string, componentNames []string) ([]*unstruc
 	return p.Objects(componentNames)
 }
 
+type isTerminalFn func(io.Reader) bool
+
+// confirm asks the user to confirm an action. It returns an error if the
+// user declines, or if in isn't a terminal, so a script can't silently
+// skip the action and still succeed.
+func confirm(in io.Reader, out io.Writer, isTerminal isTerminalFn, question, action string) error {
+	if !isTerminal(in) {
+		return errors.New("stdin is not a terminal; pass --yes")
+	}
+
+	ok, err := prompt.Confirm(in, out, question)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.Errorf("%s cancelled", action)
+	}
+
+	return nil
+}
+
 func stringListContains(list []string, value string) bool {
 	for _, item := range list {
 		if item == value {
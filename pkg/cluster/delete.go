@@ -17,15 +17,29 @@ package cluster
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/util/prompt"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
 	"github.com/ksonnet/ksonnet/utils"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	deleteActionDelete = "delete"
+	deleteActionRun    = "run"
 )
 
 // DeleteConfig is configuration for Delete.
@@ -33,8 +47,24 @@ type DeleteConfig struct {
 	App            app.App
 	ClientConfig   *client.Config
 	ComponentNames []string
+	DryRun         bool
 	EnvName        string
 	GracePeriod    int64
+	// Kinds limits the objects to those with one of these kinds. Kinds are
+	// matched case insensitively.
+	Kinds []string
+	// Names limits the objects to those with one of these names.
+	Names []string
+	// Orphan leaves the dependents of deleted objects in the cluster.
+	Orphan bool
+	// Selector is a label selector which limits the objects.
+	Selector    string
+	SkipConfirm bool
+	// Wait waits for deleted objects to no longer exist.
+	Wait        bool
+	WaitTimeout time.Duration
+	In          io.Reader
+	Out         io.Writer
 }
 
 // DeleteOpts is an option for configuring Delete.
@@ -49,17 +79,35 @@ type Delete struct {
 	genClientOptsFn       genClientOptsFn
 	objectInfo            ObjectInfo
 	resourceClientFactory resourceClientFactoryFn
+	serverVersionFn       serverVersionFn
+	isTerminalFn          isTerminalFn
+	hookRunnerFactory     func(Clients) (hookRunner, error)
+	waiterFactory         func(Clients) deletionWaiter
 }
 
 // RunDelete runs delete against a cluster for a given configuration.
 func RunDelete(config DeleteConfig, opts ...DeleteOpts) error {
+	if config.In == nil {
+		config.In = os.Stdin
+	}
+
+	if config.Out == nil {
+		config.Out = os.Stdout
+	}
+
 	d := &Delete{
 		DeleteConfig:          config,
 		findObjectsFn:         findObjects,
 		genClientOptsFn:       GenClients,
 		resourceClientFactory: resourceClientFactory,
+		serverVersionFn:       utils.FetchVersion,
+		isTerminalFn:          prompt.IsTerminal,
 		objectInfo:            &objectInfo{},
 	}
+	d.hookRunnerFactory = d.newHookRunner
+	d.waiterFactory = func(co Clients) deletionWaiter {
+		return newDefaultObjectWaiter(co, d.resourceClientFactory, d.WaitTimeout)
+	}
 
 	for _, opt := range opts {
 		opt(d)
@@ -68,40 +116,106 @@ func RunDelete(config DeleteConfig, opts ...DeleteOpts) error {
 	return d.Delete()
 }
 
-// Delete deletes objects from a cluster.
+// Delete deletes objects from a cluster. The objects to delete are listed and
+// deletion is confirmed before any changes are made. Pre-delete hooks are run
+// before any objects are deleted. If Wait is set, Delete returns once the objects
+// no longer exist.
 func (d *Delete) Delete() error {
+	selector, err := labels.Parse(d.Selector)
+	if err != nil {
+		return errors.Wrapf(err, "parsing selector %q", d.Selector)
+	}
+
 	apiObjects, err := d.findObjectsFn(d.App, d.EnvName, d.ComponentNames)
 	if err != nil {
 		return errors.Wrap(err, "find objects")
 	}
 
+	apiObjects, hooks, err := splitHooks(d.selectObjects(apiObjects, selector))
+	if err != nil {
+		return err
+	}
+
+	// Apply hooks may have been kept after they ran.
+	apiObjects = append(apiObjects, hooks[metadata.PhasePreApply]...)
+	apiObjects = append(apiObjects, hooks[metadata.PhasePostApply]...)
+	sort.Sort(sort.Reverse(utils.DependencyOrder(apiObjects)))
+
+	preDelete := hooks[metadata.PhasePreDelete]
+
+	if len(apiObjects) == 0 && len(preDelete) == 0 {
+		fmt.Fprintln(d.Out, "No objects to delete")
+		return nil
+	}
+
 	co, err := d.genClientOptsFn(d.App, d.ClientConfig, d.EnvName)
 	if err != nil {
 		return err
 	}
 
-	version, err := utils.FetchVersion(co.discovery)
+	t := table.New("delete", d.Out)
+	t.SetHeader([]string{"action", "object"})
+	for _, obj := range preDelete {
+		t.Append([]string{deleteActionRun, d.describe(co, obj)})
+	}
+	for _, obj := range apiObjects {
+		t.Append([]string{deleteActionDelete, d.describe(co, obj)})
+	}
+
+	if err = t.Render(); err != nil {
+		return err
+	}
+
+	if d.DryRun {
+		return nil
+	}
+
+	if !d.SkipConfirm {
+		question := fmt.Sprintf("Delete %d object(s) from environment %q?", len(apiObjects), d.EnvName)
+		if len(preDelete) > 0 {
+			question = fmt.Sprintf("Run %d pre-delete hook(s) and delete %d object(s) from environment %q?",
+				len(preDelete), len(apiObjects), d.EnvName)
+		}
+		if err = confirm(d.In, d.Out, d.isTerminalFn, question, "delete"); err != nil {
+			return err
+		}
+	}
+
+	if len(preDelete) > 0 {
+		hr, err := d.hookRunnerFactory(co)
+		if err != nil {
+			return err
+		}
+
+		if _, err = hr.Run(metadata.PhasePreDelete, preDelete); err != nil {
+			return errors.Wrapf(err, "run %s hooks", metadata.PhasePreDelete)
+		}
+	}
+
+	version, err := d.serverVersionFn(co.discovery)
 	if err != nil {
 		return err
 	}
-	sort.Sort(sort.Reverse(utils.DependencyOrder(apiObjects)))
 
 	deleteOpts := metav1.DeleteOptions{}
 	if version.Compare(1, 6) < 0 {
 		// 1.5.x option
-		boolFalse := false
-		deleteOpts.OrphanDependents = &boolFalse
+		orphan := d.Orphan
+		deleteOpts.OrphanDependents = &orphan
 	} else {
 		// 1.6.x option (NB: Background is broken)
-		fg := metav1.DeletePropagationForeground
-		deleteOpts.PropagationPolicy = &fg
+		propagation := metav1.DeletePropagationForeground
+		if d.Orphan {
+			propagation = metav1.DeletePropagationOrphan
+		}
+		deleteOpts.PropagationPolicy = &propagation
 	}
 	if d.GracePeriod >= 0 {
 		deleteOpts.GracePeriodSeconds = &d.GracePeriod
 	}
 
 	for _, obj := range apiObjects {
-		desc := fmt.Sprintf("%s %s", d.objectInfo.ResourceName(co.discovery, obj), utils.FqName(obj))
+		desc := d.describe(co, obj)
 		log.Info("Deleting ", desc)
 
 		client, err := d.resourceClientFactory(co, obj)
@@ -117,6 +231,67 @@ func (d *Delete) Delete() error {
 		log.Debugf("Deleted object: ", obj)
 	}
 
+	if d.Wait {
+		if err = d.waiterFactory(co).WaitDeleted(apiObjects); err != nil {
+			return err
+		}
+	}
+
 	return nil
 
 }
+
+// selectObjects returns the objects which match the label selector and the
+// kinds and names. An empty selector matches every object.
+func (d *Delete) selectObjects(objects []*unstructured.Unstructured, selector labels.Selector) []*unstructured.Unstructured {
+	kinds := make(map[string]bool)
+	for _, kind := range d.Kinds {
+		kinds[strings.ToLower(kind)] = true
+	}
+
+	names := make(map[string]bool)
+	for _, name := range d.Names {
+		names[name] = true
+	}
+
+	var selected []*unstructured.Unstructured
+	for _, obj := range objects {
+		if !selector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+
+		if len(kinds) > 0 && !kinds[strings.ToLower(obj.GetKind())] {
+			continue
+		}
+
+		if len(names) > 0 && !names[obj.GetName()] {
+			continue
+		}
+
+		selected = append(selected, obj)
+	}
+
+	return selected
+}
+
+func (d *Delete) describe(co Clients, obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s %s", d.objectInfo.ResourceName(co.discovery, obj), utils.FqName(obj))
+}
+
+// newHookRunner creates a hook runner for pre-delete hooks.
+func (d *Delete) newHookRunner(co Clients) (hookRunner, error) {
+	ac := ApplyConfig{
+		App:          d.App,
+		ClientConfig: d.ClientConfig,
+		Create:       true,
+		EnvName:      d.EnvName,
+	}
+
+	u, err := newDefaultUpserter(ac, d.objectInfo, co, d.resourceClientFactory)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating upserter")
+	}
+
+	w := newDefaultObjectWaiter(co, d.resourceClientFactory, d.WaitTimeout)
+	return newDefaultHookRunner(co, d.resourceClientFactory, u, w, w.timeout), nil
+}
@@ -0,0 +1,250 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster/mocks"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/ksonnet/ksonnet/utils"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+)
+
+func genDeleteObject(kind, name string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind(kind)
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	return obj
+}
+
+func TestDelete(t *testing.T) {
+	cases := []struct {
+		name        string
+		config      DeleteConfig
+		notTerminal bool
+		answer      string
+		hooks       []string
+		deleted     []string
+		propagation metav1.DeletionPropagation
+		expected    []string
+		waited      []string
+		isErr       bool
+	}{
+		{
+			name:   "dry run",
+			config: DeleteConfig{DryRun: true},
+			expected: []string{
+				"run    job default.backup",
+				"delete configmap default.settings",
+				"delete service default.web",
+			},
+		},
+		{
+			name:        "confirmed",
+			answer:      "y\n",
+			hooks:       []string{"backup"},
+			deleted:     []string{"settings", "web"},
+			propagation: metav1.DeletePropagationForeground,
+			expected: []string{
+				`Run 1 pre-delete hook(s) and delete 2 object(s) from environment "default"? [y/N]: `,
+			},
+		},
+		{
+			name:        "confirmed without hooks",
+			config:      DeleteConfig{Selector: "tier=frontend"},
+			answer:      "y\n",
+			deleted:     []string{"web"},
+			propagation: metav1.DeletePropagationForeground,
+			expected: []string{
+				`Delete 1 object(s) from environment "default"? [y/N]: `,
+			},
+		},
+		{
+			name:   "not confirmed",
+			answer: "n\n",
+			isErr:  true,
+		},
+		{
+			name:        "input is not a terminal",
+			notTerminal: true,
+			answer:      "y\n",
+			isErr:       true,
+		},
+		{
+			name:        "skip confirmation with orphaned dependents",
+			config:      DeleteConfig{SkipConfirm: true, Orphan: true},
+			hooks:       []string{"backup"},
+			deleted:     []string{"settings", "web"},
+			propagation: metav1.DeletePropagationOrphan,
+		},
+		{
+			name:        "select by label",
+			config:      DeleteConfig{SkipConfirm: true, Selector: "tier=frontend"},
+			deleted:     []string{"web"},
+			propagation: metav1.DeletePropagationForeground,
+		},
+		{
+			name:        "select by kind and name",
+			config:      DeleteConfig{SkipConfirm: true, Kinds: []string{"configmap", "Service"}, Names: []string{"settings"}},
+			deleted:     []string{"settings"},
+			propagation: metav1.DeletePropagationForeground,
+		},
+		{
+			name:        "wait for deletion",
+			config:      DeleteConfig{SkipConfirm: true, Wait: true},
+			hooks:       []string{"backup"},
+			deleted:     []string{"settings", "web"},
+			propagation: metav1.DeletePropagationForeground,
+			waited:      []string{"settings", "web"},
+		},
+		{
+			name:   "nothing selected",
+			config: DeleteConfig{Names: []string{"missing"}},
+			expected: []string{
+				"No objects to delete",
+			},
+		},
+		{
+			name:   "invalid selector",
+			config: DeleteConfig{Selector: "tier in frontend"},
+			isErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
+				var out bytes.Buffer
+
+				config := tc.config
+				config.App = a
+				config.ClientConfig = &client.Config{}
+				config.EnvName = "default"
+				config.GracePeriod = -1
+				config.In = strings.NewReader(tc.answer)
+				config.Out = &out
+
+				objects := []*unstructured.Unstructured{
+					genDeleteObject("ConfigMap", "settings", nil),
+					genDeleteObject("Service", "web", map[string]string{"tier": "frontend"}),
+					genHook("backup", map[string]string{metadata.AnnotationPhase: metadata.PhasePreDelete}),
+				}
+
+				var hooks, deleted, waited []string
+				var propagation metav1.DeletionPropagation
+
+				setup := func(d *Delete) {
+					d.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+						return objects, nil
+					}
+
+					d.genClientOptsFn = func(a app.App, c *client.Config, envName string) (Clients, error) {
+						return Clients{}, nil
+					}
+
+					d.serverVersionFn = func(discovery.ServerVersionInterface) (utils.ServerVersion, error) {
+						return utils.ServerVersion{Major: 1, Minor: 10}, nil
+					}
+
+					d.isTerminalFn = func(io.Reader) bool {
+						return !tc.notTerminal
+					}
+
+					d.hookRunnerFactory = func(Clients) (hookRunner, error) {
+						return &fakeHookRunner{
+							runFn: func(phase string, objects []*unstructured.Unstructured) ([]ObjectResult, error) {
+								require.Equal(t, metadata.PhasePreDelete, phase)
+								require.Nil(t, deleted, "hooks run before objects are deleted")
+								hooks = append(hooks, objectNames(objects)...)
+								return nil, nil
+							},
+						}, nil
+					}
+
+					d.waiterFactory = func(Clients) deletionWaiter {
+						return &fakeDeletionWaiter{
+							waitDeletedFn: func(objects []*unstructured.Unstructured) error {
+								waited = objectNames(objects)
+								return nil
+							},
+						}
+					}
+
+					oi := &mocks.ObjectInfo{}
+					oi.On("ResourceName", mock.Anything, mock.Anything).Return(func(_ discovery.ServerResourcesInterface, o runtime.Object) string {
+						return strings.ToLower(o.GetObjectKind().GroupVersionKind().Kind)
+					})
+					d.objectInfo = oi
+
+					d.resourceClientFactory = func(co Clients, object runtime.Object) (ResourceClient, error) {
+						obj := object.(*unstructured.Unstructured)
+
+						rc := &mocks.ResourceClient{}
+						rc.On("Delete", mock.Anything).Run(func(args mock.Arguments) {
+							opts := args.Get(0).(*metav1.DeleteOptions)
+							propagation = *opts.PropagationPolicy
+							deleted = append(deleted, obj.GetName())
+						}).Return(nil)
+						return rc, nil
+					}
+				}
+
+				err := RunDelete(config, setup)
+				if tc.isErr {
+					require.Error(t, err)
+					require.Empty(t, deleted)
+					return
+				}
+				require.NoError(t, err)
+
+				require.Equal(t, tc.hooks, hooks)
+				require.Equal(t, tc.deleted, deleted)
+				require.Equal(t, tc.propagation, propagation)
+				require.Equal(t, tc.waited, waited)
+				for _, s := range tc.expected {
+					require.Contains(t, out.String(), s)
+				}
+			})
+		})
+	}
+}
+
+type fakeDeletionWaiter struct {
+	waitDeletedFn func(objects []*unstructured.Unstructured) error
+}
+
+var _ deletionWaiter = (*fakeDeletionWaiter)(nil)
+
+func (w *fakeDeletionWaiter) WaitDeleted(objects []*unstructured.Unstructured) error {
+	return w.waitDeletedFn(objects)
+}
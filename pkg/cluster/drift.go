@@ -0,0 +1,254 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/util/compare"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// driftIgnoredAnnotations are annotations which are updated on the
+	// cluster by ksonnet or the server.
+	driftIgnoredAnnotations = map[string]bool{
+		metadata.AnnotationManaged:                         true,
+		"deployment.kubernetes.io/revision":                true,
+		"kubectl.kubernetes.io/last-applied-configuration": true,
+	}
+)
+
+// DriftConfig is configuration for Drift.
+type DriftConfig struct {
+	App            app.App
+	ClientConfig   *client.Config
+	ComponentNames []string
+	EnvName        string
+}
+
+// DriftOpts is an option for configuring Drift.
+type DriftOpts func(*Drift)
+
+// FieldDrift is a field whose value on the cluster differs from the value
+// which was applied.
+type FieldDrift struct {
+	// Path is the JSON path of the field.
+	Path string
+	// Applied is the value which was applied. It is nil if the field was added
+	// on the cluster.
+	Applied interface{}
+	// Live is the value on the cluster. It is nil if the field was removed
+	// from the cluster.
+	Live interface{}
+}
+
+// ObjectDrift is the drift of a single object.
+type ObjectDrift struct {
+	GroupVersionKind schema.GroupVersionKind
+	Namespace        string
+	Name             string
+	// Fields are the fields which were changed on the cluster.
+	Fields []FieldDrift
+}
+
+// DriftResult is the result of comparing the objects of an environment with
+// the cluster.
+type DriftResult struct {
+	// Changed are objects with fields which were changed on the cluster after
+	// they were applied.
+	Changed []ObjectDrift
+	// Deleted are objects rendered by the app which no longer exist on
+	// the cluster.
+	Deleted []ObjectDrift
+}
+
+// HasDrift returns true if any object has drifted.
+func (r *DriftResult) HasDrift() bool {
+	return len(r.Changed) > 0 || len(r.Deleted) > 0
+}
+
+// Drift compares the objects applied to an environment with the cluster.
+type Drift struct {
+	DriftConfig
+
+	// these make it easier to test Drift.
+	findObjectsFn         findObjectsFn
+	genClientOptsFn       genClientOptsFn
+	resourceClientFactory resourceClientFactoryFn
+}
+
+// RunDrift runs drift against a cluster for a given configuration.
+func RunDrift(config DriftConfig, opts ...DriftOpts) (*DriftResult, error) {
+	d := &Drift{
+		DriftConfig:           config,
+		findObjectsFn:         findObjects,
+		genClientOptsFn:       GenClients,
+		resourceClientFactory: resourceClientFactory,
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d.Drift()
+}
+
+// Drift compares the pristine copy of each object recorded in its managed
+// annotation with the live object. Only fields set by the pristine copy are
+// compared, so fields defaulted by the server are not reported.
+func (d *Drift) Drift() (*DriftResult, error) {
+	objects, err := d.findObjectsFn(d.App, d.EnvName, d.ComponentNames)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding objects")
+	}
+
+	co, err := d.genClientOptsFn(d.App, d.ClientConfig, d.EnvName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DriftResult{}
+
+	for _, obj := range objects {
+		rc, err := d.resourceClientFactory(co, obj)
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating client for %s", describeObject(obj))
+		}
+
+		live, err := rc.Get(metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(errors.Cause(err)) {
+				result.Deleted = append(result.Deleted, newObjectDrift(obj, nil))
+				continue
+			}
+
+			return nil, errors.Wrapf(err, "fetching %s", describeObject(obj))
+		}
+
+		applied, err := pristineObject(live)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding managed annotation of %s", describeObject(obj))
+		}
+
+		if applied == nil {
+			log.Warnf("%s is not managed by ksonnet; skipping", describeObject(obj))
+			continue
+		}
+
+		fields := compareApplied(applied, live.Object)
+		if len(fields) > 0 {
+			result.Changed = append(result.Changed, newObjectDrift(live, fields))
+		}
+	}
+
+	return result, nil
+}
+
+func newObjectDrift(obj *unstructured.Unstructured, fields []FieldDrift) ObjectDrift {
+	return ObjectDrift{
+		GroupVersionKind: obj.GroupVersionKind(),
+		Namespace:        obj.GetNamespace(),
+		Name:             obj.GetName(),
+		Fields:           fields,
+	}
+}
+
+// pristineObject decodes the pristine copy of an object from its managed
+// annotation. It returns nil if the object does not have the annotation.
+func pristineObject(obj *unstructured.Unstructured) (map[string]interface{}, error) {
+	data, ok := obj.GetAnnotations()[metadata.AnnotationManaged]
+	if !ok {
+		return nil, nil
+	}
+
+	var mm managedAnnotation
+	if err := json.Unmarshal([]byte(data), &mm); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return mm.Decode()
+}
+
+// compareApplied compares an applied object with a live object. Status and
+// metadata other than labels and annotations are ignored because they are
+// populated by the server. Only fields set by the applied object are compared,
+// so fields and named list items added by the server are not reported.
+func compareApplied(applied, live map[string]interface{}) []FieldDrift {
+	var fields []FieldDrift
+
+	for _, k := range sortedKeys(applied) {
+		switch k {
+		case "status":
+		case "metadata":
+			appliedMeta, _ := applied[k].(map[string]interface{})
+			liveMeta, _ := live[k].(map[string]interface{})
+
+			for _, mk := range []string{"labels", "annotations"} {
+				a := filterAnnotations(appliedMeta[mk])
+				l := filterAnnotations(liveMeta[mk])
+				fields = compareDriftValues(compare.KeyPath(compare.KeyPath("", k), mk), a, l, fields)
+			}
+		default:
+			fields = compareDriftValues(compare.KeyPath("", k), applied[k], live[k], fields)
+		}
+	}
+
+	return fields
+}
+
+// filterAnnotations removes annotations which are updated on the cluster
+// from a metadata map.
+func filterAnnotations(v interface{}) map[string]interface{} {
+	filtered := make(map[string]interface{})
+
+	m, _ := v.(map[string]interface{})
+	for k, v := range m {
+		if !driftIgnoredAnnotations[k] {
+			filtered[k] = v
+		}
+	}
+
+	return filtered
+}
+
+// compareDriftValues appends the fields of an applied value which differ from
+// the live value.
+func compareDriftValues(path string, applied, live interface{}, fields []FieldDrift) []FieldDrift {
+	for _, c := range compare.Values(path, applied, live, compare.FirstKeysOnly()) {
+		fields = append(fields, FieldDrift{Path: c.Path, Applied: c.Old, Live: c.New})
+	}
+
+	return fields
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,193 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func genDriftDeployment() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      "web",
+				"namespace": "default",
+				"labels": map[string]interface{}{
+					"app": "web",
+				},
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(2),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "web",
+								"image": "nginx:1.15",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// genDriftLive returns a live copy of an object as it would be after
+// ksonnet applied it and the server added its defaults.
+func genDriftLive(t *testing.T, obj *unstructured.Unstructured, edit func(*unstructured.Unstructured)) *unstructured.Unstructured {
+	live := obj.DeepCopy()
+	require.NoError(t, newDefaultAnnotationApplier().SetOriginalConfiguration(live))
+
+	live.SetUID("uid")
+	live.SetResourceVersion("1234")
+	require.NoError(t, unstructured.SetNestedField(live.Object, "RollingUpdate", "spec", "strategy", "type"))
+	require.NoError(t, unstructured.SetNestedField(live.Object, int64(2), "status", "replicas"))
+
+	if edit != nil {
+		edit(live)
+	}
+
+	return live
+}
+
+func TestDrift(t *testing.T) {
+	deployment := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+	cases := []struct {
+		name     string
+		live     func(t *testing.T) (*unstructured.Unstructured, error)
+		expected *DriftResult
+		isErr    bool
+	}{
+		{
+			name: "unchanged",
+			live: func(t *testing.T) (*unstructured.Unstructured, error) {
+				return genDriftLive(t, genDriftDeployment(), nil), nil
+			},
+			expected: &DriftResult{},
+		},
+		{
+			name: "changed out-of-band",
+			live: func(t *testing.T) (*unstructured.Unstructured, error) {
+				return genDriftLive(t, genDriftDeployment(), func(live *unstructured.Unstructured) {
+					live.Object["spec"].(map[string]interface{})["replicas"] = int64(5)
+					containers, _, _ := unstructured.NestedSlice(live.Object, "spec", "template", "spec", "containers")
+					containers[0].(map[string]interface{})["image"] = "nginx:1.16"
+					// Containers injected on the cluster are not drift.
+					containers = append(containers, map[string]interface{}{"name": "debug", "image": "busybox"})
+					unstructured.SetNestedSlice(live.Object, containers, "spec", "template", "spec", "containers")
+					unstructured.RemoveNestedField(live.Object, "metadata", "labels", "app")
+				}), nil
+			},
+			expected: &DriftResult{
+				Changed: []ObjectDrift{
+					{
+						GroupVersionKind: deployment,
+						Namespace:        "default",
+						Name:             "web",
+						Fields: []FieldDrift{
+							{Path: "metadata.labels.app", Applied: "web"},
+							{Path: "spec.replicas", Applied: float64(2), Live: int64(5)},
+							{Path: "spec.template.spec.containers[name=web].image", Applied: "nginx:1.15", Live: "nginx:1.16"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "deleted",
+			live: func(t *testing.T) (*unstructured.Unstructured, error) {
+				return nil, kerrors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments"}, "web")
+			},
+			expected: &DriftResult{
+				Deleted: []ObjectDrift{
+					{GroupVersionKind: deployment, Namespace: "default", Name: "web"},
+				},
+			},
+		},
+		{
+			name: "not managed by ksonnet",
+			live: func(t *testing.T) (*unstructured.Unstructured, error) {
+				return genDriftDeployment(), nil
+			},
+			expected: &DriftResult{},
+		},
+		{
+			name: "fetch failure",
+			live: func(t *testing.T) (*unstructured.Unstructured, error) {
+				return nil, errors.New("failed")
+			},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
+				config := DriftConfig{
+					App:          a,
+					ClientConfig: &client.Config{},
+					EnvName:      "default",
+				}
+
+				setup := func(d *Drift) {
+					d.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+						return []*unstructured.Unstructured{genDriftDeployment()}, nil
+					}
+
+					d.genClientOptsFn = func(a app.App, c *client.Config, envName string) (Clients, error) {
+						return Clients{}, nil
+					}
+
+					d.resourceClientFactory = func(co Clients, object runtime.Object) (ResourceClient, error) {
+						live, err := tc.live(t)
+
+						rc := &mocks.ResourceClient{}
+						rc.On("Get", mock.Anything).Return(live, err)
+						return rc, nil
+					}
+				}
+
+				result, err := RunDrift(config, setup)
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected, result)
+				require.Equal(t, len(tc.expected.Changed)+len(tc.expected.Deleted) > 0, result.HasDrift())
+			})
+		})
+	}
+}
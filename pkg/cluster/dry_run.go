@@ -0,0 +1,132 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// dryRunReport collects the actions an apply would take without changing
+// the cluster.
+type dryRunReport struct {
+	out  io.Writer
+	rows [][]string
+}
+
+func newDryRunReport(out io.Writer) *dryRunReport {
+	return &dryRunReport{
+		out: out,
+	}
+}
+
+// add records the action which would be taken for an object.
+func (r *dryRunReport) add(action ApplyAction, desc string) {
+	r.rows = append(r.rows, []string{string(action), desc})
+}
+
+// Render writes the summary of actions as a table.
+func (r *dryRunReport) Render() error {
+	t := table.New("applyDryRun", r.out)
+	t.SetHeader([]string{"action", "object"})
+	t.AppendBulk(r.rows)
+
+	return t.Render()
+}
+
+// previewPatch computes the result of sending an object as a merge patch to its live
+// version. This mirrors the patch sent by the upserter.
+func previewPatch(live, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	liveData, err := json.Marshal(live)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling live object")
+	}
+
+	patchData, err := json.Marshal(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling patch")
+	}
+
+	patchedData, err := jsonpatch.MergePatch(liveData, patchData)
+	if err != nil {
+		return nil, errors.Wrap(err, "applying merge patch")
+	}
+
+	patched := &unstructured.Unstructured{}
+	if err := patched.UnmarshalJSON(patchedData); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling patched object")
+	}
+
+	return patched, nil
+}
+
+// writeObjectDiff writes a unified diff between two versions of an object. A nil
+// object is treated as not existing. It returns true if the versions differ.
+func writeObjectDiff(w io.Writer, desc string, from, to *unstructured.Unstructured) (bool, error) {
+	fromYAML, err := objectYAML(from)
+	if err != nil {
+		return false, err
+	}
+
+	toYAML, err := objectYAML(to)
+	if err != nil {
+		return false, err
+	}
+
+	if fromYAML == toYAML {
+		return false, nil
+	}
+
+	fromFile := "/dev/null"
+	if from != nil {
+		fromFile = fmt.Sprintf("live/%s", desc)
+	}
+
+	ud := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(fromYAML),
+		B:        difflib.SplitLines(toYAML),
+		FromFile: fromFile,
+		ToFile:   fmt.Sprintf("applied/%s", desc),
+		Context:  3,
+	}
+
+	if err := difflib.WriteUnifiedDiff(w, ud); err != nil {
+		return false, errors.Wrapf(err, "writing diff for %s", desc)
+	}
+
+	return true, nil
+}
+
+func objectYAML(obj *unstructured.Unstructured) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", errors.Wrap(err, "converting object to YAML")
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,341 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// historySecretPrefix is the prefix for the name of the secret which holds
+	// the revisions of an environment.
+	historySecretPrefix = "ksonnet-history."
+
+	// historyRevisionPrefix is the prefix for the keys of revisions in the
+	// history secret.
+	historyRevisionPrefix = "revision-"
+
+	// historyLimit is how many revisions are kept for an environment.
+	historyLimit = 10
+
+	// historyMaxSize is how many bytes of encoded revisions are kept for an
+	// environment. Secrets are limited to 1MiB, so room is left for metadata.
+	historyMaxSize = 900 * 1024
+)
+
+var (
+	// reInvalidSecretName matches characters which can't be used in a secret name.
+	reInvalidSecretName = regexp.MustCompile(`[^a-z0-9.-]+`)
+)
+
+// Revision is a set of objects which was applied to an environment.
+type Revision struct {
+	// Number is the revision number. Revisions are numbered from 1.
+	Number int `json:"number"`
+	// AppliedAt is when the revision was applied.
+	AppliedAt time.Time `json:"appliedAt"`
+	// Description describes how the revision was applied.
+	Description string `json:"description"`
+	// Objects are the objects as they were rendered by the environment.
+	Objects []*unstructured.Unstructured `json:"objects"`
+}
+
+// revisionHistory records and retrieves the revisions of an environment.
+type revisionHistory interface {
+	// Revisions returns the revisions, oldest first.
+	Revisions() ([]Revision, error)
+	// Record records a set of objects as a new revision.
+	Record(description string, objects []*unstructured.Unstructured) (*Revision, error)
+}
+
+type historyFactoryFn func(co Clients, envName string) revisionHistory
+
+func defaultHistoryFactory(co Clients, envName string) revisionHistory {
+	return newSecretHistory(co, resourceClientFactory, envName)
+}
+
+// secretHistory is the default implementation of revisionHistory. Revisions
+// are stored as gzip compressed JSON in a secret in the environment's
+// namespace. Only the latest revisions which fit in the secret are kept.
+type secretHistory struct {
+	// clientOpts are Kubernetes client options.
+	clientOpts Clients
+
+	// resourceClientFactory is a factory for creating clients for resources.
+	resourceClientFactory resourceClientFactoryFn
+
+	// envName is the name of the environment.
+	envName string
+
+	// limit is how many revisions are kept.
+	limit int
+
+	// maxSize is how many bytes of encoded revisions are kept.
+	maxSize int
+
+	// nowFn returns the current time.
+	nowFn func() time.Time
+}
+
+var _ revisionHistory = (*secretHistory)(nil)
+
+// newSecretHistory creates an instance of secretHistory.
+func newSecretHistory(co Clients, rcf resourceClientFactoryFn, envName string) *secretHistory {
+	return &secretHistory{
+		clientOpts:            co,
+		resourceClientFactory: rcf,
+		envName:               envName,
+		limit:                 historyLimit,
+		maxSize:               historyMaxSize,
+		nowFn:                 time.Now,
+	}
+}
+
+// Revisions returns the revisions, oldest first. An environment which has never been
+// applied has no revisions.
+func (h *secretHistory) Revisions() ([]Revision, error) {
+	_, revisions, _, err := h.load()
+	return revisions, err
+}
+
+// Record records a set of objects as a new revision. The oldest revisions are
+// removed once there are more than the limit, or once they no longer fit in
+// the secret.
+func (h *secretHistory) Record(description string, objects []*unstructured.Unstructured) (*Revision, error) {
+	live, revisions, sizes, err := h.load()
+	if err != nil {
+		return nil, err
+	}
+
+	revision := &Revision{
+		Number:      1,
+		AppliedAt:   h.nowFn().UTC(),
+		Description: description,
+		Objects:     objects,
+	}
+	if len(revisions) > 0 {
+		revision.Number = revisions[len(revisions)-1].Number + 1
+	}
+
+	b, err := gzipJSON(revision)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding revision")
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(b)
+	if len(encoded) > h.maxSize {
+		return nil, errors.Errorf("revision %d is %d bytes, which is larger than the history limit of %d bytes",
+			revision.Number, len(encoded), h.maxSize)
+	}
+
+	data := map[string]interface{}{
+		revisionKey(revision.Number): encoded,
+	}
+
+	secret := h.secret()
+	secret.Object["type"] = "Opaque"
+	secret.Object["data"] = data
+
+	rc, err := h.resourceClientFactory(h.clientOpts, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if live == nil {
+		if _, err = rc.Create(); err != nil {
+			return nil, errors.Wrap(err, "creating history")
+		}
+
+		return revision, nil
+	}
+
+	// Keep the newest revisions which fit. Once one doesn't, all older
+	// revisions are removed as well.
+	kept, size, full := 1, len(encoded), false
+	for i := len(revisions) - 1; i >= 0; i-- {
+		number := revisions[i].Number
+		if !full && kept < h.limit && size+sizes[number] <= h.maxSize {
+			kept++
+			size += sizes[number]
+			continue
+		}
+
+		full = true
+		// Patching a key to null removes it.
+		data[revisionKey(number)] = nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"data": data})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = rc.Patch(types.MergePatchType, patch); err != nil {
+		return nil, errors.Wrap(err, "updating history")
+	}
+
+	return revision, nil
+}
+
+// load retrieves the history secret and decodes its revisions. The secret is nil
+// if it does not exist. The encoded size of each revision is returned by number.
+func (h *secretHistory) load() (*unstructured.Unstructured, []Revision, map[int]int, error) {
+	rc, err := h.resourceClientFactory(h.clientOpts, h.secret())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	live, err := rc.Get(metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(errors.Cause(err)) {
+			return nil, nil, nil, nil
+		}
+		return nil, nil, nil, errors.Wrapf(err, "retrieving history for environment %q", h.envName)
+	}
+
+	data, _, err := unstructured.NestedStringMap(live.Object, "data")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var revisions []Revision
+	sizes := make(map[int]int)
+	for key, value := range data {
+		if !strings.HasPrefix(key, historyRevisionPrefix) {
+			continue
+		}
+
+		b, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "decoding %s", key)
+		}
+
+		var revision Revision
+		if err := gunzipJSON(b, &revision); err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "decoding %s", key)
+		}
+
+		revisions = append(revisions, revision)
+		sizes[revision.Number] = len(value)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Number < revisions[j].Number
+	})
+
+	return live, revisions, sizes, nil
+}
+
+// secret returns the history secret without its data.
+func (h *secretHistory) secret() *unstructured.Unstructured {
+	secret := &unstructured.Unstructured{}
+	secret.SetAPIVersion("v1")
+	secret.SetKind("Secret")
+	secret.SetName(historySecretName(h.envName))
+	secret.SetLabels(map[string]string{
+		metadata.LabelDeployManager: appKsonnet,
+	})
+
+	return secret
+}
+
+// historySecretName returns the name of the history secret for an environment.
+// Nested environment names are separated by dots.
+func historySecretName(envName string) string {
+	name := strings.Replace(strings.ToLower(envName), "/", ".", -1)
+	return historySecretPrefix + reInvalidSecretName.ReplaceAllString(name, "-")
+}
+
+func revisionKey(number int) string {
+	return historyRevisionPrefix + strconv.Itoa(number)
+}
+
+// copyObjects copies objects so they are not changed while they are applied.
+func copyObjects(objects []*unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	copies := make([]*unstructured.Unstructured, len(objects))
+	for i, obj := range objects {
+		b, err := obj.MarshalJSON()
+		if err != nil {
+			return nil, errors.Wrapf(err, "copying %s", describeObject(obj))
+		}
+
+		copies[i] = &unstructured.Unstructured{}
+		if err := copies[i].UnmarshalJSON(b); err != nil {
+			return nil, errors.Wrapf(err, "copying %s", describeObject(obj))
+		}
+	}
+
+	return copies, nil
+}
+
+// HistoryConfig is configuration for History.
+type HistoryConfig struct {
+	App          app.App
+	ClientConfig *client.Config
+	EnvName      string
+}
+
+// HistoryOpts is an option for configuring History.
+type HistoryOpts func(*History)
+
+// History lists the revisions applied to an environment.
+type History struct {
+	HistoryConfig
+
+	// these make it easier to test History.
+	genClientOptsFn genClientOptsFn
+	historyFactory  historyFactoryFn
+}
+
+// RunHistory returns the revisions applied to an environment, oldest first.
+func RunHistory(config HistoryConfig, opts ...HistoryOpts) ([]Revision, error) {
+	h := &History{
+		HistoryConfig:   config,
+		genClientOptsFn: GenClients,
+		historyFactory:  defaultHistoryFactory,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h.History()
+}
+
+// History returns the revisions applied to an environment.
+func (h *History) History() ([]Revision, error) {
+	co, err := h.genClientOptsFn(h.App, h.ClientConfig, h.EnvName)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.historyFactory(co, h.EnvName).Revisions()
+}
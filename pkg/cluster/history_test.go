@@ -0,0 +1,245 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/cluster/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func encodeRevision(t *testing.T, revision Revision) string {
+	b, err := gzipJSON(revision)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func genHistorySecret(t *testing.T, numbers ...int) *unstructured.Unstructured {
+	data := make(map[string]interface{})
+	for _, n := range numbers {
+		data[revisionKey(n)] = encodeRevision(t, Revision{
+			Number:  n,
+			Objects: []*unstructured.Unstructured{genHook(fmt.Sprintf("job-%d", n), nil)},
+		})
+	}
+
+	secret := &unstructured.Unstructured{}
+	secret.SetAPIVersion("v1")
+	secret.SetKind("Secret")
+	secret.SetName("ksonnet-history.default")
+	secret.Object["data"] = data
+
+	return secret
+}
+
+func Test_secretHistory_Record(t *testing.T) {
+	now := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// sizeOf returns the encoded size of revisions in the history secret.
+	sizeOf := func(numbers ...int) int {
+		size := 0
+		for _, n := range numbers {
+			size += len(genHistorySecret(t, n).Object["data"].(map[string]interface{})[revisionKey(n)].(string))
+		}
+		return size
+	}
+
+	newSize := len(encodeRevision(t, Revision{
+		Number:      6,
+		AppliedAt:   now,
+		Description: "apply",
+		Objects:     []*unstructured.Unstructured{genHook("migrate", nil)},
+	}))
+
+	cases := []struct {
+		name          string
+		live          *unstructured.Unstructured
+		limit         int
+		maxSize       int
+		expectedKey   string
+		expectRemoved []string
+		isErr         bool
+	}{
+		{
+			name:        "first revision",
+			expectedKey: "revision-1",
+		},
+		{
+			name:        "next revision",
+			live:        genHistorySecret(t, 1),
+			expectedKey: "revision-2",
+		},
+		{
+			name:          "oldest revisions are removed",
+			live:          genHistorySecret(t, 3, 4, 5),
+			expectedKey:   "revision-6",
+			expectRemoved: []string{"revision-3", "revision-4"},
+		},
+		{
+			name:          "oldest revisions which don't fit are removed",
+			live:          genHistorySecret(t, 3, 4, 5),
+			limit:         10,
+			maxSize:       newSize + sizeOf(5),
+			expectedKey:   "revision-6",
+			expectRemoved: []string{"revision-3", "revision-4"},
+		},
+		{
+			name:    "revision is too large",
+			live:    genHistorySecret(t, 3),
+			maxSize: newSize - 1,
+			isErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var created *unstructured.Unstructured
+			var patch map[string]interface{}
+
+			rcf := func(co Clients, object runtime.Object) (ResourceClient, error) {
+				obj := object.(*unstructured.Unstructured)
+				require.Equal(t, "ksonnet-history.default", obj.GetName())
+
+				rc := &mocks.ResourceClient{}
+				if tc.live == nil {
+					rc.On("Get", mock.Anything).Return(nil, &notFoundError{})
+				} else {
+					rc.On("Get", mock.Anything).Return(tc.live, nil)
+				}
+				rc.On("Create").Run(func(mock.Arguments) {
+					created = obj
+				}).Return(obj, nil)
+				rc.On("Patch", types.MergePatchType, mock.Anything).Run(func(args mock.Arguments) {
+					require.NoError(t, json.Unmarshal(args.Get(1).([]byte), &patch))
+				}).Return(obj, nil)
+				return rc, nil
+			}
+
+			h := newSecretHistory(Clients{}, rcf, "default")
+			h.limit = 2
+			if tc.limit > 0 {
+				h.limit = tc.limit
+			}
+			if tc.maxSize > 0 {
+				h.maxSize = tc.maxSize
+			}
+			h.nowFn = func() time.Time { return now }
+
+			objects := []*unstructured.Unstructured{genHook("migrate", nil)}
+			revision, err := h.Record("apply", objects)
+			if tc.isErr {
+				require.Error(t, err)
+				require.Nil(t, patch)
+				return
+			}
+			require.NoError(t, err)
+
+			var data map[string]interface{}
+			if tc.live == nil {
+				require.NotNil(t, created)
+				data = created.Object["data"].(map[string]interface{})
+			} else {
+				require.NotNil(t, patch)
+				data = patch["data"].(map[string]interface{})
+			}
+
+			encoded, ok := data[tc.expectedKey].(string)
+			require.True(t, ok, "revision %s was not stored", tc.expectedKey)
+
+			b, err := base64.StdEncoding.DecodeString(encoded)
+			require.NoError(t, err)
+
+			var stored Revision
+			require.NoError(t, gunzipJSON(b, &stored))
+			require.Equal(t, *revision, stored)
+			require.Equal(t, now, stored.AppliedAt)
+			require.Equal(t, "apply", stored.Description)
+			require.Equal(t, []string{"migrate"}, objectNames(stored.Objects))
+
+			for _, key := range tc.expectRemoved {
+				v, ok := data[key]
+				require.True(t, ok, "revision %s was not removed", key)
+				require.Nil(t, v)
+			}
+			require.Len(t, data, len(tc.expectRemoved)+1)
+		})
+	}
+}
+
+func Test_secretHistory_Revisions(t *testing.T) {
+	rcf := func(co Clients, object runtime.Object) (ResourceClient, error) {
+		rc := &mocks.ResourceClient{}
+		rc.On("Get", mock.Anything).Return(genHistorySecret(t, 10, 2, 9), nil)
+		return rc, nil
+	}
+
+	h := newSecretHistory(Clients{}, rcf, "default")
+
+	revisions, err := h.Revisions()
+	require.NoError(t, err)
+
+	var numbers []int
+	for _, revision := range revisions {
+		numbers = append(numbers, revision.Number)
+	}
+	require.Equal(t, []int{2, 9, 10}, numbers)
+	require.Equal(t, []string{"job-10"}, objectNames(revisions[2].Objects))
+}
+
+func Test_historySecretName(t *testing.T) {
+	require.Equal(t, "ksonnet-history.default", historySecretName("default"))
+	require.Equal(t, "ksonnet-history.us-west.prod", historySecretName("us-west/prod"))
+	require.Equal(t, "ksonnet-history.my-env", historySecretName("My_Env"))
+}
+
+type fakeHistory struct {
+	revisions []Revision
+	err       error
+}
+
+var _ revisionHistory = (*fakeHistory)(nil)
+
+func (h *fakeHistory) factory(Clients, string) revisionHistory {
+	return h
+}
+
+func (h *fakeHistory) Revisions() ([]Revision, error) {
+	return h.revisions, nil
+}
+
+func (h *fakeHistory) Record(description string, objects []*unstructured.Unstructured) (*Revision, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+
+	revision := Revision{
+		Number:      len(h.revisions) + 1,
+		Description: description,
+		Objects:     objects,
+	}
+	h.revisions = append(h.revisions, revision)
+
+	return &revision, nil
+}
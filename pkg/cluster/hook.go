@@ -0,0 +1,244 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// defaultHookPollInterval is how often a removed hook is checked until it
+	// no longer exists.
+	defaultHookPollInterval = 1 * time.Second
+)
+
+var (
+	hookPhases = []string{
+		metadata.PhasePreApply,
+		metadata.PhasePostApply,
+		metadata.PhasePreDelete,
+	}
+
+	hookDeletePolicies = []string{
+		metadata.HookDeletePolicySucceeded,
+		metadata.HookDeletePolicyAlways,
+		metadata.HookDeletePolicyNever,
+	}
+)
+
+// splitHooks separates hook objects from the other objects. Hooks are grouped
+// by phase and ordered by weight. Hooks with the same weight keep their order.
+func splitHooks(objects []*unstructured.Unstructured) ([]*unstructured.Unstructured, map[string][]*unstructured.Unstructured, error) {
+	var others []*unstructured.Unstructured
+	hooks := make(map[string][]*unstructured.Unstructured)
+
+	for _, obj := range objects {
+		annotations := obj.GetAnnotations()
+
+		phase, ok := annotations[metadata.AnnotationPhase]
+		if !ok {
+			others = append(others, obj)
+			continue
+		}
+
+		desc := describeObject(obj)
+
+		if !stringListContains(hookPhases, phase) {
+			return nil, nil, errors.Errorf("%s has unknown phase %q", desc, phase)
+		}
+
+		if policy, ok := annotations[metadata.AnnotationHookDeletePolicy]; ok && !stringListContains(hookDeletePolicies, policy) {
+			return nil, nil, errors.Errorf("%s has unknown hook delete policy %q", desc, policy)
+		}
+
+		if _, err := hookWeight(obj); err != nil {
+			return nil, nil, errors.Wrapf(err, "%s has invalid hook weight", desc)
+		}
+
+		hooks[phase] = append(hooks[phase], obj)
+	}
+
+	for _, phaseHooks := range hooks {
+		sort.SliceStable(phaseHooks, func(i, j int) bool {
+			wi, _ := hookWeight(phaseHooks[i])
+			wj, _ := hookWeight(phaseHooks[j])
+			return wi < wj
+		})
+	}
+
+	return others, hooks, nil
+}
+
+// hookWeight returns the weight of a hook. Hooks without a weight have a weight of 0.
+func hookWeight(obj *unstructured.Unstructured) (int, error) {
+	weight, ok := obj.GetAnnotations()[metadata.AnnotationHookWeight]
+	if !ok {
+		return 0, nil
+	}
+
+	return strconv.Atoi(weight)
+}
+
+// hookDeletable returns true if a hook should be deleted after it has run.
+func hookDeletable(obj *unstructured.Unstructured, succeeded bool) bool {
+	switch obj.GetAnnotations()[metadata.AnnotationHookDeletePolicy] {
+	case metadata.HookDeletePolicyAlways:
+		return true
+	case metadata.HookDeletePolicyNever:
+		return false
+	default:
+		return succeeded
+	}
+}
+
+// hookRunner runs hooks.
+type hookRunner interface {
+	// Run runs the hooks for a phase in order. It returns the result of
+	// running each hook.
+	Run(phase string, hooks []*unstructured.Unstructured) ([]ObjectResult, error)
+}
+
+// defaultHookRunner is the default implementation of hookRunner. Each hook is
+// created, waited on until it is ready, and then deleted according to its
+// delete policy. Jobs are ready once they have completed.
+type defaultHookRunner struct {
+	// clientOpts are Kubernetes client options.
+	clientOpts Clients
+
+	// resourceClientFactory is a factory for creating clients for resources.
+	resourceClientFactory resourceClientFactoryFn
+
+	// upserter creates hooks.
+	upserter Upserter
+
+	// waiter waits for hooks to become ready.
+	waiter objectWaiter
+
+	// timeout is how long to wait for a removed hook to no longer exist.
+	timeout time.Duration
+
+	// pollInterval is how often a removed hook is checked.
+	pollInterval time.Duration
+}
+
+var _ hookRunner = (*defaultHookRunner)(nil)
+
+// newDefaultHookRunner creates an instance of defaultHookRunner.
+func newDefaultHookRunner(co Clients, rcf resourceClientFactoryFn, u Upserter, w objectWaiter, timeout time.Duration) *defaultHookRunner {
+	if timeout <= 0 {
+		timeout = defaultWaitTimeout
+	}
+
+	return &defaultHookRunner{
+		clientOpts:            co,
+		resourceClientFactory: rcf,
+		upserter:              u,
+		waiter:                w,
+		timeout:               timeout,
+		pollInterval:          defaultHookPollInterval,
+	}
+}
+
+// Run runs the hooks for a phase in order. It stops at the first hook which fails.
+func (h *defaultHookRunner) Run(phase string, hooks []*unstructured.Unstructured) ([]ObjectResult, error) {
+	var results []ObjectResult
+
+	for _, hook := range hooks {
+		log.Infof("Running %s hook %s", phase, describeObject(hook))
+
+		result, err := h.run(hook)
+		if err != nil {
+			return nil, errors.Wrapf(err, "running %s", describeObject(hook))
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (h *defaultHookRunner) run(hook *unstructured.Unstructured) (ObjectResult, error) {
+	started := time.Now()
+
+	// A hook left behind by an earlier run is removed, so the hook runs every time.
+	if err := h.remove(hook); err != nil {
+		return ObjectResult{}, errors.Wrap(err, "removing previous run")
+	}
+
+	uid, _, err := h.upserter.Upsert(hook)
+	if err != nil {
+		return ObjectResult{}, err
+	}
+
+	waitErr := h.waiter.Wait([]*unstructured.Unstructured{hook})
+
+	if hookDeletable(hook, waitErr == nil) {
+		if err = h.remove(hook); err != nil {
+			return ObjectResult{}, errors.Wrap(err, "removing hook")
+		}
+	}
+
+	if waitErr != nil {
+		return ObjectResult{}, waitErr
+	}
+
+	return newObjectResult(hook, ApplyActionHook, uid, started), nil
+}
+
+// remove deletes a hook and waits until it no longer exists.
+func (h *defaultHookRunner) remove(hook *unstructured.Unstructured) error {
+	rc, err := h.resourceClientFactory(h.clientOpts, hook)
+	if err != nil {
+		return err
+	}
+
+	// Dependents, such as the pods of a job, are removed in the background.
+	propagation := metav1.DeletePropagationBackground
+	err = rc.Delete(&metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil {
+		if kerrors.IsNotFound(errors.Cause(err)) {
+			return nil
+		}
+		return err
+	}
+
+	deadline := time.Now().Add(h.timeout)
+
+	for {
+		_, err = rc.Get(metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(errors.Cause(err)) {
+				return nil
+			}
+			return err
+		}
+
+		if time.Now().After(deadline) {
+			return errors.Errorf("timed out after %s waiting for %s to be deleted", h.timeout, describeObject(hook))
+		}
+
+		time.Sleep(h.pollInterval)
+	}
+}
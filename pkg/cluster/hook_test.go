@@ -0,0 +1,229 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/cluster/mocks"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func genHook(name string, annotations map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("batch/v1")
+	obj.SetKind("Job")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+func objectNames(objects []*unstructured.Unstructured) []string {
+	var names []string
+	for _, obj := range objects {
+		names = append(names, obj.GetName())
+	}
+	return names
+}
+
+func Test_splitHooks(t *testing.T) {
+	cases := []struct {
+		name    string
+		objects []*unstructured.Unstructured
+		others  []string
+		hooks   map[string][]string
+		isErr   bool
+	}{
+		{
+			name: "in general",
+			objects: []*unstructured.Unstructured{
+				genHook("web", nil),
+				genHook("migrate", map[string]string{
+					metadata.AnnotationPhase:      metadata.PhasePreApply,
+					metadata.AnnotationHookWeight: "5",
+				}),
+				genHook("backup", map[string]string{
+					metadata.AnnotationPhase:      metadata.PhasePreApply,
+					metadata.AnnotationHookWeight: "-1",
+				}),
+				genHook("notify", map[string]string{
+					metadata.AnnotationPhase:            metadata.PhasePostApply,
+					metadata.AnnotationHookDeletePolicy: metadata.HookDeletePolicyNever,
+				}),
+				genHook("drain", map[string]string{
+					metadata.AnnotationPhase: metadata.PhasePreDelete,
+				}),
+			},
+			others: []string{"web"},
+			hooks: map[string][]string{
+				metadata.PhasePreApply:  {"backup", "migrate"},
+				metadata.PhasePostApply: {"notify"},
+				metadata.PhasePreDelete: {"drain"},
+			},
+		},
+		{
+			name: "unknown phase",
+			objects: []*unstructured.Unstructured{
+				genHook("migrate", map[string]string{metadata.AnnotationPhase: "post-delete"}),
+			},
+			isErr: true,
+		},
+		{
+			name: "unknown delete policy",
+			objects: []*unstructured.Unstructured{
+				genHook("migrate", map[string]string{
+					metadata.AnnotationPhase:            metadata.PhasePreApply,
+					metadata.AnnotationHookDeletePolicy: "sometimes",
+				}),
+			},
+			isErr: true,
+		},
+		{
+			name: "invalid weight",
+			objects: []*unstructured.Unstructured{
+				genHook("migrate", map[string]string{
+					metadata.AnnotationPhase:      metadata.PhasePreApply,
+					metadata.AnnotationHookWeight: "first",
+				}),
+			},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			others, hooks, err := splitHooks(tc.objects)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, tc.others, objectNames(others))
+
+			got := make(map[string][]string)
+			for phase, phaseHooks := range hooks {
+				got[phase] = objectNames(phaseHooks)
+			}
+			require.Equal(t, tc.hooks, got)
+		})
+	}
+}
+
+func Test_defaultHookRunner_Run(t *testing.T) {
+	cases := []struct {
+		name    string
+		policy  string
+		waitErr error
+		deletes int
+		isErr   bool
+	}{
+		{
+			name:    "succeeded with default policy",
+			deletes: 2,
+		},
+		{
+			name:    "failed with default policy",
+			waitErr: errors.New("job failed"),
+			deletes: 1,
+			isErr:   true,
+		},
+		{
+			name:    "failed with always policy",
+			policy:  metadata.HookDeletePolicyAlways,
+			waitErr: errors.New("job failed"),
+			deletes: 2,
+			isErr:   true,
+		},
+		{
+			name:    "succeeded with never policy",
+			policy:  metadata.HookDeletePolicyNever,
+			deletes: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			annotations := map[string]string{metadata.AnnotationPhase: metadata.PhasePreApply}
+			if tc.policy != "" {
+				annotations[metadata.AnnotationHookDeletePolicy] = tc.policy
+			}
+			hook := genHook("migrate", annotations)
+
+			var calls []string
+
+			rcf := func(co Clients, object runtime.Object) (ResourceClient, error) {
+				rc := &mocks.ResourceClient{}
+				rc.On("Delete", mock.Anything).Run(func(mock.Arguments) {
+					calls = append(calls, "delete")
+				}).Return(nil)
+				rc.On("Get", mock.Anything).Return(nil, &notFoundError{})
+				return rc, nil
+			}
+
+			u := &funcUpserter{
+				upsertFn: func(obj *unstructured.Unstructured) (string, error) {
+					calls = append(calls, "create")
+					return "12345", nil
+				},
+			}
+
+			w := &fakeObjectWaiter{
+				waitFn: func(objects []*unstructured.Unstructured) error {
+					calls = append(calls, "wait")
+					return tc.waitErr
+				},
+			}
+
+			hr := newDefaultHookRunner(Clients{}, rcf, u, w, 0)
+
+			results, err := hr.Run(metadata.PhasePreApply, []*unstructured.Unstructured{hook})
+
+			expected := []string{"delete", "create", "wait"}
+			if tc.deletes == 2 {
+				expected = append(expected, "delete")
+			}
+			require.Equal(t, expected, calls)
+
+			if tc.isErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "running job default.migrate: job failed")
+				return
+			}
+			require.NoError(t, err)
+
+			require.Len(t, results, 1)
+			require.Equal(t, ApplyActionHook, results[0].Action)
+			require.Equal(t, "12345", results[0].UID)
+		})
+	}
+}
+
+type fakeHookRunner struct {
+	runFn func(phase string, hooks []*unstructured.Unstructured) ([]ObjectResult, error)
+}
+
+var _ hookRunner = (*fakeHookRunner)(nil)
+
+func (hr *fakeHookRunner) Run(phase string, hooks []*unstructured.Unstructured) ([]ObjectResult, error) {
+	return hr.runFn(phase, hooks)
+}
@@ -36,20 +36,12 @@ func (mm *managedAnnotation) Marshal() ([]byte, error) {
 
 // Encode encodes a pristine copy of the object.
 func (mm *managedAnnotation) Encode(m map[string]interface{}) error {
-	var buf bytes.Buffer
-	gz := gzip.NewWriter(&buf)
-
-	actions := []serial.Action{
-		func() error { return json.NewEncoder(gz).Encode(m) },
-		gz.Flush,
-		gz.Close,
-	}
-
-	if err := serial.RunActions(actions...); err != nil {
+	b, err := gzipJSON(m)
+	if err != nil {
 		return err
 	}
 
-	mm.Pristine = base64.StdEncoding.EncodeToString(buf.Bytes())
+	mm.Pristine = base64.StdEncoding.EncodeToString(b)
 	return nil
 }
 
@@ -60,18 +52,39 @@ func (mm *managedAnnotation) Decode() (map[string]interface{}, error) {
 		return nil, err
 	}
 
-	r := bytes.NewReader(b)
-
-	zr, err := gzip.NewReader(r)
-	if err != nil {
+	var m map[string]interface{}
+	if err := gunzipJSON(b, &m); err != nil {
 		return nil, err
 	}
-	defer zr.Close()
 
-	var m map[string]interface{}
-	if err := json.NewDecoder(zr).Decode(&m); err != nil {
+	return m, nil
+}
+
+// gzipJSON encodes a value as gzip compressed JSON.
+func gzipJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+
+	actions := []serial.Action{
+		func() error { return json.NewEncoder(gz).Encode(v) },
+		gz.Flush,
+		gz.Close,
+	}
+
+	if err := serial.RunActions(actions...); err != nil {
 		return nil, err
 	}
 
-	return m, nil
+	return buf.Bytes(), nil
+}
+
+// gunzipJSON decodes gzip compressed JSON into a value.
+func gunzipJSON(b []byte, v interface{}) error {
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	return json.NewDecoder(zr).Decode(v)
 }
@@ -17,6 +17,7 @@ package cluster
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/ksonnet/ksonnet/utils"
 	"github.com/pkg/errors"
@@ -56,3 +57,9 @@ func newDefaultObjectDescriber(co Clients, oi ObjectInfo) (*defaultObjectDescrib
 func (od *defaultObjectDescriber) Describe(obj *unstructured.Unstructured) string {
 	return fmt.Sprintf("%s %s", od.objectInfo.ResourceName(od.clientOpts.discovery, obj), utils.FqName(obj))
 }
+
+// describeObject describes an object using its kind and fully qualified name. Unlike
+// objectDescriber, it does not require cluster discovery.
+func describeObject(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s %s", strings.ToLower(obj.GetKind()), utils.FqName(obj))
+}
@@ -0,0 +1,224 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// clusterScopeDir is the directory for objects without a namespace. Namespace
+	// names can't start with an underscore, so it never clashes with a namespace.
+	clusterScopeDir = "_cluster"
+
+	// kustomizationFile is the name of the file listing the objects in a directory
+	// written by dirWriter.
+	kustomizationFile = "kustomization.yaml"
+)
+
+// objectWriter writes objects in an output format.
+type objectWriter interface {
+	// Write writes objects.
+	Write(objects []*unstructured.Unstructured) error
+}
+
+// objectWriterFactory creates an objectWriter for a configuration.
+type objectWriterFactory func(config ShowConfig) (objectWriter, error)
+
+// objectWriters are the output formats for objects.
+var objectWriters = map[string]objectWriterFactory{
+	"components": newComponentsWriter,
+	"dir":        newDirWriter,
+	"json":       newJSONListWriter,
+	"yaml":       newYAMLStreamWriter,
+}
+
+// yamlStreamWriter writes objects as a stream of YAML documents.
+type yamlStreamWriter struct {
+	out io.Writer
+}
+
+func newYAMLStreamWriter(config ShowConfig) (objectWriter, error) {
+	return &yamlStreamWriter{out: config.Out}, nil
+}
+
+func (w *yamlStreamWriter) Write(objects []*unstructured.Unstructured) error {
+	return ShowYAML(w.out, objects)
+}
+
+// jsonListWriter writes objects as a JSON object of kind List.
+type jsonListWriter struct {
+	out io.Writer
+}
+
+func newJSONListWriter(config ShowConfig) (objectWriter, error) {
+	return &jsonListWriter{out: config.Out}, nil
+}
+
+func (w *jsonListWriter) Write(objects []*unstructured.Unstructured) error {
+	enc := json.NewEncoder(w.out)
+	enc.SetIndent("", "  ")
+
+	m := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+	}
+
+	items := make([]interface{}, 0)
+
+	for _, obj := range objects {
+		items = append(items, obj.Object)
+	}
+
+	m["items"] = items
+
+	return enc.Encode(m)
+}
+
+// dirWriter writes each object to its own file in a directory. Files are named
+// <namespace>/<kind>-<name>.yaml. A kustomization.yaml listing every file is
+// written to the directory so it can be used as a kustomize base.
+type dirWriter struct {
+	fs  afero.Fs
+	dir string
+}
+
+func newDirWriter(config ShowConfig) (objectWriter, error) {
+	if config.OutputDir == "" {
+		return nil, errors.New("an output directory is required for the dir format")
+	}
+
+	return &dirWriter{fs: config.App.Fs(), dir: config.OutputDir}, nil
+}
+
+func (w *dirWriter) Write(objects []*unstructured.Unstructured) error {
+	var resources []string
+	seen := make(map[string]bool)
+
+	for _, obj := range objects {
+		path := objectPath(obj)
+		if seen[path] {
+			return errors.Errorf("more than one object is written to %s", path)
+		}
+		seen[path] = true
+
+		if err := writeObjects(w.fs, filepath.Join(w.dir, path), obj); err != nil {
+			return err
+		}
+
+		resources = append(resources, path)
+	}
+
+	b, err := yaml.Marshal(map[string]interface{}{"resources": resources})
+	if err != nil {
+		return err
+	}
+
+	if err = afero.WriteFile(w.fs, filepath.Join(w.dir, kustomizationFile), b, 0644); err != nil {
+		return errors.Wrapf(err, "writing %s", kustomizationFile)
+	}
+
+	log.Infof("Wrote %d object(s) to %s", len(objects), w.dir)
+	return nil
+}
+
+// objectPath returns the path of the file for an object relative to the output
+// directory.
+func objectPath(obj *unstructured.Unstructured) string {
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = clusterScopeDir
+	}
+
+	name := fmt.Sprintf("%s-%s.yaml", strings.ToLower(obj.GetKind()), obj.GetName())
+	return filepath.Join(namespace, name)
+}
+
+// componentsWriter writes the objects of each component to a file named after the
+// component in a directory.
+type componentsWriter struct {
+	fs  afero.Fs
+	dir string
+}
+
+func newComponentsWriter(config ShowConfig) (objectWriter, error) {
+	if config.OutputDir == "" {
+		return nil, errors.New("an output directory is required for the components format")
+	}
+
+	return &componentsWriter{fs: config.App.Fs(), dir: config.OutputDir}, nil
+}
+
+func (w *componentsWriter) Write(objects []*unstructured.Unstructured) error {
+	var names []string
+	components := make(map[string][]*unstructured.Unstructured)
+
+	for _, obj := range objects {
+		name := obj.GetLabels()[metadata.LabelComponent]
+		if name == "" {
+			return errors.Errorf("%s does not belong to a component", describeObject(obj))
+		}
+
+		if _, ok := components[name]; !ok {
+			names = append(names, name)
+		}
+		components[name] = append(components[name], obj)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(w.dir, name+".yaml")
+		if err := writeObjects(w.fs, path, components[name]...); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Wrote %d component(s) to %s", len(names), w.dir)
+	return nil
+}
+
+// writeObjects writes objects as a stream of YAML documents to a file, creating
+// its directory if needed.
+func writeObjects(fs afero.Fs, path string, objects ...*unstructured.Unstructured) error {
+	if err := fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrapf(err, "creating directory for %s", path)
+	}
+
+	var buf bytes.Buffer
+	if err := ShowYAML(&buf, objects); err != nil {
+		return err
+	}
+
+	if err := afero.WriteFile(fs, path, buf.Bytes(), 0644); err != nil {
+		return errors.Wrapf(err, "writing %s", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func genWriterObject(kind, namespace, name, component string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind(kind)
+	obj.SetName(name)
+	if namespace != "" {
+		obj.SetNamespace(namespace)
+	}
+	if component != "" {
+		obj.SetLabels(map[string]string{metadata.LabelComponent: component})
+	}
+	return obj
+}
+
+func readFile(t *testing.T, fs afero.Fs, path string) string {
+	b, err := afero.ReadFile(fs, path)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func Test_dirWriter(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		w, err := newDirWriter(ShowConfig{App: a, OutputDir: "/out"})
+		require.NoError(t, err)
+
+		objects := []*unstructured.Unstructured{
+			genWriterObject("Namespace", "", "web", ""),
+			genWriterObject("Service", "web", "frontend", ""),
+		}
+		require.NoError(t, w.Write(objects))
+
+		require.Equal(t, "---\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: web\n",
+			readFile(t, fs, "/out/_cluster/namespace-web.yaml"))
+		require.Equal(t, "---\napiVersion: v1\nkind: Service\nmetadata:\n  name: frontend\n  namespace: web\n",
+			readFile(t, fs, "/out/web/service-frontend.yaml"))
+		require.Equal(t, "resources:\n- _cluster/namespace-web.yaml\n- web/service-frontend.yaml\n",
+			readFile(t, fs, "/out/kustomization.yaml"))
+	})
+}
+
+func Test_dirWriter_duplicate_objects(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		w, err := newDirWriter(ShowConfig{App: a, OutputDir: "/out"})
+		require.NoError(t, err)
+
+		objects := []*unstructured.Unstructured{
+			genWriterObject("Service", "web", "frontend", ""),
+			genWriterObject("Service", "web", "frontend", ""),
+		}
+		require.Error(t, w.Write(objects))
+	})
+}
+
+func Test_componentsWriter(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		w, err := newComponentsWriter(ShowConfig{App: a, OutputDir: "/out"})
+		require.NoError(t, err)
+
+		objects := []*unstructured.Unstructured{
+			genWriterObject("Deployment", "web", "frontend", "guestbook"),
+			genWriterObject("Service", "web", "redis", "redis"),
+			genWriterObject("Service", "web", "frontend", "guestbook"),
+		}
+		require.NoError(t, w.Write(objects))
+
+		guestbook := readFile(t, fs, "/out/guestbook.yaml")
+		require.Contains(t, guestbook, "kind: Deployment")
+		require.Contains(t, guestbook, "kind: Service")
+		require.NotContains(t, guestbook, "name: redis")
+
+		redis := readFile(t, fs, "/out/redis.yaml")
+		require.Contains(t, redis, "name: redis")
+
+		err = w.Write([]*unstructured.Unstructured{genWriterObject("Service", "web", "frontend", "")})
+		require.Error(t, err)
+	})
+}
+
+func Test_objectWriters_require_output_dir(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		for _, format := range []string{"components", "dir"} {
+			_, err := objectWriters[format](ShowConfig{App: a})
+			require.Error(t, err, format)
+		}
+	})
+}
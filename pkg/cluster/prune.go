@@ -0,0 +1,245 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/util/prompt"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/ksonnet/ksonnet/utils"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	pruneActionPrune  = "prune"
+	pruneActionIgnore = "ignore"
+)
+
+// PruneConfig is configuration for Prune.
+type PruneConfig struct {
+	App          app.App
+	ClientConfig *client.Config
+	DryRun       bool
+	EnvName      string
+	GcTag        string
+	SkipConfirm  bool
+	In           io.Reader
+	Out          io.Writer
+}
+
+// PruneOpts is an option for configuring Prune.
+type PruneOpts func(*Prune)
+
+// Prune deletes garbage collection tagged objects which are no longer
+// rendered by an environment.
+type Prune struct {
+	PruneConfig
+
+	// these make it easier to test Prune.
+	findObjectsFn         findObjectsFn
+	genClientOptsFn       genClientOptsFn
+	resourceClientFactory resourceClientFactoryFn
+	walkObjectsFn         walkObjectsFn
+	serverVersionFn       serverVersionFn
+	isTerminalFn          isTerminalFn
+	objectInfo            ObjectInfo
+}
+
+// RunPrune runs prune against a cluster for a given configuration.
+func RunPrune(config PruneConfig, opts ...PruneOpts) error {
+	if config.GcTag == "" {
+		return errors.New("a garbage collection tag is required to prune objects")
+	}
+
+	if config.In == nil {
+		config.In = os.Stdin
+	}
+
+	if config.Out == nil {
+		config.Out = os.Stdout
+	}
+
+	p := &Prune{
+		PruneConfig:           config,
+		findObjectsFn:         findObjects,
+		genClientOptsFn:       GenClients,
+		resourceClientFactory: resourceClientFactory,
+		walkObjectsFn:         walkObjects,
+		serverVersionFn:       utils.FetchVersion,
+		isTerminalFn:          prompt.IsTerminal,
+		objectInfo:            &objectInfo{},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p.Prune()
+}
+
+// pruneCandidate is an object found in the cluster which is tagged for garbage
+// collection but is not rendered by the environment.
+type pruneCandidate struct {
+	object runtime.Object
+	desc   string
+	action string
+}
+
+// Prune lists objects which can be pruned and deletes them after confirmation.
+func (p *Prune) Prune() error {
+	co, err := p.genClientOptsFn(p.App, p.ClientConfig, p.EnvName)
+	if err != nil {
+		return err
+	}
+
+	seenUids, err := p.renderedUids(co)
+	if err != nil {
+		return err
+	}
+
+	candidates, err := p.candidates(co, seenUids)
+	if err != nil {
+		return err
+	}
+
+	if len(candidates) == 0 {
+		fmt.Fprintln(p.Out, "No objects to prune")
+		return nil
+	}
+
+	t := table.New("prune", p.Out)
+	t.SetHeader([]string{"action", "object"})
+
+	var toPrune []pruneCandidate
+	for _, c := range candidates {
+		t.Append([]string{c.action, c.desc})
+		if c.action == pruneActionPrune {
+			toPrune = append(toPrune, c)
+		}
+	}
+
+	if err = t.Render(); err != nil {
+		return err
+	}
+
+	if p.DryRun || len(toPrune) == 0 {
+		return nil
+	}
+
+	if !p.SkipConfirm {
+		question := fmt.Sprintf("Prune %d object(s) from environment %q?", len(toPrune), p.EnvName)
+		if err = confirm(p.In, p.Out, p.isTerminalFn, question, "prune"); err != nil {
+			return err
+		}
+	}
+
+	version, err := p.serverVersionFn(co.discovery)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range toPrune {
+		log.Info("Garbage collecting ", c.desc)
+		if err = gcDelete(co, p.resourceClientFactory, &version, c.object); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderedUids finds the UIDs of the live versions of the objects rendered by the environment.
+func (p *Prune) renderedUids(co Clients) (sets.String, error) {
+	objects, err := p.findObjectsFn(p.App, p.EnvName, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "find objects")
+	}
+
+	seenUids := sets.NewString()
+
+	for _, obj := range objects {
+		rc, err := p.resourceClientFactory(co, obj)
+		if err != nil {
+			return nil, err
+		}
+
+		live, err := rc.Get(metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "retrieving %s", describeObject(obj))
+		}
+
+		seenUids.Insert(string(live.GetUID()))
+	}
+
+	return seenUids, nil
+}
+
+// candidates finds objects in the cluster tagged with the garbage collection tag which
+// are not rendered. Objects with the ignore garbage collection strategy are listed, but
+// will not be pruned.
+func (p *Prune) candidates(co Clients, seenUids sets.String) ([]pruneCandidate, error) {
+	var candidates []pruneCandidate
+	// Some objects appear under multiple kinds, so only list them once.
+	listedUids := sets.NewString()
+
+	err := p.walkObjectsFn(co, metav1.ListOptions{}, func(o runtime.Object) error {
+		metav1Object, err := meta.Accessor(o)
+		if err != nil {
+			return err
+		}
+
+		uid := string(metav1Object.GetUID())
+		annotations := metav1Object.GetAnnotations()
+		if annotations[metadata.AnnotationGcTag] != p.GcTag || seenUids.Has(uid) || listedUids.Has(uid) {
+			return nil
+		}
+		listedUids.Insert(uid)
+
+		gvk := o.GetObjectKind().GroupVersionKind()
+		desc := fmt.Sprintf("%s %s (%s)",
+			p.objectInfo.ResourceName(co.discovery, o), utils.FqName(metav1Object), gvk.GroupVersion())
+
+		switch {
+		case annotations[metadata.AnnotationGcStrategy] == metadata.GcStrategyIgnore:
+			candidates = append(candidates, pruneCandidate{object: o, desc: desc, action: pruneActionIgnore})
+		case eligibleForGc(metav1Object, p.GcTag):
+			candidates = append(candidates, pruneCandidate{object: o, desc: desc, action: pruneActionPrune})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "finding objects to prune")
+	}
+
+	return candidates, nil
+}
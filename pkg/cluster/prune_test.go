@@ -0,0 +1,199 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster/mocks"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/ksonnet/ksonnet/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+)
+
+func genPruneObject(name, uid, gcTag, strategy string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetUID(types.UID(uid))
+
+	annotations := map[string]string{
+		metadata.AnnotationGcTag: gcTag,
+	}
+	if strategy != "" {
+		annotations[metadata.AnnotationGcStrategy] = strategy
+	}
+	obj.SetAnnotations(annotations)
+
+	return obj
+}
+
+func TestPrune(t *testing.T) {
+	cases := []struct {
+		name        string
+		dryRun      bool
+		skipConfirm bool
+		notTerminal bool
+		answer      string
+		deleted     []string
+		expected    []string
+		isErr       bool
+	}{
+		{
+			name:   "dry run",
+			dryRun: true,
+			expected: []string{
+				"prune  configmap default.old (v1)",
+				"ignore configmap default.kept (v1)",
+			},
+		},
+		{
+			name:    "confirmed",
+			answer:  "y\n",
+			deleted: []string{"old"},
+			expected: []string{
+				"prune  configmap default.old (v1)",
+				`Prune 1 object(s) from environment "default"? [y/N]: `,
+			},
+		},
+		{
+			name:   "not confirmed",
+			answer: "n\n",
+			isErr:  true,
+		},
+		{
+			name:        "input is not a terminal",
+			notTerminal: true,
+			answer:      "y\n",
+			isErr:       true,
+		},
+		{
+			name:        "skip confirmation",
+			skipConfirm: true,
+			deleted:     []string{"old"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
+				var out bytes.Buffer
+
+				config := PruneConfig{
+					App:          a,
+					ClientConfig: &client.Config{},
+					DryRun:       tc.dryRun,
+					EnvName:      "default",
+					GcTag:        "gc-tag",
+					SkipConfirm:  tc.skipConfirm,
+					In:           strings.NewReader(tc.answer),
+					Out:          &out,
+				}
+
+				rendered := genPruneObject("current", "1", "gc-tag", "")
+				live := []*unstructured.Unstructured{
+					rendered,
+					genPruneObject("old", "2", "gc-tag", ""),
+					genPruneObject("kept", "3", "gc-tag", metadata.GcStrategyIgnore),
+					genPruneObject("other", "4", "other-tag", ""),
+				}
+
+				var deleted []string
+
+				setup := func(p *Prune) {
+					p.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+						return []*unstructured.Unstructured{rendered}, nil
+					}
+
+					p.genClientOptsFn = func(a app.App, c *client.Config, envName string) (Clients, error) {
+						d := &mocks.DiscoveryInterface{}
+						d.On("ServerResourcesForGroupVersion", mock.Anything).Return(nil, errors.New("not found"))
+						return Clients{discovery: d}, nil
+					}
+
+					p.walkObjectsFn = func(co Clients, listopts metav1.ListOptions, callback func(runtime.Object) error) error {
+						for _, obj := range live {
+							if err := callback(obj); err != nil {
+								return err
+							}
+						}
+						return nil
+					}
+
+					p.serverVersionFn = func(discovery.ServerVersionInterface) (utils.ServerVersion, error) {
+						return utils.ServerVersion{Major: 1, Minor: 10}, nil
+					}
+
+					p.isTerminalFn = func(io.Reader) bool {
+						return !tc.notTerminal
+					}
+
+					oi := &mocks.ObjectInfo{}
+					oi.On("ResourceName", mock.Anything, mock.Anything).Return("configmap")
+					p.objectInfo = oi
+
+					p.resourceClientFactory = func(co Clients, object runtime.Object) (ResourceClient, error) {
+						obj := object.(*unstructured.Unstructured)
+
+						rc := &mocks.ResourceClient{}
+						rc.On("Get", mock.Anything).Return(obj, nil)
+						rc.On("Delete", mock.Anything).Run(func(args mock.Arguments) {
+							deleted = append(deleted, obj.GetName())
+						}).Return(nil)
+						return rc, nil
+					}
+				}
+
+				err := RunPrune(config, setup)
+				if tc.isErr {
+					require.Error(t, err)
+					require.Empty(t, deleted)
+					return
+				}
+				require.NoError(t, err)
+
+				require.Equal(t, tc.deleted, deleted)
+				for _, s := range tc.expected {
+					require.Contains(t, out.String(), s)
+				}
+				require.NotContains(t, out.String(), "default.current")
+				require.NotContains(t, out.String(), "default.other")
+			})
+		})
+	}
+}
+
+func TestPrune_requires_gc_tag(t *testing.T) {
+	err := RunPrune(PruneConfig{})
+	require.Error(t, err)
+}
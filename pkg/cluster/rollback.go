@@ -0,0 +1,168 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RollbackConfig is configuration for Rollback.
+type RollbackConfig struct {
+	App          app.App
+	ClientConfig *client.Config
+	EnvName      string
+	Revision     int
+}
+
+// RollbackOpts is an option for configuring Rollback.
+type RollbackOpts func(*Rollback)
+
+// Rollback applies a previous revision of an environment.
+type Rollback struct {
+	RollbackConfig
+
+	// these make it easier to test Rollback.
+	genClientOptsFn       genClientOptsFn
+	resourceClientFactory resourceClientFactoryFn
+	historyFactory        historyFactoryFn
+	runApplyFn            func(ApplyConfig, ...ApplyOpts) (*ApplyResult, error)
+}
+
+// RunRollback runs rollback against a cluster for a given configuration. It returns
+// the result of applying each object.
+func RunRollback(config RollbackConfig, opts ...RollbackOpts) (*ApplyResult, error) {
+	r := &Rollback{
+		RollbackConfig:        config,
+		genClientOptsFn:       GenClients,
+		resourceClientFactory: resourceClientFactory,
+		historyFactory:        defaultHistoryFactory,
+		runApplyFn:            RunApply,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r.Rollback()
+}
+
+// Rollback applies the objects of a previous revision. Objects in the latest
+// revision which are not in the previous revision are removed. The rollback is
+// recorded as a new revision.
+func (r *Rollback) Rollback() (*ApplyResult, error) {
+	co, err := r.genClientOptsFn(r.App, r.ClientConfig, r.EnvName)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := r.historyFactory(co, r.EnvName).Revisions()
+	if err != nil {
+		return nil, err
+	}
+
+	var target *Revision
+	for i := range revisions {
+		if revisions[i].Number == r.Revision {
+			target = &revisions[i]
+		}
+	}
+
+	if target == nil {
+		return nil, errors.Errorf("revision %d of environment %q does not exist", r.Revision, r.EnvName)
+	}
+
+	latest := revisions[len(revisions)-1]
+
+	log.Infof("Rolling back environment %q from revision %d to %d", r.EnvName, latest.Number, target.Number)
+
+	config := ApplyConfig{
+		App:          r.App,
+		ClientConfig: r.ClientConfig,
+		Create:       true,
+		EnvName:      r.EnvName,
+	}
+
+	result, err := r.runApplyFn(config, func(a *Apply) {
+		a.clientOpts = &co
+		a.findObjectsFn = func(app.App, string, []string) ([]*unstructured.Unstructured, error) {
+			return target.Objects, nil
+		}
+		a.historyFactory = r.historyFactory
+		a.description = fmt.Sprintf("rollback to %d", target.Number)
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "applying revision %d", target.Number)
+	}
+
+	for _, obj := range removedObjects(latest.Objects, target.Objects) {
+		started := time.Now()
+		if err = r.remove(co, obj); err != nil {
+			return nil, errors.Wrapf(err, "removing %s", describeObject(obj))
+		}
+
+		result.Objects = append(result.Objects, newObjectResult(obj, ApplyActionPruned, "", started))
+	}
+
+	return result, nil
+}
+
+func (r *Rollback) remove(co Clients, obj *unstructured.Unstructured) error {
+	log.Info("Removing ", describeObject(obj))
+
+	rc, err := r.resourceClientFactory(co, obj)
+	if err != nil {
+		return err
+	}
+
+	propagation := metav1.DeletePropagationForeground
+	err = rc.Delete(&metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !kerrors.IsNotFound(errors.Cause(err)) {
+		return err
+	}
+
+	return nil
+}
+
+// removedObjects returns the objects in from which are not in to.
+func removedObjects(from, to []*unstructured.Unstructured) []*unstructured.Unstructured {
+	key := func(obj *unstructured.Unstructured) string {
+		gk := obj.GroupVersionKind().GroupKind()
+		return fmt.Sprintf("%s/%s/%s", gk, obj.GetNamespace(), obj.GetName())
+	}
+
+	keep := make(map[string]bool)
+	for _, obj := range to {
+		keep[key(obj)] = true
+	}
+
+	var removed []*unstructured.Unstructured
+	for _, obj := range from {
+		if !keep[key(obj)] {
+			removed = append(removed, obj)
+		}
+	}
+
+	return removed
+}
@@ -0,0 +1,132 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestRollback(t *testing.T) {
+	cases := []struct {
+		name     string
+		revision int
+		applied  []string
+		removed  []string
+		isErr    bool
+	}{
+		{
+			name:     "to previous revision",
+			revision: 1,
+			applied:  []string{"a", "b"},
+			removed:  []string{"c"},
+		},
+		{
+			name:     "to latest revision",
+			revision: 2,
+			applied:  []string{"a", "c"},
+		},
+		{
+			name:     "unknown revision",
+			revision: 3,
+			isErr:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
+				history := &fakeHistory{
+					revisions: []Revision{
+						{Number: 1, Objects: []*unstructured.Unstructured{genHook("a", nil), genHook("b", nil)}},
+						{Number: 2, Objects: []*unstructured.Unstructured{genHook("a", nil), genHook("c", nil)}},
+					},
+				}
+
+				config := RollbackConfig{
+					App:          a,
+					ClientConfig: &client.Config{},
+					EnvName:      "default",
+					Revision:     tc.revision,
+				}
+
+				var applied, removed []string
+
+				setup := func(r *Rollback) {
+					r.genClientOptsFn = func(a app.App, c *client.Config, envName string) (Clients, error) {
+						return Clients{}, nil
+					}
+
+					r.historyFactory = history.factory
+
+					r.runApplyFn = func(config ApplyConfig, opts ...ApplyOpts) (*ApplyResult, error) {
+						require.True(t, config.Create)
+						require.Empty(t, config.ComponentNames)
+
+						apply := &Apply{ApplyConfig: config}
+						for _, opt := range opts {
+							opt(apply)
+						}
+
+						require.Equal(t, fmt.Sprintf("rollback to %d", tc.revision), apply.description)
+
+						objects, err := apply.findObjectsFn(a, config.EnvName, nil)
+						require.NoError(t, err)
+						applied = objectNames(objects)
+
+						return &ApplyResult{}, nil
+					}
+
+					r.resourceClientFactory = func(co Clients, object runtime.Object) (ResourceClient, error) {
+						obj := object.(*unstructured.Unstructured)
+
+						rc := &mocks.ResourceClient{}
+						rc.On("Delete", mock.Anything).Run(func(mock.Arguments) {
+							removed = append(removed, obj.GetName())
+						}).Return(nil)
+						return rc, nil
+					}
+				}
+
+				result, err := RunRollback(config, setup)
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				require.Equal(t, tc.applied, applied)
+				require.Equal(t, tc.removed, removed)
+
+				require.Len(t, result.Objects, len(tc.removed))
+				for _, r := range result.Objects {
+					require.Equal(t, ApplyActionPruned, r.Action)
+				}
+			})
+		})
+	}
+}
@@ -16,7 +16,6 @@
 package cluster
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 
@@ -32,7 +31,10 @@ type ShowConfig struct {
 	ComponentNames []string
 	EnvName        string
 	Format         string
-	Out            io.Writer
+	// OutputDir is the directory objects are written to by formats which write
+	// files.
+	OutputDir string
+	Out       io.Writer
 }
 
 // ShowOpts is an option for configuring Show.
@@ -71,38 +73,17 @@ func (s *Show) Show() error {
 	copy(sorted, apiObjects)
 	UnstructuredSlice(sorted).Sort()
 
-	switch s.Format {
-	case "yaml":
-		return s.showYAML(sorted)
-	case "json":
-		return s.showJSON(sorted)
-	default:
+	newWriter, ok := objectWriters[s.Format]
+	if !ok {
 		return fmt.Errorf("Unknown --format: %s", s.Format)
 	}
-}
-
-func (s *Show) showYAML(apiObjects []*unstructured.Unstructured) error {
-	return ShowYAML(s.Out, apiObjects)
-}
-
-func (s *Show) showJSON(apiObjects []*unstructured.Unstructured) error {
-	enc := json.NewEncoder(s.Out)
-	enc.SetIndent("", "  ")
-
-	m := map[string]interface{}{
-		"apiVersion": "v1",
-		"kind":       "List",
-	}
-
-	items := make([]interface{}, 0)
 
-	for _, obj := range apiObjects {
-		items = append(items, obj.Object)
+	w, err := newWriter(s.ShowConfig)
+	if err != nil {
+		return err
 	}
 
-	m["items"] = items
-
-	return enc.Encode(m)
+	return w.Write(sorted)
 }
 
 // ShowYAML shows YAML objects.
@@ -0,0 +1,182 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// StatusConfig is configuration for Status.
+type StatusConfig struct {
+	App            app.App
+	ClientConfig   *client.Config
+	ComponentNames []string
+	EnvName        string
+}
+
+// StatusOpts is an option for configuring Status.
+type StatusOpts func(*Status)
+
+// ObjectStatus is the status of a single object on the cluster.
+type ObjectStatus struct {
+	Component        string
+	GroupVersionKind schema.GroupVersionKind
+	Namespace        string
+	Name             string
+	// Exists is true if the object exists on the cluster.
+	Exists bool
+	// Ready is true if the object passed its readiness check. Objects without
+	// a readiness check are ready as soon as they exist.
+	Ready bool
+	// Message is a human readable description of the object's readiness.
+	Message string
+	// Created is when the object was created. It is zero if the object does
+	// not exist.
+	Created time.Time
+}
+
+// StatusResult is the status of the objects in an environment.
+type StatusResult struct {
+	// Objects are the statuses of each object in the order they were rendered.
+	Objects []ObjectStatus
+}
+
+// Status fetches the status of an environment's objects from the cluster.
+// The environment is rendered once, so its status can be fetched repeatedly
+// without rendering it again.
+type Status struct {
+	StatusConfig
+
+	// these make it easier to test Status.
+	findObjectsFn         findObjectsFn
+	genClientOptsFn       genClientOptsFn
+	resourceClientFactory resourceClientFactoryFn
+
+	objects []*unstructured.Unstructured
+	waiter  *defaultObjectWaiter
+}
+
+// NewStatus renders an environment and creates the clients for fetching the
+// status of its objects.
+func NewStatus(config StatusConfig, opts ...StatusOpts) (*Status, error) {
+	s := &Status{
+		StatusConfig:          config,
+		findObjectsFn:         findObjects,
+		genClientOptsFn:       GenClients,
+		resourceClientFactory: resourceClientFactory,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	objects, err := s.findObjectsFn(s.App, s.EnvName, s.ComponentNames)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding objects")
+	}
+
+	co, err := s.genClientOptsFn(s.App, s.ClientConfig, s.EnvName)
+	if err != nil {
+		return nil, err
+	}
+
+	s.objects = objects
+	s.waiter = newDefaultObjectWaiter(co, s.resourceClientFactory, 0)
+
+	return s, nil
+}
+
+// RunStatus runs status against a cluster for a given configuration.
+func RunStatus(config StatusConfig, opts ...StatusOpts) (*StatusResult, error) {
+	s, err := NewStatus(config, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Status()
+}
+
+// Status fetches each object from the cluster. Readiness is determined with
+// the same checks `ks apply --wait` uses.
+func (s *Status) Status() (*StatusResult, error) {
+	result := &StatusResult{}
+
+	for _, obj := range s.objects {
+		status, err := s.objectStatus(s.waiter, obj)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching %s", describeObject(obj))
+		}
+
+		result.Objects = append(result.Objects, status)
+	}
+
+	return result, nil
+}
+
+func (s *Status) objectStatus(w *defaultObjectWaiter, obj *unstructured.Unstructured) (ObjectStatus, error) {
+	status := ObjectStatus{
+		Component:        obj.GetLabels()[metadata.LabelComponent],
+		GroupVersionKind: obj.GroupVersionKind(),
+		Namespace:        obj.GetNamespace(),
+		Name:             obj.GetName(),
+	}
+
+	rc, err := s.resourceClientFactory(w.clientOpts, obj)
+	if err != nil {
+		return status, err
+	}
+
+	live, err := rc.Get(metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(errors.Cause(err)) {
+			status.Message = "object does not exist"
+			return status, nil
+		}
+
+		return status, err
+	}
+
+	status.Exists = true
+	status.Namespace = live.GetNamespace()
+	status.Created = live.GetCreationTimestamp().Time
+
+	fn, ok := readinessChecks[obj.GetKind()]
+	if !ok {
+		status.Ready = true
+		status.Message = "exists"
+		return status, nil
+	}
+
+	ready, message, err := fn(w, live)
+	if err != nil {
+		// Failures, e.g. a failed job, are reported as the object's status.
+		message = err.Error()
+	}
+
+	status.Ready = ready
+	status.Message = message
+
+	return status, nil
+}
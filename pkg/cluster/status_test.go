@@ -0,0 +1,206 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster/mocks"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func genStatusObject(kind, name string) *unstructured.Unstructured {
+	obj := genWaitObject(kind, nil)
+	obj.SetName(name)
+	obj.SetLabels(map[string]string{metadata.LabelComponent: "guestbook"})
+	return obj
+}
+
+func TestStatus(t *testing.T) {
+	created := time.Date(2018, 7, 1, 12, 0, 0, 0, time.Local)
+
+	live := func(obj *unstructured.Unstructured, status map[string]interface{}) *unstructured.Unstructured {
+		obj = obj.DeepCopy()
+		obj.SetCreationTimestamp(metav1.NewTime(created))
+		if status != nil {
+			obj.Object["status"] = status
+		}
+		return obj
+	}
+
+	ready := genStatusObject("Deployment", "ready")
+	rollingOut := genStatusObject("Deployment", "rolling-out")
+	configMap := genStatusObject("ConfigMap", "config")
+	failed := genStatusObject("Job", "failed")
+	missing := genStatusObject("Service", "missing")
+
+	liveObjects := map[string]*unstructured.Unstructured{
+		"ready": live(ready, map[string]interface{}{
+			"observedGeneration": int64(2),
+			"replicas":           int64(2),
+			"updatedReplicas":    int64(2),
+			"availableReplicas":  int64(2),
+		}),
+		"rolling-out": live(rollingOut, map[string]interface{}{
+			"observedGeneration": int64(2),
+			"replicas":           int64(2),
+			"updatedReplicas":    int64(1),
+		}),
+		"config": live(configMap, nil),
+		"failed": live(failed, map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Failed", "status": "True", "message": "BackoffLimitExceeded"},
+			},
+		}),
+	}
+
+	gvk := func(kind string) schema.GroupVersionKind {
+		return schema.GroupVersionKind{Version: "v1", Kind: kind}
+	}
+
+	expected := &StatusResult{
+		Objects: []ObjectStatus{
+			{Component: "guestbook", GroupVersionKind: gvk("Deployment"), Namespace: "default", Name: "ready", Exists: true, Ready: true, Message: "rolled out", Created: created},
+			{Component: "guestbook", GroupVersionKind: gvk("Deployment"), Namespace: "default", Name: "rolling-out", Exists: true, Message: "1 of 2 replicas updated", Created: created},
+			{Component: "guestbook", GroupVersionKind: gvk("ConfigMap"), Namespace: "default", Name: "config", Exists: true, Ready: true, Message: "exists", Created: created},
+			{Component: "guestbook", GroupVersionKind: gvk("Job"), Namespace: "default", Name: "failed", Exists: true, Message: "job failed: BackoffLimitExceeded", Created: created},
+			{Component: "guestbook", GroupVersionKind: gvk("Service"), Namespace: "default", Name: "missing", Message: "object does not exist"},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		getErr   error
+		expected *StatusResult
+		isErr    bool
+	}{
+		{
+			name:     "object statuses",
+			expected: expected,
+		},
+		{
+			name:   "fetch failure",
+			getErr: errors.New("failed"),
+			isErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
+				config := StatusConfig{
+					App:          a,
+					ClientConfig: &client.Config{},
+					EnvName:      "default",
+				}
+
+				setup := func(s *Status) {
+					s.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+						return []*unstructured.Unstructured{ready, rollingOut, configMap, failed, missing}, nil
+					}
+
+					s.genClientOptsFn = func(a app.App, c *client.Config, envName string) (Clients, error) {
+						return Clients{}, nil
+					}
+
+					s.resourceClientFactory = func(co Clients, object runtime.Object) (ResourceClient, error) {
+						obj := object.(*unstructured.Unstructured)
+
+						rc := &mocks.ResourceClient{}
+						switch liveObj, ok := liveObjects[obj.GetName()]; {
+						case tc.getErr != nil:
+							rc.On("Get", mock.Anything).Return(nil, tc.getErr)
+						case ok:
+							rc.On("Get", mock.Anything).Return(liveObj, nil)
+						default:
+							rc.On("Get", mock.Anything).Return(nil, kerrors.NewNotFound(schema.GroupResource{}, obj.GetName()))
+						}
+						return rc, nil
+					}
+				}
+
+				result, err := RunStatus(config, setup)
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected, result)
+			})
+		})
+	}
+}
+
+func TestStatus_renders_once(t *testing.T) {
+	test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
+		config := StatusConfig{
+			App:          a,
+			ClientConfig: &client.Config{},
+			EnvName:      "default",
+		}
+
+		rendered, gets := 0, 0
+
+		setup := func(s *Status) {
+			s.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+				rendered++
+				obj := &unstructured.Unstructured{}
+				obj.SetAPIVersion("v1")
+				obj.SetKind("ConfigMap")
+				obj.SetName("settings")
+				return []*unstructured.Unstructured{obj}, nil
+			}
+
+			s.genClientOptsFn = func(a app.App, c *client.Config, envName string) (Clients, error) {
+				return Clients{}, nil
+			}
+
+			s.resourceClientFactory = func(co Clients, object runtime.Object) (ResourceClient, error) {
+				rc := &mocks.ResourceClient{}
+				rc.On("Get", mock.Anything).Run(func(mock.Arguments) {
+					gets++
+				}).Return(object.(*unstructured.Unstructured), nil)
+				return rc, nil
+			}
+		}
+
+		s, err := NewStatus(config, setup)
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			_, err = s.Status()
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, 1, rendered)
+		require.Equal(t, 3, gets)
+	})
+}
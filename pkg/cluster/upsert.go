@@ -28,8 +28,9 @@ import (
 
 // Upserter updates or creates objects.
 type Upserter interface {
-	// Upsert updates or creates an object.
-	Upsert(*unstructured.Unstructured) (string, error)
+	// Upsert updates or creates an object. It returns the UID of the object
+	// and the action which was taken.
+	Upsert(*unstructured.Unstructured) (string, ApplyAction, error)
 }
 
 // defaultUpserter is the default implementation for updating or creating objects.
@@ -69,34 +70,46 @@ func newDefaultUpserter(ac ApplyConfig, oi ObjectInfo, co Clients, rfc resourceC
 }
 
 // Upsert updates or creates an object.
-func (u *defaultUpserter) Upsert(obj *unstructured.Unstructured) (string, error) {
+func (u *defaultUpserter) Upsert(obj *unstructured.Unstructured) (string, ApplyAction, error) {
 	log.Info("Applying ", u.objectDescriber.Describe(obj), u.dryRunText())
 
 	rc, err := u.resourceClientFactory(u.clientOpts, obj)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	patchedObject, err := u.updateObject(rc, obj)
 	if err == nil {
 		log.Debug("Updated object: ", kdiff.ObjectDiff(obj, patchedObject))
-		return string(patchedObject.GetUID()), nil
+		return string(patchedObject.GetUID()), patchAction(obj, patchedObject), nil
 	} else if !kerrors.IsNotFound(err) {
-		return "", errors.Wrap(err, "patching existing object")
+		return "", "", errors.Wrap(err, "patching existing object")
 	}
 
 	if !u.Create {
-		return "", errors.New("not creating non-existent object")
+		return "", "", errors.New("not creating non-existent object")
 	}
 
 	log.Info("Creating non-existent ", u.objectDescriber.Describe(obj), u.dryRunText())
 	newObj, err := u.createObject(u.clientOpts, rc, obj)
 	if err != nil {
-		return "", errors.Wrap(err, "creating object")
+		return "", "", errors.Wrap(err, "creating object")
 	}
 
 	log.Debug("Created object: ", kdiff.ObjectDiff(obj, newObj))
-	return string(newObj.GetUID()), nil
+	return string(newObj.GetUID()), ApplyActionCreated, nil
+}
+
+// patchAction determines if a patch changed an object. The object sent has been
+// merged with the live object, so it carries the live resource version. The
+// server only changes the resource version if the object changed.
+func patchAction(obj, patched *unstructured.Unstructured) ApplyAction {
+	rv := obj.GetResourceVersion()
+	if rv != "" && rv == patched.GetResourceVersion() {
+		return ApplyActionUnchanged
+	}
+
+	return ApplyActionConfigured
 }
 
 // updateObject attempts to update an object in the cluster.
@@ -37,6 +37,7 @@ func Test_defaultUpserter_Upsert(t *testing.T) {
 		initResourceClient func(*testing.T, *unstructured.Unstructured) *mocks.ResourceClient
 		isErr              bool
 		expectedID         string
+		expectedAction     ApplyAction
 	}{
 		{
 			name: "patch existing object",
@@ -53,7 +54,27 @@ func Test_defaultUpserter_Upsert(t *testing.T) {
 
 				return rc
 			},
-			expectedID: "12345",
+			expectedID:     "12345",
+			expectedAction: ApplyActionConfigured,
+		},
+		{
+			name: "patch unchanged object",
+			applyConfig: ApplyConfig{
+				Create: true,
+			},
+			initResourceClient: func(t *testing.T, obj *unstructured.Unstructured) *mocks.ResourceClient {
+				rc := &mocks.ResourceClient{}
+
+				obj.SetResourceVersion("1")
+				newObject := *obj
+				newObject.SetUID(types.UID("12345"))
+
+				rc.On("Patch", types.MergePatchType, mock.AnythingOfType("[]uint8")).Return(&newObject, nil)
+
+				return rc
+			},
+			expectedID:     "12345",
+			expectedAction: ApplyActionUnchanged,
 		},
 		{
 			name: "create new object",
@@ -73,7 +94,8 @@ func Test_defaultUpserter_Upsert(t *testing.T) {
 
 				return rc
 			},
-			expectedID: "12345",
+			expectedID:     "12345",
+			expectedAction: ApplyActionCreated,
 		},
 		{
 			name: "dry run create",
@@ -85,6 +107,7 @@ func Test_defaultUpserter_Upsert(t *testing.T) {
 				rc := &mocks.ResourceClient{}
 				return rc
 			},
+			expectedAction: ApplyActionConfigured,
 		},
 		{
 			name: "patch error other than not found",
@@ -160,7 +183,7 @@ func Test_defaultUpserter_Upsert(t *testing.T) {
 			u, err := newDefaultUpserter(tc.applyConfig, oi, co, rfc)
 			require.NoError(t, err)
 
-			id, err := u.Upsert(obj)
+			id, action, err := u.Upsert(obj)
 
 			if tc.isErr {
 				require.Error(t, err)
@@ -170,17 +193,19 @@ func Test_defaultUpserter_Upsert(t *testing.T) {
 			require.NoError(t, err)
 
 			require.Equal(t, tc.expectedID, id)
+			require.Equal(t, tc.expectedAction, action)
 		})
 	}
 }
 
 type fakeUpserter struct {
-	upsertID  string
-	upsertErr error
+	upsertID     string
+	upsertAction ApplyAction
+	upsertErr    error
 }
 
 var _ Upserter = (*fakeUpserter)(nil)
 
-func (u *fakeUpserter) Upsert(*unstructured.Unstructured) (string, error) {
-	return u.upsertID, u.upsertErr
+func (u *fakeUpserter) Upsert(*unstructured.Unstructured) (string, ApplyAction, error) {
+	return u.upsertID, u.upsertAction, u.upsertErr
 }
@@ -0,0 +1,448 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// defaultWaitTimeout is how long to wait for objects to become ready
+	// if no timeout was supplied.
+	defaultWaitTimeout = 5 * time.Minute
+
+	// defaultWaitPollInterval is how often objects are checked for readiness.
+	defaultWaitPollInterval = 2 * time.Second
+)
+
+// objectWaiter waits for objects in a cluster to become ready.
+type objectWaiter interface {
+	// Wait waits for objects to become ready.
+	Wait(objects []*unstructured.Unstructured) error
+}
+
+// deletionWaiter waits for objects to be removed from a cluster.
+type deletionWaiter interface {
+	// WaitDeleted waits for objects to no longer exist.
+	WaitDeleted(objects []*unstructured.Unstructured) error
+}
+
+// readinessCheckFn checks if a live object is ready. It returns a human readable status
+// describing the readiness of the object.
+type readinessCheckFn func(w *defaultObjectWaiter, obj *unstructured.Unstructured) (bool, string, error)
+
+// readinessChecks are readiness checks by object kind. Objects with kinds that
+// are not listed are ready as soon as they exist.
+var readinessChecks = map[string]readinessCheckFn{
+	"CustomResourceDefinition": crdReady,
+	"DaemonSet":                daemonSetReady,
+	"Deployment":               deploymentReady,
+	"Job":                      jobReady,
+	"Service":                  serviceReady,
+	"StatefulSet":              statefulSetReady,
+}
+
+// defaultObjectWaiter is the default implementation of objectWaiter. It polls the
+// cluster until all objects are ready or the timeout has elapsed.
+type defaultObjectWaiter struct {
+	// clientOpts are Kubernetes client options.
+	clientOpts Clients
+
+	// resourceClientFactory is a factory for creating clients for resources.
+	resourceClientFactory resourceClientFactoryFn
+
+	// timeout is the how long to wait for all objects to become ready.
+	timeout time.Duration
+
+	// pollInterval is how often objects are checked.
+	pollInterval time.Duration
+}
+
+var _ objectWaiter = (*defaultObjectWaiter)(nil)
+var _ deletionWaiter = (*defaultObjectWaiter)(nil)
+
+// newDefaultObjectWaiter creates an instance of defaultObjectWaiter.
+func newDefaultObjectWaiter(co Clients, rcf resourceClientFactoryFn, timeout time.Duration) *defaultObjectWaiter {
+	if timeout <= 0 {
+		timeout = defaultWaitTimeout
+	}
+
+	return &defaultObjectWaiter{
+		clientOpts:            co,
+		resourceClientFactory: rcf,
+		timeout:               timeout,
+		pollInterval:          defaultWaitPollInterval,
+	}
+}
+
+// waitStatus is the last known readiness of an object.
+type waitStatus struct {
+	desc    string
+	ready   bool
+	message string
+}
+
+// Wait waits for objects to become ready. If the timeout elapses, an error
+// summarizing the status of every object which is not ready is returned.
+func (w *defaultObjectWaiter) Wait(objects []*unstructured.Unstructured) error {
+	return w.poll(objects, "become ready", "waiting for status", "is ready", w.check)
+}
+
+// WaitDeleted waits for objects to no longer exist. If the timeout elapses, an
+// error listing every object which still exists is returned. Objects which are
+// held by finalizers are reported with the names of the finalizers.
+func (w *defaultObjectWaiter) WaitDeleted(objects []*unstructured.Unstructured) error {
+	return w.poll(objects, "be deleted", "waiting for deletion", "is deleted", w.checkDeleted)
+}
+
+// poll checks objects until every check passes or the timeout elapses. goal
+// describes what the objects are waiting for, and initial and done are the
+// messages for objects which have not been checked and which have passed.
+func (w *defaultObjectWaiter) poll(objects []*unstructured.Unstructured, goal, initial, done string,
+	checkFn func(*unstructured.Unstructured) (bool, string, error)) error {
+	statuses := make([]*waitStatus, len(objects))
+	for i, obj := range objects {
+		statuses[i] = &waitStatus{
+			desc:    describeObject(obj),
+			message: initial,
+		}
+	}
+
+	deadline := time.Now().Add(w.timeout)
+
+	for {
+		pending := 0
+
+		for i, obj := range objects {
+			status := statuses[i]
+			if status.ready {
+				continue
+			}
+
+			ready, message, err := checkFn(obj)
+			if err != nil {
+				return errors.Wrapf(err, "waiting for %s", status.desc)
+			}
+
+			if message != status.message || ready {
+				if ready {
+					log.Infof("%s %s", status.desc, done)
+				} else {
+					log.Infof("Waiting for %s: %s", status.desc, message)
+				}
+			}
+
+			status.ready = ready
+			status.message = message
+
+			if !ready {
+				pending++
+			}
+		}
+
+		if pending == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return newWaitTimeoutError(w.timeout, goal, statuses)
+		}
+
+		time.Sleep(w.pollInterval)
+	}
+}
+
+// check fetches the live version of an object and runs its readiness check.
+func (w *defaultObjectWaiter) check(obj *unstructured.Unstructured) (bool, string, error) {
+	live, err := w.get(obj)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, "object does not exist", nil
+		}
+
+		return false, "", err
+	}
+
+	fn, ok := readinessChecks[obj.GetKind()]
+	if !ok {
+		return true, "exists", nil
+	}
+
+	return fn(w, live)
+}
+
+// checkDeleted fetches the live version of an object and checks if it has been
+// deleted.
+func (w *defaultObjectWaiter) checkDeleted(obj *unstructured.Unstructured) (bool, string, error) {
+	live, err := w.get(obj)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return true, "deleted", nil
+		}
+
+		return false, "", err
+	}
+
+	if live.GetDeletionTimestamp() == nil {
+		return false, "waiting for deletion", nil
+	}
+
+	finalizers := live.GetFinalizers()
+	// Namespaces are finalized by the finalizers in their spec.
+	specFinalizers, _, _ := unstructured.NestedStringSlice(live.Object, "spec", "finalizers")
+	finalizers = append(finalizers, specFinalizers...)
+
+	if len(finalizers) > 0 {
+		return false, fmt.Sprintf("waiting for finalizers %s", strings.Join(finalizers, ", ")), nil
+	}
+
+	return false, "terminating", nil
+}
+
+func (w *defaultObjectWaiter) get(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	rc, err := w.resourceClientFactory(w.clientOpts, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return rc.Get(metav1.GetOptions{})
+}
+
+// waitTimeoutError is returned when objects did not reach a goal, e.g. becoming
+// ready, before a timeout.
+type waitTimeoutError struct {
+	timeout  time.Duration
+	goal     string
+	statuses []*waitStatus
+}
+
+func newWaitTimeoutError(timeout time.Duration, goal string, statuses []*waitStatus) *waitTimeoutError {
+	return &waitTimeoutError{
+		timeout:  timeout,
+		goal:     goal,
+		statuses: statuses,
+	}
+}
+
+func (e *waitTimeoutError) Error() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "timed out after %s waiting for objects to %s:", e.timeout, e.goal)
+	for _, status := range e.statuses {
+		if status.ready {
+			continue
+		}
+		fmt.Fprintf(&buf, "\n  %s: %s", status.desc, status.message)
+	}
+
+	return buf.String()
+}
+
+// observedGenerationCurrent returns true if the controller has observed the latest
+// generation of the object.
+func observedGenerationCurrent(obj *unstructured.Unstructured) bool {
+	observed, ok, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if !ok {
+		return false
+	}
+
+	return observed >= obj.GetGeneration()
+}
+
+func nestedInt64(obj *unstructured.Unstructured, fields ...string) int64 {
+	i, _, _ := unstructured.NestedInt64(obj.Object, fields...)
+	return i
+}
+
+// desiredReplicas returns the desired replicas for an object. If replicas is not set,
+// Kubernetes defaults it to 1.
+func desiredReplicas(obj *unstructured.Unstructured) int64 {
+	replicas, ok, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !ok {
+		return 1
+	}
+
+	return replicas
+}
+
+func deploymentReady(w *defaultObjectWaiter, obj *unstructured.Unstructured) (bool, string, error) {
+	if !observedGenerationCurrent(obj) {
+		return false, "waiting for rollout to be observed", nil
+	}
+
+	replicas := desiredReplicas(obj)
+	updated := nestedInt64(obj, "status", "updatedReplicas")
+	total := nestedInt64(obj, "status", "replicas")
+	available := nestedInt64(obj, "status", "availableReplicas")
+
+	switch {
+	case updated < replicas:
+		return false, fmt.Sprintf("%d of %d replicas updated", updated, replicas), nil
+	case total > updated:
+		return false, fmt.Sprintf("%d old replicas pending termination", total-updated), nil
+	case available < updated:
+		return false, fmt.Sprintf("%d of %d updated replicas available", available, updated), nil
+	}
+
+	return true, "rolled out", nil
+}
+
+func statefulSetReady(w *defaultObjectWaiter, obj *unstructured.Unstructured) (bool, string, error) {
+	if !observedGenerationCurrent(obj) {
+		return false, "waiting for rollout to be observed", nil
+	}
+
+	replicas := desiredReplicas(obj)
+	ready := nestedInt64(obj, "status", "readyReplicas")
+	if ready < replicas {
+		return false, fmt.Sprintf("%d of %d replicas ready", ready, replicas), nil
+	}
+
+	strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+	if strategy == "OnDelete" {
+		return true, "rolled out", nil
+	}
+
+	current, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+	update, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+	if current != update {
+		updated := nestedInt64(obj, "status", "updatedReplicas")
+		return false, fmt.Sprintf("%d of %d replicas updated", updated, replicas), nil
+	}
+
+	return true, "rolled out", nil
+}
+
+func daemonSetReady(w *defaultObjectWaiter, obj *unstructured.Unstructured) (bool, string, error) {
+	if !observedGenerationCurrent(obj) {
+		return false, "waiting for rollout to be observed", nil
+	}
+
+	desired := nestedInt64(obj, "status", "desiredNumberScheduled")
+	updated := nestedInt64(obj, "status", "updatedNumberScheduled")
+	available := nestedInt64(obj, "status", "numberAvailable")
+
+	switch {
+	case updated < desired:
+		return false, fmt.Sprintf("%d of %d pods updated", updated, desired), nil
+	case available < desired:
+		return false, fmt.Sprintf("%d of %d updated pods available", available, desired), nil
+	}
+
+	return true, "rolled out", nil
+}
+
+func jobReady(w *defaultObjectWaiter, obj *unstructured.Unstructured) (bool, string, error) {
+	if conditionStatus(obj, "Failed") == "True" {
+		return false, "", errors.Errorf("job failed: %s", conditionMessage(obj, "Failed"))
+	}
+
+	if conditionStatus(obj, "Complete") == "True" {
+		return true, "complete", nil
+	}
+
+	succeeded := nestedInt64(obj, "status", "succeeded")
+	completions, ok, _ := unstructured.NestedInt64(obj.Object, "spec", "completions")
+	if !ok {
+		completions = 1
+	}
+
+	return false, fmt.Sprintf("%d of %d completions succeeded", succeeded, completions), nil
+}
+
+func serviceReady(w *defaultObjectWaiter, obj *unstructured.Unstructured) (bool, string, error) {
+	serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type")
+	selector, _, _ := unstructured.NestedMap(obj.Object, "spec", "selector")
+	if serviceType == "ExternalName" || len(selector) == 0 {
+		// Endpoints for these services are not managed by Kubernetes.
+		return true, "exists", nil
+	}
+
+	endpoints := &unstructured.Unstructured{}
+	endpoints.SetAPIVersion("v1")
+	endpoints.SetKind("Endpoints")
+	endpoints.SetName(obj.GetName())
+	endpoints.SetNamespace(obj.GetNamespace())
+
+	live, err := w.get(endpoints)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, "waiting for endpoints", nil
+		}
+		return false, "", err
+	}
+
+	subsets, _, _ := unstructured.NestedSlice(live.Object, "subsets")
+	for _, subset := range subsets {
+		m, ok := subset.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if addresses, ok := m["addresses"].([]interface{}); ok && len(addresses) > 0 {
+			return true, "has endpoints", nil
+		}
+	}
+
+	return false, "waiting for endpoints", nil
+}
+
+func crdReady(w *defaultObjectWaiter, obj *unstructured.Unstructured) (bool, string, error) {
+	if conditionStatus(obj, "NamesAccepted") == "False" {
+		return false, "", errors.Errorf("names not accepted: %s", conditionMessage(obj, "NamesAccepted"))
+	}
+
+	if conditionStatus(obj, "Established") == "True" {
+		return true, "established", nil
+	}
+
+	return false, "waiting to be established", nil
+}
+
+// condition returns the status condition with a type.
+func condition(obj *unstructured.Unstructured, conditionType string) map[string]interface{} {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if m["type"] == conditionType {
+			return m
+		}
+	}
+
+	return nil
+}
+
+func conditionStatus(obj *unstructured.Unstructured, conditionType string) string {
+	s, _ := condition(obj, conditionType)["status"].(string)
+	return s
+}
+
+func conditionMessage(obj *unstructured.Unstructured, conditionType string) string {
+	s, _ := condition(obj, conditionType)["message"].(string)
+	return s
+}
@@ -0,0 +1,299 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/cluster/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func genWaitObject(kind string, status map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name":       "guiroot",
+				"namespace":  "default",
+				"generation": int64(2),
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(2),
+				"selector": map[string]interface{}{
+					"app": "guiroot",
+				},
+			},
+		},
+	}
+
+	if status != nil {
+		obj.Object["status"] = status
+	}
+
+	return obj
+}
+
+func Test_defaultObjectWaiter_Wait(t *testing.T) {
+	cases := []struct {
+		name      string
+		obj       *unstructured.Unstructured
+		live      []*unstructured.Unstructured
+		endpoints *unstructured.Unstructured
+		isErr     bool
+	}{
+		{
+			name: "deployment rolled out",
+			obj:  genWaitObject("Deployment", nil),
+			live: []*unstructured.Unstructured{
+				genWaitObject("Deployment", map[string]interface{}{
+					"observedGeneration": int64(1),
+				}),
+				genWaitObject("Deployment", map[string]interface{}{
+					"observedGeneration": int64(2),
+					"replicas":           int64(2),
+					"updatedReplicas":    int64(2),
+					"availableReplicas":  int64(2),
+				}),
+			},
+		},
+		{
+			name: "deployment times out",
+			obj:  genWaitObject("Deployment", nil),
+			live: []*unstructured.Unstructured{
+				genWaitObject("Deployment", map[string]interface{}{
+					"observedGeneration": int64(2),
+					"replicas":           int64(2),
+					"updatedReplicas":    int64(2),
+					"availableReplicas":  int64(1),
+				}),
+			},
+			isErr: true,
+		},
+		{
+			name: "statefulset rolled out",
+			obj:  genWaitObject("StatefulSet", nil),
+			live: []*unstructured.Unstructured{
+				genWaitObject("StatefulSet", map[string]interface{}{
+					"observedGeneration": int64(2),
+					"readyReplicas":      int64(2),
+					"currentRevision":    "1",
+					"updateRevision":     "1",
+				}),
+			},
+		},
+		{
+			name: "daemonset rolled out",
+			obj:  genWaitObject("DaemonSet", nil),
+			live: []*unstructured.Unstructured{
+				genWaitObject("DaemonSet", map[string]interface{}{
+					"observedGeneration":     int64(2),
+					"desiredNumberScheduled": int64(3),
+					"updatedNumberScheduled": int64(3),
+					"numberAvailable":        int64(3),
+				}),
+			},
+		},
+		{
+			name: "job complete",
+			obj:  genWaitObject("Job", nil),
+			live: []*unstructured.Unstructured{
+				genWaitObject("Job", map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Complete", "status": "True"},
+					},
+				}),
+			},
+		},
+		{
+			name: "job failed",
+			obj:  genWaitObject("Job", nil),
+			live: []*unstructured.Unstructured{
+				genWaitObject("Job", map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Failed", "status": "True", "message": "backoff limit exceeded"},
+					},
+				}),
+			},
+			isErr: true,
+		},
+		{
+			name: "crd established",
+			obj:  genWaitObject("CustomResourceDefinition", nil),
+			live: []*unstructured.Unstructured{
+				genWaitObject("CustomResourceDefinition", map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Established", "status": "True"},
+					},
+				}),
+			},
+		},
+		{
+			name: "service with endpoints",
+			obj:  genWaitObject("Service", nil),
+			live: []*unstructured.Unstructured{genWaitObject("Service", nil)},
+			endpoints: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"subsets": []interface{}{
+						map[string]interface{}{
+							"addresses": []interface{}{
+								map[string]interface{}{"ip": "10.0.0.1"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "service without endpoints",
+			obj:       genWaitObject("Service", nil),
+			live:      []*unstructured.Unstructured{genWaitObject("Service", nil)},
+			endpoints: &unstructured.Unstructured{Object: map[string]interface{}{}},
+			isErr:     true,
+		},
+		{
+			name: "kind without readiness check",
+			obj:  genWaitObject("ConfigMap", nil),
+			live: []*unstructured.Unstructured{genWaitObject("ConfigMap", nil)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := &mocks.ResourceClient{}
+			for i, live := range tc.live {
+				call := rc.On("Get", mock.Anything).Return(live, nil)
+				if i < len(tc.live)-1 {
+					call.Once()
+				}
+			}
+
+			endpointsClient := &mocks.ResourceClient{}
+			endpointsClient.On("Get", mock.Anything).Return(tc.endpoints, nil)
+
+			rcf := func(opts Clients, object runtime.Object) (ResourceClient, error) {
+				if object.GetObjectKind().GroupVersionKind().Kind == "Endpoints" {
+					return endpointsClient, nil
+				}
+				return rc, nil
+			}
+
+			w := newDefaultObjectWaiter(Clients{}, rcf, 10*time.Millisecond)
+			w.pollInterval = time.Millisecond
+
+			err := w.Wait([]*unstructured.Unstructured{tc.obj})
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_defaultObjectWaiter_Wait_timeout_summary(t *testing.T) {
+	rc := &mocks.ResourceClient{}
+	rc.On("Get", mock.Anything).Return(nil, &notFoundError{})
+
+	rcf := func(opts Clients, object runtime.Object) (ResourceClient, error) {
+		return rc, nil
+	}
+
+	w := newDefaultObjectWaiter(Clients{}, rcf, time.Millisecond)
+	w.pollInterval = time.Millisecond
+
+	err := w.Wait([]*unstructured.Unstructured{genWaitObject("Deployment", nil)})
+	require.Error(t, err)
+
+	expected := "timed out after 1ms waiting for objects to become ready:\n  deployment default.guiroot: object does not exist"
+	require.Equal(t, expected, err.Error())
+}
+
+func Test_defaultObjectWaiter_WaitDeleted(t *testing.T) {
+	terminating := func(finalizers ...string) *unstructured.Unstructured {
+		obj := genWaitObject("Namespace", nil)
+		now := metav1.Now()
+		obj.SetDeletionTimestamp(&now)
+		obj.SetFinalizers(finalizers)
+		return obj
+	}
+
+	cases := []struct {
+		name     string
+		live     []*unstructured.Unstructured
+		expected string
+	}{
+		{
+			name: "deleted",
+			live: []*unstructured.Unstructured{terminating(), nil},
+		},
+		{
+			name:     "not deleted",
+			live:     []*unstructured.Unstructured{genWaitObject("Namespace", nil)},
+			expected: "waiting for deletion",
+		},
+		{
+			name:     "terminating",
+			live:     []*unstructured.Unstructured{terminating()},
+			expected: "terminating",
+		},
+		{
+			name:     "stuck on finalizers",
+			live:     []*unstructured.Unstructured{terminating("example.com/cleanup", "foregroundDeletion")},
+			expected: "waiting for finalizers example.com/cleanup, foregroundDeletion",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := &mocks.ResourceClient{}
+			for i, live := range tc.live {
+				var call *mock.Call
+				if live == nil {
+					call = rc.On("Get", mock.Anything).Return(nil, &notFoundError{})
+				} else {
+					call = rc.On("Get", mock.Anything).Return(live, nil)
+				}
+				if i < len(tc.live)-1 {
+					call.Once()
+				}
+			}
+
+			rcf := func(opts Clients, object runtime.Object) (ResourceClient, error) {
+				return rc, nil
+			}
+
+			w := newDefaultObjectWaiter(Clients{}, rcf, 10*time.Millisecond)
+			w.pollInterval = time.Millisecond
+
+			err := w.WaitDeleted([]*unstructured.Unstructured{genWaitObject("Namespace", nil)})
+			if tc.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			expected := "timed out after 10ms waiting for objects to be deleted:\n  namespace default.guiroot: " + tc.expected
+			require.EqualError(t, err, expected)
+		})
+	}
+}
@@ -185,7 +185,7 @@ func envParams(a app.App, moduleName, envName string) (string, error) {
 		return "", err
 	}
 
-	env, err := a.Environment(envName)
+	env, err := app.ResolveEnvironment(a, envName)
 	if err != nil {
 		return "", err
 	}
@@ -97,6 +97,10 @@ func Delete(a app.App, name string) error {
 		return errors.Wrap(err, "writing environment params")
 	}
 
+	if err = removeParamSchema(a, m.Dir(), componentName); err != nil {
+		return errors.Wrap(err, "removing param schema")
+	}
+
 	//
 	// Delete the component file in components/.
 	//
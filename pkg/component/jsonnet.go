@@ -92,6 +92,10 @@ func (j *Jsonnet) Remove() error {
 
 // SetParam set parameter for a component.
 func (j *Jsonnet) SetParam(path []string, value interface{}) error {
+	if err := validateParam(j.app, filepath.Dir(j.paramsPath), j.Name(false), path, value); err != nil {
+		return err
+	}
+
 	paramsData, err := j.readModuleParams()
 	if err != nil {
 		return err
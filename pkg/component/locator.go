@@ -49,7 +49,7 @@ func (cpl *componentPathLocator) Locate() ([]string, error) {
 		return cpl.allNamespaces()
 	}
 
-	env, err := cpl.app.Environment(cpl.envName)
+	env, err := app.ResolveEnvironment(cpl.app, cpl.envName)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,78 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package component
+
+import (
+	"path/filepath"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/params"
+	"github.com/pkg/errors"
+)
+
+// WriteParamSchema saves the param schema of a component in its module.
+func WriteParamSchema(a app.App, moduleName, name string, cs params.ComponentSchema) error {
+	moduleDir := filepath.Join(a.Root(), componentsRoot, moduleToDir(moduleName))
+
+	s, err := params.LoadSchemas(a.Fs(), moduleDir)
+	if err != nil {
+		return err
+	}
+
+	s[name] = cs
+
+	return params.SaveSchemas(a.Fs(), moduleDir, s)
+}
+
+// ValidateParam validates a value for a component param against the param
+// schema of the component. Params without a schema accept any value.
+func ValidateParam(a app.App, name string, path []string, value interface{}) error {
+	m, c, err := ResolvePath(a, name)
+	if err != nil {
+		return errors.Wrap(err, "could not find component")
+	}
+
+	if c == nil {
+		return errors.Errorf("%q is a module, not a component", name)
+	}
+
+	return validateParam(a, m.Dir(), c.Name(false), path, value)
+}
+
+func validateParam(a app.App, moduleDir, componentName string, path []string, value interface{}) error {
+	s, err := params.LoadSchemas(a.Fs(), moduleDir)
+	if err != nil {
+		return err
+	}
+
+	return s.ValidateParam(componentName, path, value)
+}
+
+// removeParamSchema removes the param schema of a component from its module.
+func removeParamSchema(a app.App, moduleDir, componentName string) error {
+	s, err := params.LoadSchemas(a.Fs(), moduleDir)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := s[componentName]; !ok {
+		return nil
+	}
+
+	delete(s, componentName)
+
+	return params.SaveSchemas(a.Fs(), moduleDir, s)
+}
@@ -0,0 +1,109 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package component
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/params"
+	"github.com/ksonnet/ksonnet/pkg/prototype"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var guestbookSchema = params.ComponentSchema{
+	"replicas": {Type: prototype.Number, Required: true, Description: "Number of replicas"},
+}
+
+func TestWriteParamSchema(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		test.StageDir(t, fs, "delete", "/app")
+
+		err := WriteParamSchema(a, "", "guestbook-ui", guestbookSchema)
+		require.NoError(t, err)
+
+		err = WriteParamSchema(a, "nested", "guestbook-ui", guestbookSchema)
+		require.NoError(t, err)
+
+		for _, dir := range []string{"/app/components", "/app/components/nested"} {
+			s, err := params.LoadSchemas(fs, dir)
+			require.NoError(t, err)
+
+			expected := params.Schemas{"guestbook-ui": guestbookSchema}
+			assert.Equal(t, expected, s, dir)
+		}
+	})
+}
+
+func TestValidateParam(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		test.StageDir(t, fs, "delete", "/app")
+
+		err := WriteParamSchema(a, "", "guestbook-ui", guestbookSchema)
+		require.NoError(t, err)
+
+		err = ValidateParam(a, "guestbook-ui", []string{"replicas"}, 3)
+		require.NoError(t, err)
+
+		err = ValidateParam(a, "guestbook-ui", []string{"replicas"}, "three")
+		require.EqualError(t, err, `param "replicas" of component "guestbook-ui" must be a number, got string "three"`)
+
+		err = ValidateParam(a, "nested", []string{"replicas"}, 3)
+		require.Error(t, err)
+	})
+}
+
+func TestJsonnet_SetParam_invalid(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		files := []string{"guestbook-ui.jsonnet", "k.libsonnet", "k8s.libsonnet", "params.libsonnet"}
+		for _, file := range files {
+			test.StageFile(t, fs, "guestbook/"+file, "/app/components/"+file)
+		}
+
+		err := WriteParamSchema(a, "", "guestbook-ui", guestbookSchema)
+		require.NoError(t, err)
+
+		c := NewJsonnet(a, "", "/app/components/guestbook-ui.jsonnet", "/app/components/params.libsonnet")
+
+		err = c.SetParam([]string{"replicas"}, "four")
+		require.EqualError(t, err, `param "replicas" of component "guestbook-ui" must be a number, got string "four"`)
+
+		test.AssertContents(t, fs, "guestbook/params.libsonnet", "/app/components/params.libsonnet")
+	})
+}
+
+func TestDelete_removes_param_schema(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		test.StageDir(t, fs, "delete", "/app")
+
+		envs := app.EnvironmentConfigs{
+			"default": &app.EnvironmentConfig{},
+		}
+		a.On("Environments").Return(envs, nil)
+
+		err := WriteParamSchema(a, "", "guestbook-ui", guestbookSchema)
+		require.NoError(t, err)
+
+		err = Delete(a, "guestbook-ui")
+		require.NoError(t, err)
+
+		test.AssertNotExists(t, fs, params.SchemaPath("/app/components"))
+	})
+}
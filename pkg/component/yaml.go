@@ -223,6 +223,10 @@ func (y *YAML) paramValues(componentName string, valueMap map[string]schema.Valu
 
 // SetParam set parameter for a component.
 func (y *YAML) SetParam(path []string, value interface{}) error {
+	if err := validateParam(y.app, filepath.Dir(y.paramsPath), y.Name(false), path, value); err != nil {
+		return err
+	}
+
 	paramsData, err := y.readModuleParams()
 	if err != nil {
 		return err
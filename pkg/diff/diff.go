@@ -37,6 +37,7 @@ type Differ struct {
 
 	localGen  yamlGenerator
 	remoteGen yamlGenerator
+	gitGen    yamlGenerator
 }
 
 // DefaultDiff runs diff with default options.
@@ -45,10 +46,17 @@ func DefaultDiff(a app.App, config *client.Config, components []string, l1 *Loca
 	return differ.Diff(l2, l1)
 }
 
+// DefaultStructuralDiff runs a structural diff with default options.
+func DefaultStructuralDiff(a app.App, config *client.Config, components []string, l1 *Location, l2 *Location) (*Result, error) {
+	differ := New(a, config, components)
+	return differ.StructuralDiff(l2, l1)
+}
+
 // New creates an instance of Differ.
 func New(a app.App, config *client.Config, components []string) *Differ {
 	yl := newYamlLocal(a)
 	yr := newYamlRemote(a, config)
+	yg := newYamlGit(a)
 
 	d := &Differ{
 		App:        a,
@@ -56,6 +64,7 @@ func New(a app.App, config *client.Config, components []string) *Differ {
 		Components: components,
 		localGen:   yl,
 		remoteGen:  yr,
+		gitGen:     yg,
 	}
 
 	return d
@@ -98,11 +107,60 @@ func (d *Differ) toYAML(location *Location) (io.ReadSeeker, error) {
 		return d.localGen.Generate(location, d.Components)
 	case "remote":
 		return d.remoteGen.Generate(location, d.Components)
+	case "git":
+		return d.gitGen.Generate(location, d.Components)
+	}
+}
+
+// StructuralDiff generates the object level differences between two locations.
+// Changes are reported as the changes required to move from location1 to location2.
+func (d *Differ) StructuralDiff(location1, location2 *Location) (*Result, error) {
+	logrus.WithFields(logrus.Fields{
+		"src1": location1.String(),
+		"src2": location2.String(),
+	}).Debug("generating structural diff")
+
+	objects1, err := d.toObjects(location1)
+	if err != nil {
+		return nil, err
+	}
+
+	objects2, err := d.toObjects(location2)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := compareObjects(objects1, objects2)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Source1 = location1.String()
+	result.Source2 = location2.String()
+
+	return result, nil
+}
+
+func (d *Differ) toObjects(location *Location) ([]*unstructured.Unstructured, error) {
+	if err := location.Err(); err != nil {
+		return nil, err
+	}
+
+	switch location.Destination() {
+	default:
+		return nil, errors.Errorf("unknown destation %q", location.Destination())
+	case "local":
+		return d.localGen.Objects(location, d.Components)
+	case "remote":
+		return d.remoteGen.Objects(location, d.Components)
+	case "git":
+		return d.gitGen.Objects(location, d.Components)
 	}
 }
 
 type yamlGenerator interface {
 	Generate(*Location, []string) (io.ReadSeeker, error)
+	Objects(*Location, []string) ([]*unstructured.Unstructured, error)
 }
 
 type yamlLocal struct {
@@ -127,14 +185,11 @@ func localCollectObjects(a app.App, envName string, componentNames []string) ([]
 func (yl *yamlLocal) Generate(location *Location, components []string) (io.ReadSeeker, error) {
 	var buf bytes.Buffer
 
-	objects, err := yl.collectObjectsFn(yl.app, location.EnvName(), components)
+	objects, err := yl.Objects(location, components)
 	if err != nil {
 		return nil, err
-
 	}
 
-	cluster.UnstructuredSlice(objects).Sort()
-
 	if err := yl.showFn(&buf, objects); err != nil {
 		return nil, err
 	}
@@ -160,10 +215,34 @@ func newYamlRemote(a app.App, config *client.Config) *yamlRemote {
 	}
 }
 
+func (yl *yamlLocal) Objects(location *Location, components []string) ([]*unstructured.Unstructured, error) {
+	objects, err := yl.collectObjectsFn(yl.app, location.EnvName(), components)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster.UnstructuredSlice(objects).Sort()
+
+	return objects, nil
+}
+
 func (yr *yamlRemote) Generate(location *Location, components []string) (io.ReadSeeker, error) {
 	var buf bytes.Buffer
 
-	environment, err := yr.app.Environment(location.EnvName())
+	objects, err := yr.Objects(location, components)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yr.showFn(&buf, objects); err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(buf.Bytes()), nil
+}
+
+func (yr *yamlRemote) Objects(location *Location, components []string) ([]*unstructured.Unstructured, error) {
+	environment, err := app.ResolveEnvironment(yr.app, location.EnvName())
 	if err != nil {
 		return nil, err
 	}
@@ -181,9 +260,5 @@ func (yr *yamlRemote) Generate(location *Location, components []string) (io.Read
 
 	cluster.UnstructuredSlice(objects).Sort()
 
-	if err := yr.showFn(&buf, objects); err != nil {
-		return nil, err
-	}
-
-	return bytes.NewReader(buf.Bytes()), nil
+	return objects, nil
 }
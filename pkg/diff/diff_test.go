@@ -35,8 +35,9 @@ import (
 )
 
 type fakeYamlGenerator struct {
-	b   []byte
-	err error
+	b       []byte
+	objects []*unstructured.Unstructured
+	err     error
 }
 
 func (fyg *fakeYamlGenerator) Generate(l *Location, components []string) (io.ReadSeeker, error) {
@@ -50,6 +51,10 @@ func (fyg *fakeYamlGenerator) Generate(l *Location, components []string) (io.Rea
 	return r, fyg.err
 }
 
+func (fyg *fakeYamlGenerator) Objects(l *Location, components []string) ([]*unstructured.Unstructured, error) {
+	return fyg.objects, fyg.err
+}
+
 func TestDiffer(t *testing.T) {
 	test.WithApp(t, "/", func(appMock *mocks.App, fs afero.Fs) {
 		differ := New(appMock, &client.Config{}, []string{})
@@ -75,6 +80,35 @@ func TestDiffer(t *testing.T) {
 	})
 }
 
+func TestDiffer_StructuralDiff(t *testing.T) {
+	test.WithApp(t, "/", func(appMock *mocks.App, fs afero.Fs) {
+		differ := New(appMock, &client.Config{}, []string{})
+
+		differ.localGen = &fakeYamlGenerator{
+			objects: []*unstructured.Unstructured{
+				genStructuralObject("v1", "ConfigMap", "a", map[string]interface{}{
+					"data": map[string]interface{}{"key": "new"},
+				}),
+			},
+		}
+		differ.remoteGen = &fakeYamlGenerator{
+			objects: []*unstructured.Unstructured{
+				genStructuralObject("v1", "ConfigMap", "a", map[string]interface{}{
+					"data": map[string]interface{}{"key": "old"},
+				}),
+			},
+		}
+
+		result, err := differ.StructuralDiff(NewLocation("remote:default"), NewLocation("local:default"))
+		require.NoError(t, err)
+
+		require.Equal(t, "remote:default", result.Source1)
+		require.Equal(t, "local:default", result.Source2)
+		require.Equal(t, Summary{Changed: 1}, result.Summary)
+		require.Equal(t, []FieldChange{{Path: "data.key", Old: "old", New: "new"}}, result.Objects[0].Changes)
+	})
+}
+
 func Test_yamlLocal(t *testing.T) {
 	cases := []struct {
 		name             string
@@ -0,0 +1,151 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/util/archive"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// yamlGit generates objects from the app as it exists at a git revision.
+type yamlGit struct {
+	app              app.App
+	checkoutFn       func(appRoot, revision string) (afero.Fs, error)
+	loadAppFn        func(fs afero.Fs, httpClient *http.Client, root string) (app.App, error)
+	collectObjectsFn func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error)
+	showFn           func(io.Writer, []*unstructured.Unstructured) error
+}
+
+func newYamlGit(a app.App) *yamlGit {
+	return &yamlGit{
+		app:              a,
+		checkoutFn:       gitCheckout,
+		loadAppFn:        loadGitApp,
+		collectObjectsFn: localCollectObjects,
+		showFn:           cluster.ShowYAML,
+	}
+}
+
+func loadGitApp(fs afero.Fs, httpClient *http.Client, root string) (app.App, error) {
+	return app.Load(fs, httpClient, root, true)
+}
+
+func (yg *yamlGit) Generate(location *Location, components []string) (io.ReadSeeker, error) {
+	var buf bytes.Buffer
+
+	objects, err := yg.Objects(location, components)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yg.showFn(&buf, objects); err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(buf.Bytes()), nil
+}
+
+func (yg *yamlGit) Objects(location *Location, components []string) ([]*unstructured.Unstructured, error) {
+	fs, err := yg.checkoutFn(yg.app.Root(), location.Revision())
+	if err != nil {
+		return nil, err
+	}
+
+	gitApp, err := yg.loadAppFn(fs, yg.app.HTTPClient(), yg.app.Root())
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading app at revision %q", location.Revision())
+	}
+
+	objects, err := yg.collectObjectsFn(gitApp, location.EnvName(), components)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster.UnstructuredSlice(objects).Sort()
+
+	return objects, nil
+}
+
+// gitCheckout copies the app tree at a git revision into an in-memory
+// filesystem. The app is placed at the same root as the working copy.
+func gitCheckout(appRoot, revision string) (afero.Fs, error) {
+	out, err := runGit(appRoot, "rev-parse", "--show-toplevel", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+
+	// The prefix is empty when the app is at the top of the repository.
+	lines := strings.SplitN(strings.TrimSpace(out.String()), "\n", 2)
+	topLevel, prefix := lines[0], ""
+	if len(lines) == 2 {
+		prefix = lines[1]
+	}
+
+	// Archive the tree of the app directory at the revision, so entries are
+	// relative to the app root.
+	r, err := runGit(topLevel, "archive", "--format=tar", revision+":"+prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := afero.NewMemMapFs()
+	if err := extractTar(fs, appRoot, r); err != nil {
+		return nil, errors.Wrapf(err, "extracting app at revision %q", revision)
+	}
+
+	return fs, nil
+}
+
+// extractTar writes the files in a tar archive to a filesystem under root.
+func extractTar(fs afero.Fs, root string, r io.Reader) error {
+	t := &archive.Tar{}
+	return t.Unarchive(r, func(f *archive.File) error {
+		path := filepath.Join(root, filepath.FromSlash(f.Name))
+
+		if err := fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+
+		return afero.WriteReader(fs, path, f.Reader)
+	})
+}
+
+func runGit(dir string, args ...string) (*bytes.Buffer, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "running git %s: %s",
+			strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+
+	return &stdout, nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package diff
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_yamlGit(t *testing.T) {
+	cases := []struct {
+		name        string
+		checkoutErr error
+		loadErr     error
+		expected    string
+		isErr       bool
+	}{
+		{
+			name:     "in general",
+			expected: sortedYAML,
+		},
+		{
+			name:        "checkout failed",
+			checkoutErr: errors.New("fail"),
+			isErr:       true,
+		},
+		{
+			name:    "load app failed",
+			loadErr: errors.New("fail"),
+			isErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/app", func(appMock *mocks.App, fs afero.Fs) {
+				appMock.On("HTTPClient").Return(&http.Client{})
+
+				gitFs := afero.NewMemMapFs()
+				gitApp := &mocks.App{}
+
+				yg := newYamlGit(appMock)
+				yg.checkoutFn = func(appRoot, revision string) (afero.Fs, error) {
+					require.Equal(t, "/app", appRoot)
+					require.Equal(t, "origin/master", revision)
+					return gitFs, tc.checkoutErr
+				}
+				yg.loadAppFn = func(fs afero.Fs, httpClient *http.Client, root string) (app.App, error) {
+					require.Equal(t, gitFs, fs)
+					require.Equal(t, "/app", root)
+					return gitApp, tc.loadErr
+				}
+				yg.collectObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+					require.Equal(t, gitApp, a)
+					require.Equal(t, "default", envName)
+					return genObjects(), nil
+				}
+				yg.showFn = showYAML
+
+				rs, err := yg.Generate(NewLocation("git:origin/master:default"), []string{})
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				b, err := ioutil.ReadAll(rs)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected, string(b))
+			})
+		})
+	}
+}
+
+func Test_extractTar(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	content := []byte("apiVersion: 0.2.0\n")
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "components/", Typeflag: tar.TypeDir, Mode: 0755}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "app.yaml", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}))
+	_, err := tw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	fs := afero.NewMemMapFs()
+	err = extractTar(fs, "/app", &buf)
+	require.NoError(t, err)
+
+	b, err := afero.ReadFile(fs, "/app/app.yaml")
+	require.NoError(t, err)
+	require.Equal(t, content, b)
+}
+
+func Test_gitCheckout(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "diff-git")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	appRoot := filepath.Join(dir, "app")
+	require.NoError(t, os.MkdirAll(appRoot, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(appRoot, "app.yaml"), []byte("committed"), 0644))
+
+	git := func(args ...string) {
+		_, err := runGit(dir, args...)
+		require.NoError(t, err)
+	}
+
+	git("init", "-q")
+	git("add", "-A")
+	git("-c", "user.name=ks", "-c", "user.email=ks@example.com", "commit", "-q", "-m", "initial")
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(appRoot, "app.yaml"), []byte("working copy"), 0644))
+
+	fs, err := gitCheckout(appRoot, "HEAD")
+	require.NoError(t, err)
+
+	b, err := afero.ReadFile(fs, filepath.Join(appRoot, "app.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, "committed", string(b))
+
+	_, err = gitCheckout(appRoot, "missing")
+	require.Error(t, err)
+}
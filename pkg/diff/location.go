@@ -26,15 +26,17 @@ import (
 var (
 	diffDestinationNames = []string{"local", "remote"}
 
-	errInvalidLocation = errors.New("invalid location. format is destination:environment or environment")
+	errInvalidLocation = errors.New("invalid location. format is destination:environment, git:revision:environment or environment")
 )
 
 // Location is a diff location.
 type Location struct {
-	// destination is either `local` or `remote`
+	// destination is either `local`, `remote` or `git`
 	destination string
 	// envName is the environment name.
 	envName string
+	// revision is the git revision for the `git` destination.
+	revision string
 
 	err error
 }
@@ -57,11 +59,19 @@ func NewLocation(src string) *Location {
 		l.envName = parts[0]
 	case 2:
 		if !strings.InSlice(parts[0], diffDestinationNames) {
-			l.err = errors.Errorf("%q is not a valid destination name")
+			l.err = errors.Errorf("%q is not a valid destination name", parts[0])
 			break
 		}
 		l.destination = parts[0]
 		l.envName = parts[1]
+	case 3:
+		if parts[0] != "git" || parts[1] == "" {
+			l.err = errInvalidLocation
+			break
+		}
+		l.destination = parts[0]
+		l.revision = parts[1]
+		l.envName = parts[2]
 	}
 
 	return l
@@ -82,6 +92,23 @@ func (l *Location) EnvName() string {
 	return l.envName
 }
 
+// WithEnvName returns a copy of this location for another environment.
+func (l *Location) WithEnvName(envName string) *Location {
+	c := *l
+	c.envName = envName
+	return &c
+}
+
+// Revision is the git revision for the destination. It is only set for
+// the `git` destination.
+func (l *Location) Revision() string {
+	return l.revision
+}
+
 func (l *Location) String() string {
+	if l.destination == "git" {
+		return fmt.Sprintf("%s:%s:%s", l.destination, l.revision, l.envName)
+	}
+
 	return fmt.Sprintf("%s:%s", l.destination, l.envName)
 }
@@ -30,6 +30,8 @@ func TestLocation(t *testing.T) {
 		src         string
 		destination string
 		envName     string
+		revision    string
+		str         string
 		isErr       bool
 	}{
 		{
@@ -44,6 +46,19 @@ func TestLocation(t *testing.T) {
 			destination: "local",
 			envName:     "default",
 		},
+		{
+			name:        "git:origin/master:default",
+			src:         "git:origin/master:default",
+			destination: "git",
+			envName:     "default",
+			revision:    "origin/master",
+			str:         "git:origin/master:default",
+		},
+		{
+			name:  "git without revision",
+			src:   "git::default",
+			isErr: true,
+		},
 		{
 			name:  "blank",
 			isErr: true,
@@ -71,7 +86,13 @@ func TestLocation(t *testing.T) {
 
 			assert.Equal(t, tc.destination, l.Destination())
 			assert.Equal(t, tc.envName, l.EnvName())
-			assert.Equal(t, fmt.Sprintf("%s:%s", l.Destination(), l.EnvName()), l.String())
+			assert.Equal(t, tc.revision, l.Revision())
+
+			str := tc.str
+			if str == "" {
+				str = fmt.Sprintf("%s:%s", l.Destination(), l.EnvName())
+			}
+			assert.Equal(t, str, l.String())
 		})
 	}
 }
@@ -0,0 +1,378 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/util/compare"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// OutputText renders a structural diff as text.
+	OutputText = "text"
+	// OutputJSON renders a structural diff as JSON.
+	OutputJSON = "json"
+	// OutputSummary renders a structural diff as a summary table.
+	OutputSummary = "summary"
+)
+
+// ObjectStatus is the status of an object in a structural diff.
+type ObjectStatus string
+
+const (
+	// ObjectAdded is an object which only exists in the second location.
+	ObjectAdded ObjectStatus = "added"
+	// ObjectRemoved is an object which only exists in the first location.
+	ObjectRemoved ObjectStatus = "removed"
+	// ObjectChanged is an object which exists in both locations with different fields.
+	ObjectChanged ObjectStatus = "changed"
+)
+
+var (
+	// ignoredMetadataFields are metadata fields populated by the server.
+	ignoredMetadataFields = []string{
+		"creationTimestamp",
+		"generation",
+		"resourceVersion",
+		"selfLink",
+		"uid",
+	}
+
+	// ignoredAnnotations are annotations managed by ksonnet or the server.
+	ignoredAnnotations = []string{
+		metadata.AnnotationManaged,
+		"deployment.kubernetes.io/revision",
+		"kubectl.kubernetes.io/last-applied-configuration",
+	}
+)
+
+// FieldChange is a change to a single field of an object.
+type FieldChange struct {
+	// Path is the JSON path of the field.
+	Path string `json:"path"`
+	// Old is the value of the field in the first location. It is nil if the field was added.
+	Old interface{} `json:"old,omitempty"`
+	// New is the value of the field in the second location. It is nil if the field was removed.
+	New interface{} `json:"new,omitempty"`
+}
+
+// ObjectDiff is the difference for a single object.
+type ObjectDiff struct {
+	Group     string        `json:"group"`
+	Kind      string        `json:"kind"`
+	Namespace string        `json:"namespace,omitempty"`
+	Name      string        `json:"name"`
+	Status    ObjectStatus  `json:"status"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+}
+
+// String returns a description of the object.
+func (od *ObjectDiff) String() string {
+	kind := strings.ToLower(od.Kind)
+	if od.Group != "" {
+		kind = fmt.Sprintf("%s.%s", kind, od.Group)
+	}
+
+	if od.Namespace == "" {
+		return fmt.Sprintf("%s %s", kind, od.Name)
+	}
+
+	return fmt.Sprintf("%s %s.%s", kind, od.Namespace, od.Name)
+}
+
+// Summary counts objects by status.
+type Summary struct {
+	Added     int `json:"added"`
+	Removed   int `json:"removed"`
+	Changed   int `json:"changed"`
+	Unchanged int `json:"unchanged"`
+}
+
+// Result is the result of a structural diff.
+type Result struct {
+	Source1 string       `json:"source1"`
+	Source2 string       `json:"source2"`
+	Summary Summary      `json:"summary"`
+	Objects []ObjectDiff `json:"objects"`
+}
+
+// HasDifferences returns true if any objects were added, removed, or changed.
+func (r *Result) HasDifferences() bool {
+	return len(r.Objects) > 0
+}
+
+// Render writes the result in an output format.
+func (r *Result) Render(w io.Writer, output string) error {
+	switch output {
+	case OutputText, "":
+		return r.renderText(w)
+	case OutputJSON:
+		return r.renderJSON(w)
+	case OutputSummary:
+		return r.renderSummary(w)
+	default:
+		return errors.Errorf("unknown diff output %q", output)
+	}
+}
+
+func (r *Result) renderText(w io.Writer) error {
+	for _, od := range r.Objects {
+		switch od.Status {
+		case ObjectAdded:
+			fmt.Fprintf(w, "+ %s\n", od.String())
+		case ObjectRemoved:
+			fmt.Fprintf(w, "- %s\n", od.String())
+		case ObjectChanged:
+			fmt.Fprintf(w, "~ %s\n", od.String())
+		}
+
+		for _, change := range od.Changes {
+			switch {
+			case change.Old == nil:
+				fmt.Fprintf(w, "    + %s: %s\n", change.Path, formatValue(change.New))
+			case change.New == nil:
+				fmt.Fprintf(w, "    - %s: %s\n", change.Path, formatValue(change.Old))
+			default:
+				fmt.Fprintf(w, "    ~ %s: %s -> %s\n", change.Path, formatValue(change.Old), formatValue(change.New))
+			}
+		}
+	}
+
+	return nil
+}
+
+// jsonResult is the JSON document for a result.
+type jsonResult struct {
+	Source1     string       `json:"source1"`
+	Source2     string       `json:"source2"`
+	Differences bool         `json:"differences"`
+	Summary     Summary      `json:"summary"`
+	Objects     []ObjectDiff `json:"objects"`
+}
+
+func (r *Result) renderJSON(w io.Writer) error {
+	out := jsonResult{
+		Source1:     r.Source1,
+		Source2:     r.Source2,
+		Differences: r.HasDifferences(),
+		Summary:     r.Summary,
+		Objects:     r.Objects,
+	}
+	if out.Objects == nil {
+		out.Objects = []ObjectDiff{}
+	}
+
+	data, err := json.MarshalIndent(&out, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshaling diff")
+	}
+
+	fmt.Fprintln(w, string(data))
+	return nil
+}
+
+func (r *Result) renderSummary(w io.Writer) error {
+	t := table.New("diffSummary", w)
+	t.SetHeader([]string{"status", "object", "fields"})
+
+	for _, od := range r.Objects {
+		fields := ""
+		if od.Status == ObjectChanged {
+			fields = strconv.Itoa(len(od.Changes))
+		}
+
+		t.Append([]string{string(od.Status), od.String(), fields})
+	}
+
+	if err := t.Render(); err != nil {
+		return err
+	}
+
+	s := r.Summary
+	fmt.Fprintf(w, "\n%d added, %d removed, %d changed, %d unchanged\n",
+		s.Added, s.Removed, s.Changed, s.Unchanged)
+
+	return nil
+}
+
+func formatValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(data)
+}
+
+// compareObjects pairs objects by group, kind, namespace, and name and compares
+// their fields. Fields populated by the server are ignored.
+func compareObjects(objects1, objects2 []*unstructured.Unstructured) (*Result, error) {
+	m1, err := indexObjects(objects1)
+	if err != nil {
+		return nil, err
+	}
+
+	m2, err := indexObjects(objects2)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[objectKey]bool)
+	for k := range m1 {
+		keys[k] = true
+	}
+	for k := range m2 {
+		keys[k] = true
+	}
+
+	var sortedKeys []objectKey
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Slice(sortedKeys, func(i, j int) bool {
+		return sortedKeys[i].less(sortedKeys[j])
+	})
+
+	result := &Result{}
+
+	for _, k := range sortedKeys {
+		od := ObjectDiff{
+			Group:     k.group,
+			Kind:      k.kind,
+			Namespace: k.namespace,
+			Name:      k.name,
+		}
+
+		obj1, ok1 := m1[k]
+		obj2, ok2 := m2[k]
+
+		switch {
+		case !ok1:
+			od.Status = ObjectAdded
+			result.Summary.Added++
+		case !ok2:
+			od.Status = ObjectRemoved
+			result.Summary.Removed++
+		default:
+			for _, c := range compare.Values("", obj1, obj2) {
+				od.Changes = append(od.Changes, FieldChange{Path: c.Path, Old: c.Old, New: c.New})
+			}
+			if len(od.Changes) == 0 {
+				result.Summary.Unchanged++
+				continue
+			}
+			od.Status = ObjectChanged
+			result.Summary.Changed++
+		}
+
+		result.Objects = append(result.Objects, od)
+	}
+
+	return result, nil
+}
+
+// objectKey identifies an object independently of its version.
+type objectKey struct {
+	group     string
+	kind      string
+	namespace string
+	name      string
+}
+
+func (k objectKey) less(other objectKey) bool {
+	if k.group != other.group {
+		return k.group < other.group
+	}
+	if k.kind != other.kind {
+		return k.kind < other.kind
+	}
+	if k.namespace != other.namespace {
+		return k.namespace < other.namespace
+	}
+	return k.name < other.name
+}
+
+func indexObjects(objects []*unstructured.Unstructured) (map[objectKey]map[string]interface{}, error) {
+	m := make(map[objectKey]map[string]interface{})
+
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		k := objectKey{
+			group:     gvk.Group,
+			kind:      gvk.Kind,
+			namespace: obj.GetNamespace(),
+			name:      obj.GetName(),
+		}
+
+		normalized, err := normalizeObject(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		m[k] = normalized
+	}
+
+	return m, nil
+}
+
+// normalizeObject converts an object to its JSON representation and removes
+// fields which are populated by the server.
+func normalizeObject(obj *unstructured.Unstructured) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, errors.Wrapf(err, "marshaling %s", obj.GetName())
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, errors.Wrapf(err, "unmarshaling %s", obj.GetName())
+	}
+
+	delete(m, "status")
+	// apiVersion is not compared since objects are paired across versions.
+	delete(m, "apiVersion")
+
+	md, ok := m["metadata"].(map[string]interface{})
+	if !ok {
+		return m, nil
+	}
+
+	for _, field := range ignoredMetadataFields {
+		delete(md, field)
+	}
+
+	if annotations, ok := md["annotations"].(map[string]interface{}); ok {
+		for _, annotation := range ignoredAnnotations {
+			delete(annotations, annotation)
+		}
+
+		if len(annotations) == 0 {
+			delete(md, "annotations")
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,293 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func genStructuralObject(apiVersion, kind, name string, data map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+			},
+		},
+	}
+
+	for k, v := range data {
+		obj.Object[k] = v
+	}
+
+	return obj
+}
+
+func genContainers(images ...string) map[string]interface{} {
+	var containers []interface{}
+	for i := 0; i < len(images); i += 2 {
+		containers = append(containers, map[string]interface{}{
+			"name":  images[i],
+			"image": images[i+1],
+		})
+	}
+
+	return map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": containers,
+				},
+			},
+		},
+	}
+}
+
+func Test_compareObjects(t *testing.T) {
+	cases := []struct {
+		name     string
+		objects1 []*unstructured.Unstructured
+		objects2 []*unstructured.Unstructured
+		expected *Result
+	}{
+		{
+			name: "added and removed",
+			objects1: []*unstructured.Unstructured{
+				genStructuralObject("v1", "ConfigMap", "old", nil),
+			},
+			objects2: []*unstructured.Unstructured{
+				genStructuralObject("v1", "ConfigMap", "new", nil),
+			},
+			expected: &Result{
+				Summary: Summary{Added: 1, Removed: 1},
+				Objects: []ObjectDiff{
+					{Kind: "ConfigMap", Namespace: "default", Name: "new", Status: ObjectAdded},
+					{Kind: "ConfigMap", Namespace: "default", Name: "old", Status: ObjectRemoved},
+				},
+			},
+		},
+		{
+			name: "server populated fields are ignored",
+			objects1: []*unstructured.Unstructured{
+				genStructuralObject("apps/v1beta1", "Deployment", "web", map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"name":            "web",
+						"namespace":       "default",
+						"uid":             "1234",
+						"resourceVersion": "10",
+						"annotations": map[string]interface{}{
+							"ksonnet.io/managed": "{}",
+						},
+					},
+					"status": map[string]interface{}{"replicas": 1},
+				}),
+			},
+			objects2: []*unstructured.Unstructured{
+				genStructuralObject("apps/v1", "Deployment", "web", nil),
+			},
+			expected: &Result{
+				Summary: Summary{Unchanged: 1},
+			},
+		},
+		{
+			name: "reordered named list",
+			objects1: []*unstructured.Unstructured{
+				genStructuralObject("apps/v1", "Deployment", "web", genContainers("a", "a:1", "b", "b:1")),
+			},
+			objects2: []*unstructured.Unstructured{
+				genStructuralObject("apps/v1", "Deployment", "web", genContainers("b", "b:2", "a", "a:1", "c", "c:1")),
+			},
+			expected: &Result{
+				Summary: Summary{Changed: 1},
+				Objects: []ObjectDiff{
+					{
+						Group:     "apps",
+						Kind:      "Deployment",
+						Namespace: "default",
+						Name:      "web",
+						Status:    ObjectChanged,
+						Changes: []FieldChange{
+							{
+								Path: "spec.template.spec.containers[name=b].image",
+								Old:  "b:1",
+								New:  "b:2",
+							},
+							{
+								Path: "spec.template.spec.containers[name=c]",
+								New:  map[string]interface{}{"name": "c", "image": "c:1"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "unnamed list and quoted keys",
+			objects1: []*unstructured.Unstructured{
+				genStructuralObject("v1", "ConfigMap", "cm", map[string]interface{}{
+					"data": map[string]interface{}{
+						"app.json": "{}",
+						"args":     []interface{}{"a", "b"},
+					},
+				}),
+			},
+			objects2: []*unstructured.Unstructured{
+				genStructuralObject("v1", "ConfigMap", "cm", map[string]interface{}{
+					"data": map[string]interface{}{
+						"args": []interface{}{"a"},
+					},
+				}),
+			},
+			expected: &Result{
+				Summary: Summary{Changed: 1},
+				Objects: []ObjectDiff{
+					{
+						Kind:      "ConfigMap",
+						Namespace: "default",
+						Name:      "cm",
+						Status:    ObjectChanged,
+						Changes: []FieldChange{
+							{Path: `data["app.json"]`, Old: "{}"},
+							{Path: "data.args[1]", Old: "b"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := compareObjects(tc.objects1, tc.objects2)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestResult_Render(t *testing.T) {
+	result := &Result{
+		Source1: "remote:default",
+		Source2: "local:default",
+		Summary: Summary{Added: 1, Changed: 1, Unchanged: 2},
+		Objects: []ObjectDiff{
+			{Group: "apps", Kind: "Deployment", Namespace: "default", Name: "web", Status: ObjectChanged,
+				Changes: []FieldChange{
+					{Path: "spec.replicas", Old: 1, New: 2},
+					{Path: "spec.paused", New: true},
+				},
+			},
+			{Kind: "ConfigMap", Namespace: "default", Name: "cm", Status: ObjectAdded},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		output   string
+		expected string
+		isErr    bool
+	}{
+		{
+			name:   "text",
+			output: OutputText,
+			expected: `~ deployment.apps default.web
+    ~ spec.replicas: 1 -> 2
+    + spec.paused: true
++ configmap default.cm
+`,
+		},
+		{
+			name:   "summary",
+			output: OutputSummary,
+			expected: `STATUS  OBJECT                      FIELDS
+======  ======                      ======
+changed deployment.apps default.web 2
+added   configmap default.cm
+
+1 added, 0 removed, 1 changed, 2 unchanged
+`,
+		},
+		{
+			name:   "json",
+			output: OutputJSON,
+			expected: `{
+  "source1": "remote:default",
+  "source2": "local:default",
+  "differences": true,
+  "summary": {
+    "added": 1,
+    "removed": 0,
+    "changed": 1,
+    "unchanged": 2
+  },
+  "objects": [
+    {
+      "group": "apps",
+      "kind": "Deployment",
+      "namespace": "default",
+      "name": "web",
+      "status": "changed",
+      "changes": [
+        {
+          "path": "spec.replicas",
+          "old": 1,
+          "new": 2
+        },
+        {
+          "path": "spec.paused",
+          "new": true
+        }
+      ]
+    },
+    {
+      "group": "",
+      "kind": "ConfigMap",
+      "namespace": "default",
+      "name": "cm",
+      "status": "added"
+    }
+  ]
+}
+`,
+		},
+		{
+			name:   "unknown output",
+			output: "yaml",
+			isErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := result.Render(&buf, tc.output)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
@@ -65,6 +65,11 @@ func newCreator(a app.App, d Destination, name, k8sSpecFlag string, overrideData
 }
 
 func (c *creator) Create() error {
+	if strings.Contains(c.name, app.DestinationSeparator) {
+		return errors.Errorf("environment name %q can not contain %q; it selects a destination of an environment",
+			c.name, app.DestinationSeparator)
+	}
+
 	if c.environmentExists() {
 		return errors.Errorf("environment %q already exists", c.name)
 	}
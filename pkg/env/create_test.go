@@ -45,3 +45,16 @@ func TestCreate(t *testing.T) {
 		checkExists(t, fs, "/environments/newenv/params.libsonnet")
 	})
 }
+
+func TestCreate_destination_name(t *testing.T) {
+	withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
+		d := NewDestination("http://example.com", "default")
+		var od, pd []byte
+		err := Create(appMock, d, "prod@us-east", "version:v1.8.7", od, pd, false)
+		require.Error(t, err)
+
+		exists, err := afero.Exists(fs, "/environments/prod@us-east")
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+}
@@ -122,7 +122,7 @@ func evaluateMain(a app.App, envName, snippet, components, paramsStr string, opt
 		return "", err
 	}
 
-	appEnv, err := a.Environment(envName)
+	appEnv, err := app.ResolveEnvironment(a, envName)
 	if err != nil {
 		return "", err
 	}
@@ -203,7 +203,7 @@ for x in components
 `
 
 func envRoot(a app.App, envName string) (string, error) {
-	envSpec, err := a.Environment(envName)
+	envSpec, err := app.ResolveEnvironment(a, envName)
 	if err != nil {
 		return "", err
 	}
@@ -223,7 +223,7 @@ func Path(a app.App, envName string, path ...string) (string, error) {
 }
 
 func environmentsCode(a app.App, envName string) (string, error) {
-	envDetails, err := a.Environment(envName)
+	envDetails, err := app.ResolveEnvironment(a, envName)
 	if err != nil {
 		return "", err
 	}
@@ -115,27 +115,41 @@ type SetParamsConfig struct {
 
 // SetParams sets params for an environment.
 func SetParams(envName, component string, p param.Params, config SetParamsConfig) error {
-	if err := ensureEnvExists(config.App, envName); err != nil {
+	return updateParams(config.App, envName, component, func(eps *params.EnvParamSet, text string) (string, error) {
+		return eps.Set(component, text, p)
+	})
+}
+
+// SetParamValues sets decoded param values for an environment. Unlike
+// SetParams, string values are kept as strings.
+func SetParamValues(envName, component string, values map[string]interface{}, config SetParamsConfig) error {
+	return updateParams(config.App, envName, component, func(eps *params.EnvParamSet, text string) (string, error) {
+		return eps.SetValues(component, text, values)
+	})
+}
+
+// updateParams rewrites the params of an environment with fn.
+func updateParams(a app.App, envName, component string, fn func(eps *params.EnvParamSet, text string) (string, error)) error {
+	if err := ensureEnvExists(a, envName); err != nil {
 		return err
 	}
 
-	path, err := Path(config.App, envName, paramsFileName)
+	path, err := Path(a, envName, paramsFileName)
 	if err != nil {
 		return err
 	}
 
-	text, err := afero.ReadFile(config.App.Fs(), path)
+	text, err := afero.ReadFile(a.Fs(), path)
 	if err != nil {
 		return err
 	}
 
-	eps := params.NewEnvParamSet()
-	updated, err := eps.Set(component, string(text), p)
+	updated, err := fn(params.NewEnvParamSet(), string(text))
 	if err != nil {
 		return err
 	}
 
-	err = afero.WriteFile(config.App.Fs(), path, []byte(updated), app.DefaultFilePermissions)
+	err = afero.WriteFile(a.Fs(), path, []byte(updated), app.DefaultFilePermissions)
 	if err != nil {
 		return err
 	}
@@ -64,6 +64,23 @@ func TestSetParams(t *testing.T) {
 	})
 }
 
+func TestSetParamValues(t *testing.T) {
+	withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
+		config := SetParamsConfig{
+			App: appMock,
+		}
+
+		values := map[string]interface{}{
+			"foo": "bar",
+		}
+
+		err := SetParamValues("env1", "component1", values, config)
+		require.NoError(t, err)
+
+		compareOutput(t, fs, "updated-params.libsonnet", "/environments/env1/params.libsonnet")
+	})
+}
+
 func TestDeleteParams(t *testing.T) {
 	withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
 		err := DeleteParam(appMock, "env1", "component1", "foo")
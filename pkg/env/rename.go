@@ -25,6 +25,7 @@ import (
 	"github.com/spf13/afero"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
 )
 
 // Rename renames an environment
@@ -63,10 +64,21 @@ func (r *renamer) Rename() error {
 
 	log.Infof("Setting environment name from %q to %q", r.from, r.to)
 
+	resealed, err := secrets.Reseal(r.app, r.from, r.to)
+	if err != nil {
+		return errors.Wrap(err, "reseal secrets")
+	}
+
 	if err := r.app.RenameEnvironment(r.from, r.to, r.override); err != nil {
 		return err
 	}
 
+	if resealed != nil {
+		if err := resealed.Save(r.app, r.to); err != nil {
+			return errors.Wrap(err, "save secrets")
+		}
+	}
+
 	if err := cleanEmptyDirs(r.app); err != nil {
 		return errors.Wrap(err, "clean empty directories")
 	}
@@ -16,13 +16,17 @@
 package env
 
 import (
+	"os"
 	"testing"
 
 	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
 )
 
 func TestRename(t *testing.T) {
@@ -36,3 +40,30 @@ func TestRename(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestRename_secrets(t *testing.T) {
+	old := os.Getenv(secrets.KeyFileEnvVar)
+	require.NoError(t, os.Setenv(secrets.KeyFileEnvVar, "/keys/secret.key"))
+	defer os.Setenv(secrets.KeyFileEnvVar, old)
+
+	withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
+		require.NoError(t, secrets.Set(appMock, "env1", "guestbook", "password", "s3cret"))
+
+		appMock.On("RenameEnvironment", "env1", "env1-updated", false).
+			Run(func(args mock.Arguments) {
+				err := fs.Rename("/environments/env1", "/environments/env1-updated")
+				require.NoError(t, err)
+			}).
+			Return(nil)
+
+		envSpec := &app.EnvironmentConfig{Path: "env1-updated"}
+		appMock.On("Environment", "env1-updated").Return(envSpec, nil)
+
+		err := Rename(appMock, "env1", "env1-updated", false)
+		require.NoError(t, err)
+
+		values, err := secrets.Decrypt(appMock, "env1-updated")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret", values["guestbook"]["password"])
+	})
+}
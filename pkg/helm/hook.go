@@ -0,0 +1,109 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package helm
+
+import (
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+)
+
+const (
+	annotationHook             = "helm.sh/hook"
+	annotationHookWeight       = "helm.sh/hook-weight"
+	annotationHookDeletePolicy = "helm.sh/hook-delete-policy"
+)
+
+// hookPhases maps Helm hooks to the ksonnet phases they run in.
+var hookPhases = map[string]string{
+	"pre-install":  metadata.PhasePreApply,
+	"pre-upgrade":  metadata.PhasePreApply,
+	"post-install": metadata.PhasePostApply,
+	"post-upgrade": metadata.PhasePostApply,
+	"pre-delete":   metadata.PhasePreDelete,
+}
+
+// HookPhase returns the ksonnet phase for the value of a Helm hook annotation. A
+// hook annotation can list multiple hooks. The phase of the first hook which has
+// a ksonnet equivalent is used. It returns false if none of the hooks are supported.
+func HookPhase(hook string) (string, bool) {
+	for _, name := range strings.Split(hook, ",") {
+		if phase, ok := hookPhases[strings.TrimSpace(name)]; ok {
+			return phase, true
+		}
+	}
+
+	return "", false
+}
+
+// convertHook converts the Helm hook annotations of a rendered object to ksonnet
+// hook annotations. It returns false if the object is a hook which is not supported,
+// such as a test.
+func convertHook(m map[string]interface{}) bool {
+	meta, ok := m["metadata"].(map[string]interface{})
+	if !ok {
+		return true
+	}
+
+	annotations, ok := meta["annotations"].(map[string]interface{})
+	if !ok {
+		return true
+	}
+
+	hook, ok := annotations[annotationHook].(string)
+	if !ok {
+		return true
+	}
+
+	phase, ok := HookPhase(hook)
+	if !ok {
+		return false
+	}
+
+	annotations[metadata.AnnotationPhase] = phase
+
+	if weight, ok := annotations[annotationHookWeight].(string); ok {
+		annotations[metadata.AnnotationHookWeight] = strings.TrimSpace(weight)
+	}
+
+	policies, _ := annotations[annotationHookDeletePolicy].(string)
+	annotations[metadata.AnnotationHookDeletePolicy] = hookDeletePolicy(policies)
+
+	return true
+}
+
+// hookDeletePolicy converts Helm hook delete policies to a ksonnet hook delete
+// policy. Helm keeps hooks unless a policy says otherwise.
+func hookDeletePolicy(policies string) string {
+	var succeeded, failed bool
+	for _, policy := range strings.Split(policies, ",") {
+		switch strings.TrimSpace(policy) {
+		case "hook-succeeded":
+			succeeded = true
+		case "hook-failed":
+			failed = true
+		}
+	}
+
+	switch {
+	case succeeded && failed:
+		return metadata.HookDeletePolicyAlways
+	case succeeded:
+		return metadata.HookDeletePolicySucceeded
+	default:
+		return metadata.HookDeletePolicyNever
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookPhase(t *testing.T) {
+	cases := []struct {
+		hook     string
+		expected string
+		ok       bool
+	}{
+		{hook: "pre-install", expected: "pre-apply", ok: true},
+		{hook: "pre-install, pre-upgrade", expected: "pre-apply", ok: true},
+		{hook: "post-upgrade", expected: "post-apply", ok: true},
+		{hook: "pre-delete", expected: "pre-delete", ok: true},
+		{hook: "post-delete,post-install", expected: "post-apply", ok: true},
+		{hook: "test-success"},
+		{hook: "pre-rollback"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.hook, func(t *testing.T) {
+			phase, ok := HookPhase(tc.hook)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, phase)
+		})
+	}
+}
+
+func Test_convertHook(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]interface{}
+		expected    map[string]interface{}
+		keep        bool
+	}{
+		{
+			name:        "not a hook",
+			annotations: map[string]interface{}{"app": "redis"},
+			expected:    map[string]interface{}{"app": "redis"},
+			keep:        true,
+		},
+		{
+			name: "supported hook",
+			annotations: map[string]interface{}{
+				"helm.sh/hook":               "pre-install,pre-upgrade",
+				"helm.sh/hook-weight":        "-5",
+				"helm.sh/hook-delete-policy": "hook-succeeded",
+			},
+			expected: map[string]interface{}{
+				"helm.sh/hook":                  "pre-install,pre-upgrade",
+				"helm.sh/hook-weight":           "-5",
+				"helm.sh/hook-delete-policy":    "hook-succeeded",
+				"ksonnet.io/phase":              "pre-apply",
+				"ksonnet.io/hook-weight":        "-5",
+				"ksonnet.io/hook-delete-policy": "succeeded",
+			},
+			keep: true,
+		},
+		{
+			name: "hook without delete policy is kept",
+			annotations: map[string]interface{}{
+				"helm.sh/hook": "post-install",
+			},
+			expected: map[string]interface{}{
+				"helm.sh/hook":                  "post-install",
+				"ksonnet.io/phase":              "post-apply",
+				"ksonnet.io/hook-delete-policy": "never",
+			},
+			keep: true,
+		},
+		{
+			name: "hook deleted after success or failure",
+			annotations: map[string]interface{}{
+				"helm.sh/hook":               "pre-delete",
+				"helm.sh/hook-delete-policy": "hook-succeeded,hook-failed",
+			},
+			expected: map[string]interface{}{
+				"helm.sh/hook":                  "pre-delete",
+				"helm.sh/hook-delete-policy":    "hook-succeeded,hook-failed",
+				"ksonnet.io/phase":              "pre-delete",
+				"ksonnet.io/hook-delete-policy": "always",
+			},
+			keep: true,
+		},
+		{
+			name: "unsupported hook",
+			annotations: map[string]interface{}{
+				"helm.sh/hook": "test-success",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":        "redis",
+					"annotations": tc.annotations,
+				},
+			}
+
+			keep := convertHook(m)
+			require.Equal(t, tc.keep, keep)
+			if !keep {
+				return
+			}
+
+			require.Equal(t, tc.expected, tc.annotations)
+		})
+	}
+}
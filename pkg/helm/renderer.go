@@ -50,7 +50,7 @@ func NewRenderer(a app.App, envName string) *Renderer {
 }
 
 func (r *Renderer) k8sVersion() (string, error) {
-	env, err := r.app.Environment(r.envName)
+	env, err := app.ResolveEnvironment(r.app, r.envName)
 	if err != nil {
 		return "", errors.Wrapf(err, "retrieving environment %q", r.envName)
 	}
@@ -59,7 +59,7 @@ func (r *Renderer) k8sVersion() (string, error) {
 }
 
 func (r *Renderer) namespace() (string, error) {
-	env, err := r.app.Environment(r.envName)
+	env, err := app.ResolveEnvironment(r.app, r.envName)
 	if err != nil {
 		return "", errors.Wrapf(err, "retrieving environment %q", r.envName)
 	}
@@ -168,6 +168,11 @@ func (r *Renderer) Render(repoName, chartName, chartVersion, componentName strin
 				return nil, errors.Wrapf(err, "unmarshalling %s", name)
 			}
 
+			if !convertHook(m) {
+				logrus.Debugf("skipping unsupported Helm hook in %s", name)
+				continue
+			}
+
 			out = append(out, m)
 		}
 	}
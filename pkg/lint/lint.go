@@ -0,0 +1,192 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package lint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	paramsFile = "params.libsonnet"
+)
+
+// Problem is a problem found in a module's component sources.
+type Problem struct {
+	// Component is the namespaced name of the component.
+	Component string
+	// Path is the path of the file containing the problem.
+	Path string
+	// Line is the line of the problem in the file. It is zero if the problem
+	// isn't on a line.
+	Line int
+	// Message describes the problem.
+	Message string
+}
+
+// Location returns the location of the problem as path:line.
+func (p *Problem) Location() string {
+	if p.Line == 0 {
+		return p.Path
+	}
+
+	return fmt.Sprintf("%s:%d", p.Path, p.Line)
+}
+
+// Module lints the components of a module. It reports params defined in the
+// module's params that no component references, references to params that are
+// not defined, and component names used by more than one file.
+func Module(fs afero.Fs, m component.Module, components []component.Component) ([]Problem, error) {
+	var problems []Problem
+
+	paramsPath := filepath.Join(m.Dir(), paramsFile)
+	r, err := m.ParamsSource()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", paramsPath)
+	}
+
+	mp, err := readModuleParams(paramsPath, string(src))
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", paramsPath)
+	}
+
+	byName := make(map[string][]component.Component)
+	var names []string
+	for _, c := range components {
+		name := c.Name(false)
+		if _, ok := byName[name]; !ok {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], c)
+	}
+	sort.Strings(names)
+
+	used := make(map[string]map[string]bool)
+	dynamic := make(map[string]bool)
+
+	for _, name := range names {
+		members := byName[name]
+		if len(members) > 1 {
+			var types []string
+			for _, c := range members {
+				types = append(types, c.Type())
+			}
+
+			problems = append(problems, Problem{
+				Component: members[0].Name(true),
+				Path:      m.Dir(),
+				Message: fmt.Sprintf("component name %q is used by multiple files (%s)",
+					name, strings.Join(types, ", ")),
+			})
+		}
+
+		for _, c := range members {
+			if c.Type() != component.TypeJsonnet {
+				// Params of other components patch their objects, so they are all used.
+				dynamic[name] = true
+				continue
+			}
+
+			path := filepath.Join(m.Dir(), name+".jsonnet")
+			data, err := afero.ReadFile(fs, path)
+			if err != nil {
+				return nil, err
+			}
+
+			pr, err := findParamRefs(path, string(data))
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing %s", path)
+			}
+
+			for k := range pr.dynamic {
+				dynamic[k] = true
+			}
+
+			for _, ref := range pr.refs {
+				if used[ref.component] == nil {
+					used[ref.component] = make(map[string]bool)
+				}
+				used[ref.component][ref.key] = true
+
+				if !mp.has(ref.component, ref.key) {
+					problems = append(problems, Problem{
+						Component: c.Name(true),
+						Path:      path,
+						Line:      ref.line,
+						Message:   fmt.Sprintf("param %q of component %q is not defined", ref.key, ref.component),
+					})
+				}
+			}
+		}
+	}
+
+	var paramComponents []string
+	for name := range mp.components {
+		paramComponents = append(paramComponents, name)
+	}
+	sort.Strings(paramComponents)
+
+	for _, name := range paramComponents {
+		if _, ok := byName[name]; !ok {
+			problems = append(problems, Problem{
+				Component: namespaced(m, name),
+				Path:      paramsPath,
+				Line:      mp.lines[name],
+				Message:   fmt.Sprintf("params are defined for component %q, which does not exist", name),
+			})
+			continue
+		}
+
+		if dynamic[name] {
+			continue
+		}
+
+		for _, p := range mp.components[name] {
+			if !used[name][p.key] {
+				problems = append(problems, Problem{
+					Component: namespaced(m, name),
+					Path:      paramsPath,
+					Line:      p.line,
+					Message:   fmt.Sprintf("param %q of component %q is not used", p.key, name),
+				})
+			}
+		}
+	}
+
+	return problems, nil
+}
+
+// namespaced returns the name of a component in a module.
+func namespaced(m component.Module, name string) string {
+	if m.Name() == "/" || m.Name() == "" {
+		return name
+	}
+
+	return m.Name() + "." + name
+}
@@ -0,0 +1,151 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package lint
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testParams = `{
+  global: {
+    replicas: 1,
+  },
+  components: {
+    guestbook: {
+      image: "gcr.io/heptio-images/ks-guestbook-demo:0.1",
+      port: 80,
+      unused: true,
+    },
+    deployment: {
+      replicas: 2,
+    },
+    dynamic: {
+      name: "dynamic",
+    },
+    removed: {
+      name: "removed",
+    },
+  },
+}
+`
+
+	testGuestbook = `local env = std.extVar("__ksonnet/env");
+local params = std.extVar("__ksonnet/params").components.guestbook;
+{
+  image: params.image,
+  port: params["port"],
+  replicas: params.replicas,
+  name: params.nmae,
+  other: std.extVar("__ksonnet/params").components.deployment.replicas,
+}
+`
+
+	testDynamic = `local params = std.extVar("__ksonnet/params").components.dynamic;
+std.objectFields(params)
+`
+)
+
+func TestModule(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		files := map[string]string{
+			"/app/components/params.libsonnet":   testParams,
+			"/app/components/guestbook.jsonnet":  testGuestbook,
+			"/app/components/dynamic.jsonnet":    testDynamic,
+			"/app/components/deployment.yaml":    "apiVersion: apps/v1beta2\nkind: Deployment\n",
+			"/app/components/deployment.jsonnet": `{}`,
+		}
+		for path, content := range files {
+			require.NoError(t, afero.WriteFile(fs, path, []byte(content), 0644))
+		}
+
+		m := component.NewModule(a, "")
+		components, err := m.Components()
+		require.NoError(t, err)
+
+		problems, err := Module(fs, m, components)
+		require.NoError(t, err)
+
+		expected := []Problem{
+			{
+				Component: "deployment",
+				Path:      "/app/components",
+				Message:   `component name "deployment" is used by multiple files (jsonnet, yaml)`,
+			},
+			{
+				Component: "guestbook",
+				Path:      "/app/components/guestbook.jsonnet",
+				Line:      7,
+				Message:   `param "nmae" of component "guestbook" is not defined`,
+			},
+			{
+				Component: "guestbook",
+				Path:      "/app/components/params.libsonnet",
+				Line:      9,
+				Message:   `param "unused" of component "guestbook" is not used`,
+			},
+			{
+				Component: "removed",
+				Path:      "/app/components/params.libsonnet",
+				Line:      17,
+				Message:   `params are defined for component "removed", which does not exist`,
+			},
+		}
+		require.Equal(t, expected, problems)
+	})
+}
+
+func TestProblem_Location(t *testing.T) {
+	p := Problem{Path: "/app/components/guestbook.jsonnet"}
+	require.Equal(t, "/app/components/guestbook.jsonnet", p.Location())
+
+	p.Line = 3
+	require.Equal(t, "/app/components/guestbook.jsonnet:3", p.Location())
+}
+
+func Test_findParamRefs_invalid(t *testing.T) {
+	_, err := findParamRefs("invalid.jsonnet", "{")
+	require.Error(t, err)
+}
+
+func Test_findParamRefs_scope(t *testing.T) {
+	src := `local params = std.extVar("__ksonnet/params").components.guestbook;
+local f(params) = params.arg;
+{
+  image: params.image,
+  local other = params,
+  shadowed: local params = {name: "x"}; params.name,
+  object: { local params = self.x, x: 1, y: params },
+  list: [params.item for params in [{item: 1}]],
+  method(params):: params.method,
+}
+`
+
+	pr, err := findParamRefs("scope.jsonnet", src)
+	require.NoError(t, err)
+
+	expected := []paramRef{
+		{component: "guestbook", key: "image", line: 4},
+	}
+	require.Equal(t, expected, pr.refs)
+	require.Equal(t, map[string]bool{"guestbook": true}, pr.dynamic)
+}
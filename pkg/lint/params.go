@@ -0,0 +1,408 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package lint
+
+import (
+	"github.com/google/go-jsonnet/ast"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+	jsonnetutil "github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+)
+
+const (
+	paramsExtVar = "__ksonnet/params"
+)
+
+// paramRef is a reference to a component param in Jsonnet.
+type paramRef struct {
+	component string
+	key       string
+	line      int
+}
+
+// paramRefs are the references to component params in a Jsonnet source.
+type paramRefs struct {
+	refs []paramRef
+	// dynamic are the components whose params are used other than by
+	// referencing a key, e.g. by passing them to a function. Their keys can't be
+	// checked for use.
+	dynamic map[string]bool
+}
+
+// findParamRefs finds the references to component params in a Jsonnet source.
+// Params are referenced through `std.extVar("__ksonnet/params").components.<name>`,
+// either directly or through a local bound to it.
+func findParamRefs(filename, src string) (*paramRefs, error) {
+	node, err := jsonnetutil.ParseNode(filename, src)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := paramVars(node)
+
+	paramsOf := func(n ast.Node) (string, bool) {
+		if v, ok := n.(*ast.Var); ok {
+			name, ok := vars[v]
+			return name, ok
+		}
+
+		return componentParams(n)
+	}
+
+	pr := &paramRefs{dynamic: make(map[string]bool)}
+	indexed := make(map[ast.Node]bool)
+
+	jsonnetutil.Walk(node, func(n ast.Node) {
+		index, ok := n.(*ast.Index)
+		if !ok {
+			return
+		}
+
+		name, ok := paramsOf(index.Target)
+		if !ok {
+			return
+		}
+
+		key, ok := indexKey(index)
+		if !ok {
+			return
+		}
+
+		indexed[index.Target] = true
+		pr.refs = append(pr.refs, paramRef{component: name, key: key, line: index.Loc().Begin.Line})
+	})
+
+	jsonnetutil.Walk(node, func(n ast.Node) {
+		if v, ok := n.(*ast.Var); ok && !indexed[n] {
+			if name, ok := vars[v]; ok {
+				pr.dynamic[name] = true
+			}
+		}
+	})
+
+	return pr, nil
+}
+
+// scope maps the names bound in a Jsonnet scope to the component whose params
+// they are bound to. Names bound to anything else map to "".
+type scope map[ast.Identifier]string
+
+// extend returns a copy of the scope for binding names in an inner scope.
+func (s scope) extend() scope {
+	inner := make(scope, len(s))
+	for k, v := range s {
+		inner[k] = v
+	}
+
+	return inner
+}
+
+// paramVars finds the variables which refer to locals bound to a component's
+// params. Names are resolved by scope, so a local or parameter which shadows
+// a params local isn't mistaken for it.
+func paramVars(node ast.Node) map[*ast.Var]string {
+	vars := make(map[*ast.Var]string)
+	resolveParamVars(node, scope{}, vars)
+	return vars
+}
+
+// nolint: gocyclo
+func resolveParamVars(n ast.Node, s scope, vars map[*ast.Var]string) {
+	if n == nil {
+		return
+	}
+
+	switch t := n.(type) {
+	case *ast.Var:
+		if name := s[t.Id]; name != "" {
+			vars[t] = name
+		}
+	case *ast.Local:
+		// Locals can refer to each other, so they are all bound before any
+		// of them are resolved.
+		inner := s.extend()
+		for _, bind := range t.Binds {
+			inner[bind.Variable] = ""
+			if bind.Fun == nil {
+				inner[bind.Variable], _ = componentParams(bind.Body)
+			}
+		}
+
+		for _, bind := range t.Binds {
+			if bind.Fun != nil {
+				resolveFunctionParamVars(&bind.Fun.Parameters, bind.Body, inner, vars)
+				continue
+			}
+			resolveParamVars(bind.Body, inner, vars)
+		}
+		resolveParamVars(t.Body, inner, vars)
+	case *ast.Function:
+		resolveFunctionParamVars(&t.Parameters, t.Body, s, vars)
+	case *ast.Object:
+		resolveFieldParamVars(t.Fields, s, vars)
+	case *astext.Object:
+		var fields ast.ObjectFields
+		for _, field := range t.Fields {
+			fields = append(fields, field.ObjectField)
+		}
+		resolveFieldParamVars(fields, s, vars)
+	case *ast.ArrayComp:
+		resolveParamVars(t.Body, resolveForSpecParamVars(&t.Spec, s, vars), vars)
+	case *ast.ObjectComp:
+		resolveFieldParamVars(t.Fields, resolveForSpecParamVars(&t.Spec, s, vars), vars)
+	default:
+		for _, child := range jsonnetutil.Children(n) {
+			resolveParamVars(child, s, vars)
+		}
+	}
+}
+
+// resolveFunctionParamVars resolves a function's default arguments and body.
+// The function's parameters shadow names in the enclosing scope.
+func resolveFunctionParamVars(params *ast.Parameters, body ast.Node, s scope, vars map[*ast.Var]string) {
+	inner := s.extend()
+	for _, id := range params.Required {
+		inner[id] = ""
+	}
+	for _, p := range params.Optional {
+		inner[p.Name] = ""
+	}
+
+	for _, p := range params.Optional {
+		resolveParamVars(p.DefaultArg, inner, vars)
+	}
+	resolveParamVars(body, inner, vars)
+}
+
+// resolveFieldParamVars resolves the fields of an object. Object locals are
+// visible to every field's value, but not to field names.
+func resolveFieldParamVars(fields ast.ObjectFields, s scope, vars map[*ast.Var]string) {
+	inner := s.extend()
+	for _, field := range fields {
+		if field.Kind != ast.ObjectLocal || field.Id == nil {
+			continue
+		}
+
+		inner[*field.Id] = ""
+		if field.Params == nil {
+			inner[*field.Id], _ = componentParams(field.Expr2)
+		}
+	}
+
+	for _, field := range fields {
+		resolveParamVars(field.Expr1, s, vars)
+
+		if field.Params != nil {
+			resolveFunctionParamVars(field.Params, field.Expr2, inner, vars)
+		} else {
+			resolveParamVars(field.Expr2, inner, vars)
+		}
+		resolveParamVars(field.Expr3, inner, vars)
+	}
+}
+
+// resolveForSpecParamVars resolves the clauses of a comprehension, outermost
+// first. It returns the scope of the comprehension's body.
+func resolveForSpecParamVars(spec *ast.ForSpec, s scope, vars map[*ast.Var]string) scope {
+	var specs []*ast.ForSpec
+	for ; spec != nil; spec = spec.Outer {
+		specs = append([]*ast.ForSpec{spec}, specs...)
+	}
+
+	inner := s
+	for _, spec := range specs {
+		resolveParamVars(spec.Expr, inner, vars)
+
+		inner = inner.extend()
+		inner[spec.VarName] = ""
+
+		for _, cond := range spec.Conditions {
+			resolveParamVars(cond.Expr, inner, vars)
+		}
+	}
+
+	return inner
+}
+
+// componentParams returns the name of the component if a node is
+// `std.extVar("__ksonnet/params").components.<name>`.
+func componentParams(n ast.Node) (string, bool) {
+	index, ok := n.(*ast.Index)
+	if !ok {
+		return "", false
+	}
+
+	components, ok := index.Target.(*ast.Index)
+	if !ok {
+		return "", false
+	}
+
+	if key, ok := indexKey(components); !ok || key != "components" {
+		return "", false
+	}
+
+	if !isParamsExtVar(components.Target) {
+		return "", false
+	}
+
+	return indexKey(index)
+}
+
+// isParamsExtVar returns true if a node is `std.extVar("__ksonnet/params")`.
+func isParamsExtVar(n ast.Node) bool {
+	apply, ok := n.(*ast.Apply)
+	if !ok || len(apply.Arguments.Positional) != 1 {
+		return false
+	}
+
+	fn, ok := apply.Target.(*ast.Index)
+	if !ok {
+		return false
+	}
+
+	std, ok := fn.Target.(*ast.Var)
+	if !ok || std.Id != "std" {
+		return false
+	}
+
+	if key, ok := indexKey(fn); !ok || key != "extVar" {
+		return false
+	}
+
+	arg, ok := apply.Arguments.Positional[0].(*ast.LiteralString)
+	return ok && arg.Value == paramsExtVar
+}
+
+// indexKey returns the key of an index if it is an identifier or a string.
+func indexKey(index *ast.Index) (string, bool) {
+	if index.Id != nil {
+		return string(*index.Id), true
+	}
+
+	if s, ok := index.Index.(*ast.LiteralString); ok {
+		return s.Value, true
+	}
+
+	return "", false
+}
+
+// moduleParam is a param defined in a module's params.
+type moduleParam struct {
+	key  string
+	line int
+}
+
+// moduleParams are the params defined in a module's params.libsonnet.
+type moduleParams struct {
+	global     map[string]bool
+	components map[string][]moduleParam
+	// lines are the lines where component params are defined.
+	lines map[string]int
+}
+
+// has returns true if a param is defined for a component, either by the
+// component or globally.
+func (mp *moduleParams) has(component, key string) bool {
+	if mp.global[key] {
+		return true
+	}
+
+	for _, p := range mp.components[component] {
+		if p.key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readModuleParams reads the params defined in a module's params.libsonnet.
+func readModuleParams(filename, src string) (*moduleParams, error) {
+	obj, err := jsonnetutil.Parse(filename, src)
+	if err != nil {
+		return nil, err
+	}
+
+	mp := &moduleParams{
+		global:     make(map[string]bool),
+		components: make(map[string][]moduleParam),
+		lines:      make(map[string]int),
+	}
+
+	if global := childObject(obj, "global"); global != nil {
+		for _, field := range global.Fields {
+			id, err := jsonnetutil.FieldID(field)
+			if err != nil {
+				return nil, errors.Wrap(err, "reading global params")
+			}
+
+			mp.global[id] = true
+		}
+	}
+
+	components := childObject(obj, "components")
+	if components == nil {
+		return mp, nil
+	}
+
+	for _, field := range components.Fields {
+		name, err := jsonnetutil.FieldID(field)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading component params")
+		}
+
+		mp.components[name] = nil
+		if field.Expr2 != nil {
+			mp.lines[name] = field.Expr2.Loc().Begin.Line
+		}
+
+		params, ok := field.Expr2.(*astext.Object)
+		if !ok {
+			continue
+		}
+
+		for _, pf := range params.Fields {
+			key, err := jsonnetutil.FieldID(pf)
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading params for component %q", name)
+			}
+
+			p := moduleParam{key: key}
+			if pf.Expr2 != nil {
+				p.line = pf.Expr2.Loc().Begin.Line
+			}
+			mp.components[name] = append(mp.components[name], p)
+		}
+	}
+
+	return mp, nil
+}
+
+// childObject returns the object value of a field, or nil if the field doesn't
+// exist or isn't an object.
+func childObject(obj *astext.Object, name string) *astext.Object {
+	for _, field := range obj.Fields {
+		id, err := jsonnetutil.FieldID(field)
+		if err != nil || id != name {
+			continue
+		}
+
+		child, _ := field.Expr2.(*astext.Object)
+		return child
+	}
+
+	return nil
+}
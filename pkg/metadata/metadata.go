@@ -29,6 +29,18 @@ const (
 	// AnnotationManaged annotation holds the pristine object.
 	AnnotationManaged = "ksonnet.io/managed"
 
+	// AnnotationPhase marks an object as a hook which is run during a phase
+	// of apply or delete instead of being applied with the other objects.
+	AnnotationPhase = "ksonnet.io/phase"
+
+	// AnnotationHookDeletePolicy controls when a hook object is deleted after
+	// it has run.
+	AnnotationHookDeletePolicy = "ksonnet.io/hook-delete-policy"
+
+	// AnnotationHookWeight orders hooks in a phase. Hooks with lower weights
+	// run first.
+	AnnotationHookWeight = "ksonnet.io/hook-weight"
+
 	// LabelDeployManager label signifies an object is deployed with ksonnet.
 	LabelDeployManager = "app.kubernetes.io/deploy-manager"
 
@@ -40,4 +52,19 @@ const (
 	GcStrategyAuto = "auto"
 	// GcStrategyIgnore means this object should be ignored by garbage collection
 	GcStrategyIgnore = "ignore"
+
+	// PhasePreApply hooks run before objects are applied.
+	PhasePreApply = "pre-apply"
+	// PhasePostApply hooks run after objects are applied.
+	PhasePostApply = "post-apply"
+	// PhasePreDelete hooks run before objects are deleted.
+	PhasePreDelete = "pre-delete"
+
+	// HookDeletePolicySucceeded deletes a hook after it succeeds. Failed hooks
+	// are kept so they can be inspected. This is the default.
+	HookDeletePolicySucceeded = "succeeded"
+	// HookDeletePolicyAlways deletes a hook after it runs.
+	HookDeletePolicyAlways = "always"
+	// HookDeletePolicyNever keeps a hook after it runs.
+	HookDeletePolicyNever = "never"
 )
@@ -0,0 +1,198 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package openapi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	crdGroup = "apiextensions.k8s.io"
+	crdKind  = "CustomResourceDefinition"
+)
+
+// IsCRD returns true if an object is a CustomResourceDefinition.
+func IsCRD(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	return gvk.Group == crdGroup && gvk.Kind == crdKind
+}
+
+// IsCustomResource returns true if an object's kind is not built into Kubernetes.
+// Only custom resources have groups containing dots, except for the built-in
+// groups in the k8s.io domain.
+func IsCustomResource(obj *unstructured.Unstructured) bool {
+	group := obj.GroupVersionKind().Group
+	return strings.Contains(group, ".") && !strings.HasSuffix(group, ".k8s.io")
+}
+
+// crdKinds returns the kinds defined by a CustomResourceDefinition.
+func crdKinds(crd *unstructured.Unstructured) ([]schema.GroupVersionKind, error) {
+	group, kind, err := crdGroupKind(crd)
+	if err != nil {
+		return nil, err
+	}
+
+	var kinds []schema.GroupVersionKind
+	for _, version := range crdVersions(crd) {
+		kinds = append(kinds, schema.GroupVersionKind{Group: group, Version: version, Kind: kind})
+	}
+
+	return kinds, nil
+}
+
+func crdGroupKind(crd *unstructured.Unstructured) (string, string, error) {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	if group == "" || kind == "" {
+		return "", "", errors.New("group and kind are required")
+	}
+
+	return group, kind, nil
+}
+
+// crdVersions returns the versions served by a CustomResourceDefinition.
+func crdVersions(crd *unstructured.Unstructured) []string {
+	var versions []string
+	seen := make(map[string]bool)
+
+	add := func(version string) {
+		if version != "" && !seen[version] {
+			seen[version] = true
+			versions = append(versions, version)
+		}
+	}
+
+	version, _, _ := unstructured.NestedString(crd.Object, "spec", "version")
+	add(version)
+
+	list, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range list {
+		if m, ok := v.(map[string]interface{}); ok {
+			name, _, _ := unstructured.NestedString(m, "name")
+			add(name)
+		}
+	}
+
+	return versions
+}
+
+// crdSchemas returns the validation schemas of a CustomResourceDefinition by the
+// kinds it defines. A schema defined for a version takes precedence over the
+// schema for the whole CRD.
+func crdSchemas(crd *unstructured.Unstructured) (map[schema.GroupVersionKind]*spec.Schema, error) {
+	group, kind, err := crdGroupKind(crd)
+	if err != nil {
+		return nil, err
+	}
+
+	common, _, _ := unstructured.NestedMap(crd.Object, "spec", "validation", "openAPIV3Schema")
+
+	versionSchemas := make(map[string]map[string]interface{})
+	if version, _, _ := unstructured.NestedString(crd.Object, "spec", "version"); version != "" {
+		versionSchemas[version] = common
+	}
+
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _, _ := unstructured.NestedString(m, "name")
+		if name == "" {
+			continue
+		}
+
+		s, ok, _ := unstructured.NestedMap(m, "schema", "openAPIV3Schema")
+		if !ok {
+			s = common
+		}
+		versionSchemas[name] = s
+	}
+
+	schemas := make(map[schema.GroupVersionKind]*spec.Schema)
+	for version, m := range versionSchemas {
+		if len(m) == 0 {
+			continue
+		}
+
+		b, err := json.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+
+		var s spec.Schema
+		if err := json.Unmarshal(b, &s); err != nil {
+			return nil, errors.Wrapf(err, "parsing schema for version %s", version)
+		}
+
+		gvk := schema.GroupVersionKind{Group: group, Version: version, Kind: kind}
+		schemas[gvk] = &s
+	}
+
+	return schemas, nil
+}
+
+// readObjects reads the objects in a YAML or JSON file. YAML files may contain
+// multiple documents.
+func readObjects(fs afero.Fs, path string) ([]*unstructured.Unstructured, error) {
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []*unstructured.Unstructured
+
+	r := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(b)))
+	for {
+		doc, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		data, err := yaml.ToJSON(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(data)) == 0 || string(bytes.TrimSpace(data)) == "null" {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(data); err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
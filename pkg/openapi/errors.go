@@ -0,0 +1,82 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	oerrors "github.com/go-openapi/errors"
+)
+
+// SchemaError is a violation of a schema by a field of an object.
+type SchemaError struct {
+	// Path is the JSON path of the field, e.g. $.spec.containers[0].image.
+	Path string
+	// Message describes the violation.
+	Message string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// schemaErrors flattens the errors returned by schema validation into an error
+// for each violation. Violations of fields are converted to SchemaErrors.
+func schemaErrors(err error) []error {
+	switch t := err.(type) {
+	case nil:
+		return nil
+	case *oerrors.CompositeError:
+		var errs []error
+		for _, e := range t.Errors {
+			errs = append(errs, schemaErrors(e)...)
+		}
+		return errs
+	case *oerrors.Validation:
+		msg := t.Error()
+		for _, prefix := range []string{t.Name + " in " + t.In + " ", t.Name + " "} {
+			if t.Name != "" && strings.HasPrefix(msg, prefix) {
+				msg = strings.TrimPrefix(msg, prefix)
+				break
+			}
+		}
+
+		return []error{&SchemaError{Path: jsonPath(t.Name), Message: msg}}
+	default:
+		return []error{err}
+	}
+}
+
+// jsonPath converts a dotted field name to a JSON path.
+func jsonPath(name string) string {
+	path := "$"
+	for _, part := range strings.Split(name, ".") {
+		if part == "" {
+			continue
+		}
+
+		if _, err := strconv.Atoi(part); err == nil {
+			path += "[" + part + "]"
+			continue
+		}
+
+		path += "." + part
+	}
+
+	return path
+}
@@ -0,0 +1,68 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package openapi
+
+import (
+	"testing"
+
+	oerrors "github.com/go-openapi/errors"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_schemaErrors(t *testing.T) {
+	err := oerrors.CompositeValidationError(
+		oerrors.InvalidType("spec.containers.0.image", "body", "string", nil),
+		oerrors.CompositeValidationError(
+			oerrors.Required("spec.selector", "body"),
+		),
+		errors.New("other"),
+	)
+
+	expected := []string{
+		"$.spec.containers[0].image: must be of type string",
+		"$.spec.selector: is required",
+		"other",
+	}
+
+	var got []string
+	for _, e := range schemaErrors(err) {
+		got = append(got, e.Error())
+	}
+
+	require.Equal(t, expected, got)
+}
+
+func Test_schemaErrors_nil(t *testing.T) {
+	require.Empty(t, schemaErrors(nil))
+}
+
+func Test_jsonPath(t *testing.T) {
+	cases := []struct {
+		name     string
+		expected string
+	}{
+		{name: "", expected: "$"},
+		{name: "spec", expected: "$.spec"},
+		{name: "spec.ports.1.port", expected: "$.spec.ports[1].port"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, jsonPath(tc.name))
+		})
+	}
+}
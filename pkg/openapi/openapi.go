@@ -17,16 +17,12 @@ package openapi
 
 import (
 	"fmt"
-	"path/filepath"
 	"strings"
 
 	"github.com/go-openapi/spec"
 	"github.com/go-openapi/strfmt"
-	"github.com/go-openapi/validate"
-	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubespec"
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/pkg/errors"
-	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -36,8 +32,12 @@ var (
 
 // ValidateAgainstSchema validates a document against the schema.
 func ValidateAgainstSchema(a app.App, obj *unstructured.Unstructured, envName string) []error {
-	v := newValidateAgainstSchema()
-	return v.run(a, obj, envName)
+	v, err := NewValidator(a, envName, "")
+	if err != nil {
+		return []error{err}
+	}
+
+	return v.Validate(obj)
 }
 
 type validateAgainstSchema struct {
@@ -46,14 +46,6 @@ type validateAgainstSchema struct {
 	validate       func(*spec.Schema, interface{}, strfmt.Registry) error
 }
 
-func newValidateAgainstSchema() *validateAgainstSchema {
-	return &validateAgainstSchema{
-		definitionName: definitionName,
-		loadSchema:     loadSchema,
-		validate:       validate.AgainstSchema,
-	}
-}
-
 func (v *validateAgainstSchema) run(a app.App, obj *unstructured.Unstructured, envName string) []error {
 	name, err := v.definitionName(obj)
 	if err != nil {
@@ -96,11 +88,6 @@ func definitionName(obj *unstructured.Unstructured) (string, error) {
 		name = fmt.Sprintf("io.k8s.api.core.%s.%s", parts[0], kind)
 	case 2:
 		if strings.Contains(parts[0], ".") {
-			logrus.WithFields(logrus.Fields{
-				"kind":       kind,
-				"apiVersion": parts[0],
-			}).Warn("ksonnet currently does not support CRDs")
-
 			return "", errUnsupportedDefinition
 		}
 		name = fmt.Sprintf("io.k8s.api.%s.%s.%s", parts[0], parts[1], kind)
@@ -108,30 +95,3 @@ func definitionName(obj *unstructured.Unstructured) (string, error) {
 
 	return name, nil
 }
-
-func loadSchema(a app.App, name, envName string) (*spec.Schema, error) {
-	libPath, err := a.LibPath(envName)
-	if err != nil {
-		return nil, err
-	}
-
-	schemaPath := filepath.Join(libPath, "swagger.json")
-	apiSpec, _, err := kubespec.Import(schemaPath)
-	if err != nil {
-		return nil, err
-	}
-
-	schema, ok := apiSpec.Definitions[name]
-	if !ok {
-		return nil, errors.Errorf("unable to find definition for %s", name)
-	}
-
-	options := &spec.ExpandOptions{
-		RelativeBase: schemaPath,
-	}
-	if err := spec.ExpandSchemaWithBasePath(&schema, nil, options); err != nil {
-		return nil, err
-	}
-
-	return &schema, nil
-}
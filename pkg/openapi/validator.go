@@ -0,0 +1,207 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubespec"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// swaggerFilename is the name of the swagger spec in an environment's lib directory.
+	swaggerFilename = "swagger.json"
+)
+
+// Validator validates objects against OpenAPI schemas without contacting a
+// cluster. Built-in kinds are validated with the swagger spec recorded in the
+// app's lib directory for the environment's Kubernetes version. Custom resources
+// are validated with the schemas of CustomResourceDefinitions.
+type Validator struct {
+	app     app.App
+	envName string
+
+	// swagger is the swagger spec for the environment's Kubernetes version.
+	swagger *spec.Swagger
+
+	// expanded are the definitions from the swagger spec with their references
+	// expanded, by definition name.
+	expanded map[string]*spec.Schema
+
+	// custom are the schemas for custom resources.
+	custom map[schema.GroupVersionKind]*spec.Schema
+
+	// crds are the kinds defined by CustomResourceDefinitions, including CRDs
+	// without a schema.
+	crds map[schema.GroupVersionKind]bool
+
+	v *validateAgainstSchema
+}
+
+// NewValidator creates an instance of Validator for an environment. If schemaDir
+// is not empty, the CustomResourceDefinitions in its YAML and JSON files are
+// loaded to validate custom resources.
+func NewValidator(a app.App, envName, schemaDir string) (*Validator, error) {
+	libPath, err := a.LibPath(envName)
+	if err != nil {
+		return nil, err
+	}
+
+	swaggerPath := filepath.Join(libPath, swaggerFilename)
+	b, err := afero.ReadFile(a.Fs(), swaggerPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading swagger spec for environment %q", envName)
+	}
+
+	swagger, err := kubespec.CreateAPISpec(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", swaggerPath)
+	}
+
+	val := &Validator{
+		app:      a,
+		envName:  envName,
+		swagger:  swagger,
+		expanded: make(map[string]*spec.Schema),
+		custom:   make(map[schema.GroupVersionKind]*spec.Schema),
+		crds:     make(map[schema.GroupVersionKind]bool),
+	}
+
+	val.v = &validateAgainstSchema{
+		definitionName: definitionName,
+		loadSchema:     val.loadSchema,
+		validate:       validate.AgainstSchema,
+	}
+
+	if schemaDir != "" {
+		if err := val.loadSchemaDir(schemaDir); err != nil {
+			return nil, err
+		}
+	}
+
+	return val, nil
+}
+
+// Validate validates an object against the schema for its kind. Violations of
+// the schema are returned as SchemaErrors. Custom resources without a schema are
+// not validated.
+func (val *Validator) Validate(obj *unstructured.Unstructured) []error {
+	if s, ok := val.custom[obj.GroupVersionKind()]; ok {
+		return schemaErrors(val.v.validate(s, obj.Object, strfmt.Default))
+	}
+
+	var errs []error
+	for _, err := range val.v.run(val.app, obj, val.envName) {
+		errs = append(errs, schemaErrors(err)...)
+	}
+
+	return errs
+}
+
+// AddCRD adds the schemas of a CustomResourceDefinition.
+func (val *Validator) AddCRD(crd *unstructured.Unstructured) error {
+	kinds, err := crdKinds(crd)
+	if err != nil {
+		return errors.Wrapf(err, "loading CustomResourceDefinition %s", crd.GetName())
+	}
+
+	for _, gvk := range kinds {
+		val.crds[gvk] = true
+	}
+
+	schemas, err := crdSchemas(crd)
+	if err != nil {
+		return errors.Wrapf(err, "loading schema from CustomResourceDefinition %s", crd.GetName())
+	}
+
+	for gvk, s := range schemas {
+		val.custom[gvk] = s
+	}
+
+	return nil
+}
+
+// HasCRD returns true if a CustomResourceDefinition for a kind has been added.
+func (val *Validator) HasCRD(gvk schema.GroupVersionKind) bool {
+	return val.crds[gvk]
+}
+
+// loadSchemaDir adds the schemas of the CustomResourceDefinitions in a directory.
+func (val *Validator) loadSchemaDir(dir string) error {
+	fs := val.app.Fs()
+
+	return afero.Walk(fs, dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if fi.IsDir() {
+			return nil
+		}
+
+		switch filepath.Ext(path) {
+		case ".json", ".yaml", ".yml":
+		default:
+			return nil
+		}
+
+		objects, err := readObjects(fs, path)
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", path)
+		}
+
+		for _, obj := range objects {
+			if !IsCRD(obj) {
+				continue
+			}
+
+			if err := val.AddCRD(obj); err != nil {
+				return errors.Wrapf(err, "reading %s", path)
+			}
+		}
+
+		return nil
+	})
+}
+
+// loadSchema loads a definition from the swagger spec. It satisfies the loadSchema
+// field of validateAgainstSchema.
+func (val *Validator) loadSchema(_ app.App, name, _ string) (*spec.Schema, error) {
+	if s, ok := val.expanded[name]; ok {
+		return s, nil
+	}
+
+	s, ok := val.swagger.Definitions[name]
+	if !ok {
+		return nil, errors.Errorf("unable to find definition for %s", name)
+	}
+
+	if err := spec.ExpandSchema(&s, val.swagger, nil); err != nil {
+		return nil, errors.Wrapf(err, "expanding definition for %s", name)
+	}
+
+	val.expanded[name] = &s
+	return &s, nil
+}
@@ -0,0 +1,253 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	testSwagger = `{
+  "swagger": "2.0",
+  "info": {"title": "Kubernetes", "version": "v1.8.7"},
+  "paths": {},
+  "definitions": {
+    "io.k8s.api.core.v1.Service": {
+      "type": "object",
+      "properties": {
+        "apiVersion": {"type": "string"},
+        "kind": {"type": "string"},
+        "spec": {"$ref": "#/definitions/io.k8s.api.core.v1.ServiceSpec"}
+      }
+    },
+    "io.k8s.api.core.v1.ServiceSpec": {
+      "type": "object",
+      "properties": {
+        "clusterIP": {"type": "string"}
+      }
+    }
+  }
+}`
+
+	testCRD = `---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.stable.example.com
+spec:
+  group: stable.example.com
+  version: v1
+  names:
+    kind: CronTab
+    plural: crontabs
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          properties:
+            replicas:
+              type: integer
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored
+`
+)
+
+func genObject(apiVersion, kind string, spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name": "example",
+			},
+			"spec": spec,
+		},
+	}
+}
+
+func TestValidator(t *testing.T) {
+	cases := []struct {
+		name  string
+		obj   *unstructured.Unstructured
+		isErr bool
+	}{
+		{
+			name: "valid built-in object",
+			obj:  genObject("v1", "Service", map[string]interface{}{"clusterIP": "None"}),
+		},
+		{
+			name:  "invalid built-in object",
+			obj:   genObject("v1", "Service", map[string]interface{}{"clusterIP": int64(1)}),
+			isErr: true,
+		},
+		{
+			name:  "built-in object without a definition",
+			obj:   genObject("v1", "Pod", nil),
+			isErr: true,
+		},
+		{
+			name: "valid custom resource",
+			obj:  genObject("stable.example.com/v1", "CronTab", map[string]interface{}{"replicas": int64(1)}),
+		},
+		{
+			name:  "invalid custom resource",
+			obj:   genObject("stable.example.com/v1", "CronTab", map[string]interface{}{"replicas": "one"}),
+			isErr: true,
+		},
+		{
+			name: "custom resource without a schema",
+			obj:  genObject("other.example.com/v1", "Widget", map[string]interface{}{"replicas": "one"}),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/app/lib/v1.8.7/swagger.json", []byte(testSwagger), 0644))
+				require.NoError(t, afero.WriteFile(fs, "/schemas/crontab.yaml", []byte(testCRD), 0644))
+				require.NoError(t, afero.WriteFile(fs, "/schemas/README.md", []byte("not a schema"), 0644))
+
+				v, err := NewValidator(a, "default", "/schemas")
+				require.NoError(t, err)
+
+				errs := v.Validate(tc.obj)
+				if tc.isErr {
+					require.NotEmpty(t, errs)
+					return
+				}
+				require.Empty(t, errs)
+			})
+		})
+	}
+}
+
+func TestValidator_schema_error_paths(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		require.NoError(t, afero.WriteFile(fs, "/app/lib/v1.8.7/swagger.json", []byte(testSwagger), 0644))
+
+		v, err := NewValidator(a, "default", "")
+		require.NoError(t, err)
+
+		errs := v.Validate(genObject("v1", "Service", map[string]interface{}{"clusterIP": int64(1)}))
+		require.Len(t, errs, 1)
+
+		schemaErr, ok := errs[0].(*SchemaError)
+		require.True(t, ok, "unexpected error type %T", errs[0])
+		require.Equal(t, "$.spec.clusterIP", schemaErr.Path)
+	})
+}
+
+func TestValidator_AddCRD(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		require.NoError(t, afero.WriteFile(fs, "/app/lib/v1.8.7/swagger.json", []byte(testSwagger), 0644))
+
+		v, err := NewValidator(a, "default", "")
+		require.NoError(t, err)
+
+		require.NoError(t, afero.WriteFile(fs, "/crd.yaml", []byte(testCRD), 0644))
+		objects, err := readObjects(fs, "/crd.yaml")
+		require.NoError(t, err)
+
+		gvk := schema.GroupVersionKind{Group: "stable.example.com", Version: "v1", Kind: "CronTab"}
+		require.False(t, v.HasCRD(gvk))
+
+		require.NoError(t, v.AddCRD(objects[0]))
+		require.True(t, v.HasCRD(gvk))
+
+		obj := genObject("stable.example.com/v1", "CronTab", map[string]interface{}{"replicas": "one"})
+		errs := v.Validate(obj)
+		require.Len(t, errs, 1)
+		require.Equal(t, "$.spec.replicas", errs[0].(*SchemaError).Path)
+
+		require.Error(t, v.AddCRD(genObject("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", nil)))
+	})
+}
+
+func TestIsCustomResource(t *testing.T) {
+	cases := []struct {
+		apiVersion string
+		expected   bool
+	}{
+		{apiVersion: "v1"},
+		{apiVersion: "apps/v1beta2"},
+		{apiVersion: "rbac.authorization.k8s.io/v1"},
+		{apiVersion: "stable.example.com/v1", expected: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.apiVersion, func(t *testing.T) {
+			obj := genObject(tc.apiVersion, "Kind", nil)
+			require.Equal(t, tc.expected, IsCustomResource(obj))
+		})
+	}
+}
+
+func TestNewValidator_missing_swagger(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		_, err := NewValidator(a, "default", "")
+		require.Error(t, err)
+	})
+}
+
+func Test_crdSchemas_versions(t *testing.T) {
+	crd := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1beta1",
+			"kind":       "CustomResourceDefinition",
+			"spec": map[string]interface{}{
+				"group": "stable.example.com",
+				"names": map[string]interface{}{"kind": "CronTab"},
+				"validation": map[string]interface{}{
+					"openAPIV3Schema": map[string]interface{}{"type": "object"},
+				},
+				"versions": []interface{}{
+					map[string]interface{}{"name": "v1"},
+					map[string]interface{}{
+						"name": "v2",
+						"schema": map[string]interface{}{
+							"openAPIV3Schema": map[string]interface{}{"required": []interface{}{"spec"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	schemas, err := crdSchemas(crd)
+	require.NoError(t, err)
+	require.Len(t, schemas, 2)
+
+	for gvk, s := range schemas {
+		switch gvk.Version {
+		case "v1":
+			require.True(t, s.Type.Contains("object"))
+		case "v2":
+			require.Equal(t, []string{"spec"}, s.Required)
+		default:
+			t.Fatalf("unexpected version %s", gvk.Version)
+		}
+	}
+}
@@ -75,3 +75,21 @@ func TestEnvParamSet(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvParamSet_SetValues(t *testing.T) {
+	snippet := test.ReadTestData(t, filepath.Join("env", "no-globals", "set", "in.libsonnet"))
+
+	values := map[string]interface{}{
+		"containerPort": "8080",
+		"replicas":      float64(2),
+		"labels":        map[string]interface{}{"tier": "frontend"},
+	}
+
+	epa := NewEnvParamSet()
+
+	got, err := epa.SetValues("guestbook", snippet, values)
+	require.NoError(t, err)
+
+	expected := test.ReadTestData(t, filepath.Join("env", "no-globals", "set", "out-values.libsonnet"))
+	require.Equal(t, expected, got)
+}
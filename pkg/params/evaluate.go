@@ -16,9 +16,14 @@
 package params
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"path/filepath"
+	"sort"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
 	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
@@ -26,11 +31,21 @@ import (
 
 // EvaluateEnv evaluates environment parameters.
 func EvaluateEnv(a app.App, sourcePath, paramsStr, envName, moduleName string) (string, error) {
-	snippet, err := afero.ReadFile(a.Fs(), sourcePath)
+	b, err := afero.ReadFile(a.Fs(), sourcePath)
 	if err != nil {
 		return "", err
 	}
 
+	snippet, err := ApplyDestinationParams(a, envName, string(b))
+	if err != nil {
+		return "", errors.Wrap(err, "applying destination parameters")
+	}
+
+	snippet, err = applySecretParams(a, envName, snippet)
+	if err != nil {
+		return "", errors.Wrap(err, "applying secret parameters")
+	}
+
 	paramsStr, err = modularizeParameters(a, envName, moduleName, paramsStr)
 	if err != nil {
 		return "", errors.Wrap(err, "modularizing parameters")
@@ -38,7 +53,7 @@ func EvaluateEnv(a app.App, sourcePath, paramsStr, envName, moduleName string) (
 
 	envDir := filepath.Dir(sourcePath)
 
-	moduleParams, err := BuildEnvParamsForModule(moduleName, string(snippet), paramsStr, envDir)
+	moduleParams, err := BuildEnvParamsForModule(moduleName, snippet, paramsStr, envDir)
 	if err != nil {
 		return "", errors.Wrapf(err, "selecting params for module %q in environment %q", moduleName, envName)
 	}
@@ -51,6 +66,66 @@ func EvaluateEnv(a app.App, sourcePath, paramsStr, envName, moduleName string) (
 	return envParams, nil
 }
 
+// ApplyDestinationParams layers the parameter overrides of an environment's
+// destination over the environment's parameters. Overrides only apply when
+// envName selects a destination, e.g. `prod@us-east`.
+func ApplyDestinationParams(a app.App, envName, snippet string) (string, error) {
+	env, err := app.ResolveEnvironment(a, envName)
+	if err != nil {
+		return "", err
+	}
+
+	if env.Destination == nil {
+		return snippet, nil
+	}
+
+	return overlayParams(snippet, env.Destination.Params)
+}
+
+// applySecretParams layers the decrypted secret parameters of an environment
+// over the environment's parameters.
+func applySecretParams(a app.App, envName, snippet string) (string, error) {
+	values, err := secrets.Decrypt(a, envName)
+	if err != nil {
+		return "", err
+	}
+
+	return overlayParams(snippet, values)
+}
+
+// overlayParams merges component parameters into an environment parameters
+// snippet. overrides is keyed by component name.
+func overlayParams(snippet string, overrides map[string]map[string]interface{}) (string, error) {
+	if len(overrides) == 0 {
+		return snippet, nil
+	}
+
+	var names []string
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "(\n%s\n) + {\n  components+: {\n", snippet)
+	for _, name := range names {
+		key, err := json.Marshal(name)
+		if err != nil {
+			return "", err
+		}
+
+		value, err := json.Marshal(overrides[name])
+		if err != nil {
+			return "", errors.Wrapf(err, "encoding parameters for component %q", name)
+		}
+
+		fmt.Fprintf(&buf, "    %s+: %s,\n", key, value)
+	}
+	buf.WriteString("  },\n}\n")
+
+	return buf.String(), nil
+}
+
 // modularizeParameters adds a module prefix to component parameters.
 // * Given a root module, it will not update the component name
 // * Given a module nested under root, it will prepend the module: eg: `module apps -> apps.component`
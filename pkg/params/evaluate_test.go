@@ -16,11 +16,13 @@
 package params
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
 	"github.com/ksonnet/ksonnet/pkg/util/test"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
@@ -52,3 +54,89 @@ func TestEvaluateEnv(t *testing.T) {
 		assert.Equal(t, expected, got)
 	})
 }
+
+func TestEvaluateEnv_destination_params(t *testing.T) {
+	cases := []struct {
+		name     string
+		envName  string
+		expected string
+	}{
+		{
+			name:     "selected destination",
+			envName:  "default@us-east",
+			expected: "expected_destination.libsonnet",
+		},
+		{
+			name:     "no destination selected",
+			envName:  "default",
+			expected: "expected.libsonnet",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+				envConfig := &app.EnvironmentConfig{
+					Name: "default",
+					Destinations: []*app.EnvironmentDestinationSpec{
+						{
+							Name:      "us-east",
+							Namespace: "default",
+							Server:    "http://example.com",
+							Params: map[string]map[string]interface{}{
+								"app.project-1.ds": {"replicas": 5},
+							},
+						},
+					},
+				}
+				a.On("Environment", "default").Return(envConfig, nil)
+
+				sourcePath := "/app/environments/default/params.libsonnet"
+				paramsStr := test.ReadTestData(t, filepath.Join("evaluate_env", "component_params.libsonnet"))
+				moduleName := "app.project-1"
+
+				test.StageFile(t, fs, filepath.Join("evaluate_env", "env_params.libsonnet"), sourcePath)
+
+				got, err := EvaluateEnv(a, sourcePath, paramsStr, tc.envName, moduleName)
+				require.NoError(t, err)
+
+				expected := test.ReadTestData(t, filepath.Join("evaluate_env", tc.expected))
+
+				assert.Equal(t, expected, got)
+			})
+		})
+	}
+}
+
+func TestEvaluateEnv_secret_params(t *testing.T) {
+	old := os.Getenv(secrets.KeyFileEnvVar)
+	require.NoError(t, os.Setenv(secrets.KeyFileEnvVar, "/keys/secret.key"))
+	defer os.Setenv(secrets.KeyFileEnvVar, old)
+
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		envConfig := &app.EnvironmentConfig{
+			Destination: &app.EnvironmentDestinationSpec{
+				Namespace: "default",
+				Server:    "http://example.com",
+			},
+		}
+		a.On("Environment", "default").Return(envConfig, nil)
+
+		sourcePath := "/app/environments/default/params.libsonnet"
+		paramsStr := test.ReadTestData(t, filepath.Join("evaluate_env", "component_params.libsonnet"))
+		envName := "default"
+		moduleName := "app.project-1"
+
+		test.StageFile(t, fs, filepath.Join("evaluate_env", "env_params.libsonnet"), sourcePath)
+
+		err := secrets.Set(a, envName, "app.project-1.ds", "password", "s3cret")
+		require.NoError(t, err)
+
+		got, err := EvaluateEnv(a, sourcePath, paramsStr, envName, moduleName)
+		require.NoError(t, err)
+
+		expected := test.ReadTestData(t, filepath.Join("evaluate_env", "expected_secrets.libsonnet"))
+
+		assert.Equal(t, expected, got)
+	})
+}
@@ -0,0 +1,491 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package params
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/go-jsonnet/ast"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
+	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// Layers which define params, from lowest to highest precedence.
+const (
+	// LayerModule is a component param in a module's params.libsonnet.
+	LayerModule = "module"
+	// LayerModuleGlobal is a global param in a module's params.libsonnet.
+	LayerModuleGlobal = "module global"
+	// LayerEnv is a component param in an environment's params.libsonnet.
+	LayerEnv = "environment"
+	// LayerEnvGlobal is a param in an environment's globals.libsonnet.
+	LayerEnvGlobal = "environment global"
+	// LayerDestination is a param set by an environment's destination in app.yaml.
+	LayerDestination = "destination"
+	// LayerSecret is an encrypted param in an environment's secrets.json.
+	LayerSecret = "secret"
+)
+
+// appConfigFileName is the file which configures destinations.
+const appConfigFileName = "app.yaml"
+
+// Definition is where a layer defines a param.
+type Definition struct {
+	// Layer is the layer which defines the param.
+	Layer string
+	// Path is the path of the file which defines the param, relative to the
+	// app root.
+	Path string
+	// Line is the line where the param is defined. It is 0 if the file is
+	// not Jsonnet.
+	Line int
+	// Value is the source of the value in this layer.
+	Value string
+}
+
+// Source returns the path and line of the definition.
+func (d *Definition) Source() string {
+	if d.Line == 0 {
+		return d.Path
+	}
+
+	return fmt.Sprintf("%s:%d", d.Path, d.Line)
+}
+
+// Provenance lists the layers which define a component param, from lowest to
+// highest precedence.
+type Provenance struct {
+	// ComponentName is the component that owns the param.
+	ComponentName string
+	// ParamName is the name of the param.
+	ParamName string
+	// Definitions are the definitions of the param.
+	Definitions []Definition
+}
+
+// Winner returns the definition which sets the param's value. It is nil if no
+// layer defines the param.
+func (p *Provenance) Winner() *Definition {
+	if len(p.Definitions) == 0 {
+		return nil
+	}
+
+	return &p.Definitions[len(p.Definitions)-1]
+}
+
+// ExplainConfig is configuration for Explain.
+type ExplainConfig struct {
+	App app.App
+	// EnvName is the environment.
+	EnvName string
+	// ModuleName is the module whose params are explained.
+	ModuleName string
+	// ModuleParamsPath is the path of the module's params.libsonnet.
+	ModuleParamsPath string
+}
+
+// Explain finds the layers which define the params of a module's components in
+// an environment. Layers are found by reading the Jsonnet source of the
+// params files, so params set by computed fields or imports are not found.
+func Explain(config ExplainConfig) ([]Provenance, error) {
+	e := &explainer{
+		ExplainConfig: config,
+		provenances:   make(map[string]map[string]*Provenance),
+	}
+
+	if err := e.moduleLayers(); err != nil {
+		return nil, err
+	}
+
+	if err := e.envLayers(); err != nil {
+		return nil, err
+	}
+
+	if err := e.destinationLayer(); err != nil {
+		return nil, err
+	}
+
+	if err := e.secretLayer(); err != nil {
+		return nil, err
+	}
+
+	var result []Provenance
+	for _, params := range e.provenances {
+		for _, p := range params {
+			result = append(result, *p)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ComponentName != result[j].ComponentName {
+			return result[i].ComponentName < result[j].ComponentName
+		}
+		return result[i].ParamName < result[j].ParamName
+	})
+
+	return result, nil
+}
+
+type explainer struct {
+	ExplainConfig
+
+	// components are the components in the module.
+	components  []string
+	provenances map[string]map[string]*Provenance
+}
+
+func (e *explainer) add(componentName, paramName string, d Definition) {
+	if _, ok := e.provenances[componentName]; !ok {
+		e.provenances[componentName] = make(map[string]*Provenance)
+	}
+
+	p, ok := e.provenances[componentName][paramName]
+	if !ok {
+		p = &Provenance{ComponentName: componentName, ParamName: paramName}
+		e.provenances[componentName][paramName] = p
+	}
+
+	p.Definitions = append(p.Definitions, d)
+}
+
+// addGlobal adds a definition to a param of every component in the module.
+func (e *explainer) addGlobal(paramName string, d Definition) {
+	for _, componentName := range e.components {
+		e.add(componentName, paramName, d)
+	}
+}
+
+// componentName returns the name of a component in the module given the name
+// used in environment params, or false if the component is in another module.
+func (e *explainer) componentName(envKey string) (string, bool) {
+	if e.ModuleName == "/" || e.ModuleName == "" {
+		return envKey, !strings.Contains(envKey, ".")
+	}
+
+	prefix := e.ModuleName + "."
+	if !strings.HasPrefix(envKey, prefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(envKey, prefix), true
+}
+
+func (e *explainer) relPath(path string) string {
+	rel, err := filepath.Rel(e.App.Root(), path)
+	if err != nil {
+		return path
+	}
+
+	return rel
+}
+
+func (e *explainer) moduleLayers() error {
+	obj, err := e.parseObject(e.ModuleParamsPath)
+	if err != nil {
+		return err
+	}
+
+	path := e.relPath(e.ModuleParamsPath)
+
+	// Module globals are merged over component params, so they are added after
+	// all of the component params.
+	var globals []ast.Node
+
+	for _, field := range obj.Fields {
+		id, err := jsonnet.FieldID(field)
+		if err != nil {
+			continue
+		}
+
+		switch id {
+		case "components":
+			components, ok := field.Expr2.(*astext.Object)
+			if !ok {
+				continue
+			}
+
+			err = eachParam(components, func(componentName, paramName string, value ast.Node) error {
+				d, err := definition(LayerModule, path, value)
+				if err != nil {
+					return err
+				}
+
+				e.add(componentName, paramName, d)
+				return nil
+			}, func(componentName string) {
+				e.components = append(e.components, componentName)
+			})
+			if err != nil {
+				return err
+			}
+		case "global":
+			globals = append(globals, field.Expr2)
+		}
+	}
+
+	for _, node := range globals {
+		global, ok := node.(*astext.Object)
+		if !ok {
+			continue
+		}
+
+		err = eachField(global, func(paramName string, value ast.Node) error {
+			d, err := definition(LayerModuleGlobal, path, value)
+			if err != nil {
+				return err
+			}
+
+			e.addGlobal(paramName, d)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *explainer) envDir() string {
+	envName, _ := app.SplitDestination(e.EnvName)
+	return filepath.Join(e.App.Root(), app.EnvironmentDirName, envName)
+}
+
+func (e *explainer) envLayers() error {
+	paramsPath := filepath.Join(e.envDir(), "params.libsonnet")
+	b, err := afero.ReadFile(e.App.Fs(), paramsPath)
+	if err != nil {
+		return err
+	}
+
+	node, err := jsonnet.ParseNode(paramsPath, string(b))
+	if err != nil {
+		return errors.Wrapf(err, "parsing %s", paramsPath)
+	}
+
+	path := e.relPath(paramsPath)
+
+	var walkErr error
+	jsonnet.Walk(node, func(n ast.Node) {
+		obj, ok := n.(*astext.Object)
+		if !ok || walkErr != nil {
+			return
+		}
+
+		for _, field := range obj.Fields {
+			if id, err := jsonnet.FieldID(field); err != nil || id != "components" {
+				continue
+			}
+
+			components, ok := field.Expr2.(*astext.Object)
+			if !ok {
+				continue
+			}
+
+			walkErr = eachParam(components, func(envKey, paramName string, value ast.Node) error {
+				componentName, ok := e.componentName(envKey)
+				if !ok {
+					return nil
+				}
+
+				d, err := definition(LayerEnv, path, value)
+				if err != nil {
+					return err
+				}
+
+				e.add(componentName, paramName, d)
+				return nil
+			}, nil)
+		}
+	})
+	if walkErr != nil {
+		return walkErr
+	}
+
+	// Environment globals are only merged if params.libsonnet imports them.
+	if !strings.Contains(string(b), `import "globals.libsonnet"`) {
+		return nil
+	}
+
+	globalsPath := filepath.Join(e.envDir(), "globals.libsonnet")
+	exists, err := afero.Exists(e.App.Fs(), globalsPath)
+	if err != nil || !exists {
+		return err
+	}
+
+	globals, err := e.parseObject(globalsPath)
+	if err != nil {
+		return err
+	}
+
+	path = e.relPath(globalsPath)
+	return eachField(globals, func(paramName string, value ast.Node) error {
+		d, err := definition(LayerEnvGlobal, path, value)
+		if err != nil {
+			return err
+		}
+
+		e.addGlobal(paramName, d)
+		return nil
+	})
+}
+
+func (e *explainer) destinationLayer() error {
+	env, err := app.ResolveEnvironment(e.App, e.EnvName)
+	if err != nil {
+		return err
+	}
+
+	if env.Destination == nil {
+		return nil
+	}
+
+	for envKey, params := range env.Destination.Params {
+		componentName, ok := e.componentName(envKey)
+		if !ok {
+			continue
+		}
+
+		for paramName, value := range params {
+			b, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+
+			d := Definition{
+				Layer: LayerDestination,
+				Path:  appConfigFileName,
+				Value: string(b),
+			}
+			e.add(componentName, paramName, d)
+		}
+	}
+
+	return nil
+}
+
+func (e *explainer) secretLayer() error {
+	s, err := secrets.Load(e.App, e.EnvName)
+	if err != nil {
+		return err
+	}
+
+	path := e.relPath(secrets.Path(e.App, e.EnvName))
+	for _, entry := range s.Entries() {
+		componentName, ok := e.componentName(entry.ComponentName)
+		if !ok {
+			continue
+		}
+
+		d := Definition{
+			Layer: LayerSecret,
+			Path:  path,
+			Value: secrets.Mask,
+		}
+		e.add(componentName, entry.ParamName, d)
+	}
+
+	return nil
+}
+
+func (e *explainer) parseObject(path string) (*astext.Object, error) {
+	b, err := afero.ReadFile(e.App.Fs(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := jsonnet.Parse(path, string(b))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", path)
+	}
+
+	return obj, nil
+}
+
+// eachParam calls fn for each param of each component in a components object.
+// If componentFn is not nil, it is called for each component.
+func eachParam(components *astext.Object, fn func(componentName, paramName string, value ast.Node) error, componentFn func(componentName string)) error {
+	for _, field := range components.Fields {
+		componentName, err := jsonnet.FieldID(field)
+		if err != nil {
+			continue
+		}
+
+		if componentFn != nil {
+			componentFn(componentName)
+		}
+
+		params, ok := field.Expr2.(*astext.Object)
+		if !ok {
+			continue
+		}
+
+		err = eachField(params, func(paramName string, value ast.Node) error {
+			return fn(componentName, paramName, value)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// eachField calls fn for each field of an object with a static name.
+func eachField(obj *astext.Object, fn func(name string, value ast.Node) error) error {
+	for _, field := range obj.Fields {
+		name, err := jsonnet.FieldID(field)
+		if err != nil {
+			continue
+		}
+
+		if err = fn(name, field.Expr2); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func definition(layer, path string, value ast.Node) (Definition, error) {
+	d := Definition{
+		Layer: layer,
+		Path:  path,
+	}
+
+	if value == nil {
+		return d, nil
+	}
+
+	// The line is read before printing, which can reset it.
+	d.Line = value.Loc().Begin.Line
+
+	s, err := nodeAsString(value)
+	if err != nil {
+		return d, err
+	}
+	d.Value = s
+
+	return d, nil
+}
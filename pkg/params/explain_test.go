@@ -0,0 +1,145 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package params
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/secrets"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	old := os.Getenv(secrets.KeyFileEnvVar)
+	require.NoError(t, os.Setenv(secrets.KeyFileEnvVar, "/keys/secret.key"))
+	defer os.Setenv(secrets.KeyFileEnvVar, old)
+
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		envConfig := &app.EnvironmentConfig{
+			Name: "prod",
+			Destinations: []*app.EnvironmentDestinationSpec{
+				{
+					Name: "us-east",
+					Params: map[string]map[string]interface{}{
+						"guestbook": {"replicas": 5},
+					},
+				},
+			},
+		}
+		a.On("Environment", "prod").Return(envConfig, nil)
+
+		test.StageFile(t, fs, filepath.Join("explain", "module_params.libsonnet"), "/app/components/params.libsonnet")
+		test.StageFile(t, fs, filepath.Join("explain", "env_params.libsonnet"), "/app/environments/prod/params.libsonnet")
+		test.StageFile(t, fs, filepath.Join("explain", "globals.libsonnet"), "/app/environments/prod/globals.libsonnet")
+
+		require.NoError(t, secrets.Set(a, "prod", "guestbook", "password", "s3cret"))
+
+		config := ExplainConfig{
+			App:              a,
+			EnvName:          "prod@us-east",
+			ModuleName:       "/",
+			ModuleParamsPath: "/app/components/params.libsonnet",
+		}
+
+		got, err := Explain(config)
+		require.NoError(t, err)
+
+		moduleParams := "components/params.libsonnet"
+		envParams := "environments/prod/params.libsonnet"
+		envGlobals := "environments/prod/globals.libsonnet"
+
+		expected := []Provenance{
+			{
+				ComponentName: "guestbook",
+				ParamName:     "image",
+				Definitions: []Definition{
+					{Layer: LayerModule, Path: moduleParams, Line: 7, Value: `'gcr.io/heptio-images/ks-guestbook-demo:0.1'`},
+				},
+			},
+			{
+				ComponentName: "guestbook",
+				ParamName:     "name",
+				Definitions: []Definition{
+					{Layer: LayerModule, Path: moduleParams, Line: 8, Value: `'guestbook'`},
+					{Layer: LayerEnv, Path: envParams, Line: 6, Value: `'guestbook-prod'`},
+				},
+			},
+			{
+				ComponentName: "guestbook",
+				ParamName:     "namespace",
+				Definitions: []Definition{
+					{Layer: LayerEnvGlobal, Path: envGlobals, Line: 2, Value: `'prod'`},
+				},
+			},
+			{
+				ComponentName: "guestbook",
+				ParamName:     "password",
+				Definitions: []Definition{
+					{Layer: LayerSecret, Path: "environments/prod/secrets.json", Value: secrets.Mask},
+				},
+			},
+			{
+				ComponentName: "guestbook",
+				ParamName:     "replicas",
+				Definitions: []Definition{
+					{Layer: LayerModule, Path: moduleParams, Line: 9, Value: "1"},
+					{Layer: LayerModuleGlobal, Path: moduleParams, Line: 3, Value: "2"},
+					{Layer: LayerEnv, Path: envParams, Line: 7, Value: "3"},
+					{Layer: LayerDestination, Path: "app.yaml", Value: "5"},
+				},
+			},
+			{
+				ComponentName: "redis",
+				ParamName:     "name",
+				Definitions: []Definition{
+					{Layer: LayerModule, Path: moduleParams, Line: 12, Value: `'redis'`},
+				},
+			},
+			{
+				ComponentName: "redis",
+				ParamName:     "namespace",
+				Definitions: []Definition{
+					{Layer: LayerEnvGlobal, Path: envGlobals, Line: 2, Value: `'prod'`},
+				},
+			},
+			{
+				ComponentName: "redis",
+				ParamName:     "replicas",
+				Definitions: []Definition{
+					{Layer: LayerModuleGlobal, Path: moduleParams, Line: 3, Value: "2"},
+				},
+			},
+		}
+
+		assert.Equal(t, expected, got)
+
+		assert.Equal(t, LayerDestination, got[4].Winner().Layer)
+		assert.Equal(t, "environments/prod/params.libsonnet:7", got[4].Definitions[2].Source())
+		assert.Equal(t, "app.yaml", got[4].Definitions[3].Source())
+	})
+}
+
+func TestProvenance_Winner(t *testing.T) {
+	p := Provenance{}
+	assert.Nil(t, p.Winner())
+}
@@ -25,7 +25,7 @@ import (
 // This object includes the current server and namespace. The object
 // is suitable to use as a Jsonnet ext code option.
 func JsonnetEnvObject(a app.App, envName string) (string, error) {
-	envDetails, err := a.Environment(envName)
+	envDetails, err := app.ResolveEnvironment(a, envName)
 	if err != nil {
 		return "", err
 	}
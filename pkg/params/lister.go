@@ -217,26 +217,31 @@ func objectValueAsString(source, key string) (string, error) {
 			continue
 		}
 
-		switch t := f.Expr2.(type) {
-		case *astext.Object:
-			t.Oneline = true
-		case *ast.Array:
-			// force array to print on a single line by setting its begin line equal to its
-			// end line.
-			loc := t.NodeBase.Loc()
-			loc.Begin.Line = 1
-			loc.End.Line = 1
-		}
+		return nodeAsString(f.Expr2)
+	}
 
-		var buf bytes.Buffer
-		if err = printer.Fprint(&buf, f.Expr2); err != nil {
-			return "", errors.Wrap(err, "converting node to text")
-		}
+	return "", errors.Errorf("object did not contain key %q", key)
+}
 
-		return buf.String(), nil
+// nodeAsString prints a node on a single line.
+func nodeAsString(node ast.Node) (string, error) {
+	switch t := node.(type) {
+	case *astext.Object:
+		t.Oneline = true
+	case *ast.Array:
+		// force array to print on a single line by setting its begin line equal to its
+		// end line.
+		loc := t.NodeBase.Loc()
+		loc.Begin.Line = 1
+		loc.End.Line = 1
 	}
 
-	return "", errors.Errorf("object did not contain key %q", key)
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, node); err != nil {
+		return "", errors.Wrap(err, "converting node to text")
+	}
+
+	return buf.String(), nil
 }
 
 // entryCreator creates Entry from a param object.
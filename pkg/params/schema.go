@@ -0,0 +1,361 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package params
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/prototype"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// SchemaFile is the name of the file in a module's directory which describes
+// the params of the module's components. It is a JSON document, so it can
+// also be imported from Jsonnet.
+const SchemaFile = "params.schema.libsonnet"
+
+// ParamSchema describes the values a component param accepts.
+type ParamSchema struct {
+	Type        prototype.ParamType `json:"type,omitempty"`
+	Required    bool                `json:"required,omitempty"`
+	Enum        []interface{}       `json:"enum,omitempty"`
+	Description string              `json:"description,omitempty"`
+}
+
+// ComponentSchema is the schema of a component's params keyed by param name.
+type ComponentSchema map[string]ParamSchema
+
+// Schemas are the component schemas of a module keyed by component name.
+type Schemas map[string]ComponentSchema
+
+// SchemaFromPrototype creates a component schema from the params of a
+// prototype. Params without a default are required.
+func SchemaFromPrototype(p *prototype.Prototype) ComponentSchema {
+	cs := ComponentSchema{}
+	for _, param := range p.Params {
+		cs[param.Name] = ParamSchema{
+			Type:        param.Type,
+			Required:    param.Default == nil,
+			Description: param.Description,
+		}
+	}
+
+	return cs
+}
+
+// SchemaPath returns the path of the schema file in a module directory.
+func SchemaPath(moduleDir string) string {
+	return filepath.Join(moduleDir, SchemaFile)
+}
+
+// LoadSchemas loads the component schemas in a module directory. A module
+// without a schema file has no schemas.
+func LoadSchemas(fs afero.Fs, moduleDir string) (Schemas, error) {
+	path := SchemaPath(moduleDir)
+
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Schemas{}, nil
+		}
+		return nil, errors.Wrapf(err, "reading param schemas %s", path)
+	}
+
+	s := Schemas{}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, errors.Wrapf(err, "decoding param schemas %s", path)
+	}
+
+	return s, nil
+}
+
+// SaveSchemas writes the component schemas of a module directory. The schema
+// file is removed once it has no schemas.
+func SaveSchemas(fs afero.Fs, moduleDir string, s Schemas) error {
+	path := SchemaPath(moduleDir)
+
+	if len(s) == 0 {
+		exists, err := afero.Exists(fs, path)
+		if err != nil || !exists {
+			return err
+		}
+		return fs.Remove(path)
+	}
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "encoding param schemas")
+	}
+
+	return afero.WriteFile(fs, path, append(b, '\n'), 0644)
+}
+
+// ValidateParam validates a value set at path in a component's params. Only
+// the top level param of the path is described by the schema, so values
+// nested in it are accepted as long as the param is an object.
+func (s Schemas) ValidateParam(componentName string, path []string, value interface{}) error {
+	if len(path) == 0 {
+		return nil
+	}
+
+	ps, ok := s[componentName][path[0]]
+	if !ok {
+		return nil
+	}
+
+	if len(path) > 1 {
+		if ps.Type != "" && ps.Type != prototype.Object {
+			return errors.Errorf("param %q of component %q is not an object, so it has no nested param %q",
+				path[0], componentName, strings.Join(path[1:], "."))
+		}
+		return nil
+	}
+
+	if err := ps.validate(value); err != nil {
+		return errors.Errorf("param %q of component %q %s", path[0], componentName, err)
+	}
+
+	return nil
+}
+
+// Validate validates evaluated component params, keyed by component name,
+// against the schemas. Every violation is reported.
+func (s Schemas) Validate(components map[string]interface{}) error {
+	var problems []string
+
+	for componentName, cs := range s {
+		raw, ok := components[componentName]
+		if !ok {
+			continue
+		}
+
+		values, ok := raw.(map[string]interface{})
+		if !ok {
+			problems = append(problems, fmt.Sprintf("params of component %q are not an object", componentName))
+			continue
+		}
+
+		for paramName, ps := range cs {
+			value := values[paramName]
+			if value == nil && !ps.Required {
+				continue
+			}
+
+			if err := ps.validate(value); err != nil {
+				problems = append(problems, fmt.Sprintf("param %q of component %q %s", paramName, componentName, err))
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+	return errors.Errorf("invalid params:\n  %s", strings.Join(problems, "\n  "))
+}
+
+// ValidateJSON validates the evaluated params of a module in JSON.
+func (s Schemas) ValidateJSON(moduleName, data string) error {
+	if len(s) == 0 {
+		return nil
+	}
+
+	components, err := DecodeModuleComponents(moduleName, data)
+	if err != nil {
+		return err
+	}
+
+	return s.Validate(components)
+}
+
+// DecodeModuleComponents decodes the evaluated params of a module in JSON. The
+// params are an object with component params in its `components` field.
+// Environments name the components of nested modules with a module prefix, so
+// prefixed params are layered over the params of the component they name.
+func DecodeModuleComponents(moduleName, data string) (map[string]interface{}, error) {
+	var evaluated struct {
+		Components map[string]interface{} `json:"components"`
+	}
+	if err := json.Unmarshal([]byte(data), &evaluated); err != nil {
+		return nil, errors.Wrap(err, "decoding evaluated params")
+	}
+
+	return localizeComponents(moduleName, evaluated.Components), nil
+}
+
+// localizeComponents removes the module prefix from component names.
+func localizeComponents(moduleName string, components map[string]interface{}) map[string]interface{} {
+	prefix := moduleName + "."
+
+	localized := make(map[string]interface{})
+	prefixed := make(map[string]interface{})
+	for name, value := range components {
+		if strings.HasPrefix(name, prefix) {
+			prefixed[strings.TrimPrefix(name, prefix)] = value
+			continue
+		}
+		localized[name] = value
+	}
+
+	for name, value := range prefixed {
+		base, ok := localized[name].(map[string]interface{})
+		overrides, isObject := value.(map[string]interface{})
+		if !ok || !isObject {
+			localized[name] = value
+			continue
+		}
+
+		merged := make(map[string]interface{})
+		for k, v := range base {
+			merged[k] = v
+		}
+		for k, v := range overrides {
+			merged[k] = v
+		}
+		localized[name] = merged
+	}
+
+	return localized
+}
+
+// validate returns an error describing why value does not match the schema.
+func (ps ParamSchema) validate(value interface{}) error {
+	if value == nil {
+		if ps.Required {
+			return errors.New("is required")
+		}
+		return nil
+	}
+
+	if ps.Type != "" {
+		want, ok := typeDescriptions[ps.Type]
+		if !ok {
+			return errors.Errorf("has unknown type %q in its schema", string(ps.Type))
+		}
+
+		if !matchesType(ps.Type, value) {
+			return errors.Errorf("must be %s, got %s", want, describeValue(value))
+		}
+	}
+
+	if len(ps.Enum) == 0 {
+		return nil
+	}
+
+	var allowed []string
+	for _, e := range ps.Enum {
+		if enumEqual(e, value) {
+			return nil
+		}
+		allowed = append(allowed, jsonValue(e))
+	}
+
+	return errors.Errorf("must be one of %s, got %s", strings.Join(allowed, ", "), describeValue(value))
+}
+
+// typeDescriptions describe param types in error messages.
+var typeDescriptions = map[prototype.ParamType]string{
+	prototype.Number:         "a number",
+	prototype.String:         "a string",
+	prototype.NumberOrString: "a number or a string",
+	prototype.Object:         "an object",
+	prototype.Array:          "an array",
+}
+
+func matchesType(t prototype.ParamType, value interface{}) bool {
+	switch t {
+	case prototype.Number:
+		return isNumber(value)
+	case prototype.String:
+		_, ok := value.(string)
+		return ok
+	case prototype.NumberOrString:
+		_, ok := value.(string)
+		return ok || isNumber(value)
+	case prototype.Object:
+		_, ok := value.(map[string]interface{})
+		return ok
+	case prototype.Array:
+		_, ok := value.([]interface{})
+		return ok
+	default:
+		return false
+	}
+}
+
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case int, int32, int64, float32, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// enumEqual compares values after a JSON round trip, so numbers decoded as
+// ints match numbers decoded as floats.
+func enumEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(normalizeValue(a), normalizeValue(b))
+}
+
+func normalizeValue(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	var out interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return v
+	}
+
+	return out
+}
+
+func jsonValue(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(b)
+}
+
+// describeValue describes a value with its JSON type for error messages.
+func describeValue(v interface{}) string {
+	switch normalizeValue(v).(type) {
+	case string:
+		return "string " + jsonValue(v)
+	case float64:
+		return "number " + jsonValue(v)
+	case bool:
+		return "boolean " + jsonValue(v)
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	default:
+		return jsonValue(v)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package params
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/prototype"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaFromPrototype(t *testing.T) {
+	replicas := "1"
+	p := &prototype.Prototype{
+		Params: prototype.ParamSchemas{
+			{Name: "name", Type: prototype.String, Description: "Name"},
+			{Name: "replicas", Type: prototype.Number, Default: &replicas, Description: "Replicas"},
+		},
+	}
+
+	expected := ComponentSchema{
+		"name":     {Type: prototype.String, Required: true, Description: "Name"},
+		"replicas": {Type: prototype.Number, Description: "Replicas"},
+	}
+
+	assert.Equal(t, expected, SchemaFromPrototype(p))
+}
+
+func TestSchemas_load_save(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	s, err := LoadSchemas(fs, "/app/components")
+	require.NoError(t, err)
+	assert.Empty(t, s)
+
+	s = Schemas{
+		"deployment": ComponentSchema{
+			"type": {Type: prototype.String, Enum: []interface{}{"ClusterIP", "NodePort"}},
+		},
+	}
+	require.NoError(t, SaveSchemas(fs, "/app/components", s))
+
+	got, err := LoadSchemas(fs, "/app/components")
+	require.NoError(t, err)
+	assert.Equal(t, s, got)
+
+	require.NoError(t, SaveSchemas(fs, "/app/components", Schemas{}))
+
+	exists, err := afero.Exists(fs, SchemaPath("/app/components"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestSchemas_ValidateParam(t *testing.T) {
+	s := Schemas{
+		"deployment": ComponentSchema{
+			"replicas":  {Type: prototype.Number},
+			"port":      {Type: prototype.NumberOrString},
+			"labels":    {Type: prototype.Object},
+			"args":      {Type: prototype.Array},
+			"type":      {Type: prototype.String, Enum: []interface{}{"ClusterIP", "NodePort"}},
+			"weight":    {Enum: []interface{}{1, 2}},
+			"untyped":   {},
+			"malformed": {Type: "boolean"},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		path     []string
+		value    interface{}
+		expected string
+	}{
+		{name: "number", path: []string{"replicas"}, value: 3},
+		{name: "float", path: []string{"replicas"}, value: 1.5},
+		{name: "not a number", path: []string{"replicas"}, value: "3",
+			expected: `param "replicas" of component "deployment" must be a number, got string "3"`},
+		{name: "number or string", path: []string{"port"}, value: "http"},
+		{name: "not a number or string", path: []string{"port"}, value: true,
+			expected: `param "port" of component "deployment" must be a number or a string, got boolean true`},
+		{name: "object", path: []string{"labels"}, value: map[string]interface{}{"app": "web"}},
+		{name: "not an object", path: []string{"labels"}, value: []interface{}{"app"},
+			expected: `param "labels" of component "deployment" must be an object, got an array`},
+		{name: "nested in object", path: []string{"labels", "app"}, value: "web"},
+		{name: "nested in number", path: []string{"replicas", "count"}, value: 3,
+			expected: `param "replicas" of component "deployment" is not an object, so it has no nested param "count"`},
+		{name: "array", path: []string{"args"}, value: []interface{}{"-v"}},
+		{name: "not an array", path: []string{"args"}, value: map[string]interface{}{},
+			expected: `param "args" of component "deployment" must be an array, got an object`},
+		{name: "enum", path: []string{"type"}, value: "NodePort"},
+		{name: "not in enum", path: []string{"type"}, value: "LoadBalancer",
+			expected: `param "type" of component "deployment" must be one of "ClusterIP", "NodePort", got string "LoadBalancer"`},
+		{name: "numeric enum", path: []string{"weight"}, value: 2.0},
+		{name: "untyped", path: []string{"untyped"}, value: true},
+		{name: "unknown type", path: []string{"malformed"}, value: true,
+			expected: `param "malformed" of component "deployment" has unknown type "boolean" in its schema`},
+		{name: "param without schema", path: []string{"image"}, value: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := s.ValidateParam("deployment", tc.path, tc.value)
+			if tc.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.EqualError(t, err, tc.expected)
+		})
+	}
+
+	require.NoError(t, s.ValidateParam("service", []string{"replicas"}, "3"))
+}
+
+func TestSchemas_ValidateJSON(t *testing.T) {
+	s := Schemas{
+		"deployment": ComponentSchema{
+			"image":    {Type: prototype.String, Required: true},
+			"replicas": {Type: prototype.Number},
+		},
+	}
+
+	cases := []struct {
+		name       string
+		moduleName string
+		data       string
+		expected   string
+	}{
+		{
+			name:       "valid",
+			moduleName: "/",
+			data:       `{"components": {"deployment": {"image": "nginx", "replicas": 2}}}`,
+		},
+		{
+			name:       "optional param is null",
+			moduleName: "/",
+			data:       `{"components": {"deployment": {"image": "nginx", "replicas": null}}}`,
+		},
+		{
+			name:       "invalid",
+			moduleName: "/",
+			data:       `{"components": {"deployment": {"replicas": "2"}}}`,
+			expected: `invalid params:
+  param "image" of component "deployment" is required
+  param "replicas" of component "deployment" must be a number, got string "2"`,
+		},
+		{
+			name:       "module prefix",
+			moduleName: "app",
+			data:       `{"components": {"deployment": {"image": "nginx"}, "app.deployment": {"replicas": "2"}}}`,
+			expected: `invalid params:
+  param "replicas" of component "deployment" must be a number, got string "2"`,
+		},
+		{
+			name:       "not an object",
+			moduleName: "/",
+			data:       `{"components": {"deployment": "nginx"}}`,
+			expected: `invalid params:
+  params of component "deployment" are not an object`,
+		},
+		{
+			name:       "invalid JSON",
+			moduleName: "/",
+			data:       `{`,
+			expected:   "decoding evaluated params: unexpected end of JSON input",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := s.ValidateJSON(tc.moduleName, tc.data)
+			if tc.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.EqualError(t, err, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,14 @@
+local params = import '../../components/params.libsonnet';
+
+params + {
+  components+: {
+    guestbook+: {
+      name: 'guestbook-dev',
+      replicas: 2,
+      containerPort: '8080',
+      labels: {
+        tier: 'frontend',
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,8 @@
+{
+   "components": {
+      "ds": {
+         "name": "name",
+         "replicas": 5
+      }
+   }
+}
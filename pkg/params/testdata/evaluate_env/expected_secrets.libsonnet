@@ -0,0 +1,9 @@
+{
+   "components": {
+      "ds": {
+         "name": "name",
+         "password": "s3cret",
+         "replicas": 3
+      }
+   }
+}
@@ -0,0 +1,19 @@
+local params = std.extVar("__ksonnet/params");
+local globals = import "globals.libsonnet";
+local envParams = params + {
+  components +: {
+    guestbook +: {
+      name: "guestbook-prod",
+      replicas: 3,
+    },
+    "app.other.redis" +: {
+      name: "other",
+    },
+  },
+};
+
+{
+  components: {
+    [x]: envParams.components[x] + globals, for x in std.objectFields(envParams.components)
+  },
+}
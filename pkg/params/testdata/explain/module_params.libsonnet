@@ -0,0 +1,15 @@
+{
+  global: {
+    replicas: 2,
+  },
+  components: {
+    guestbook: {
+      image: "gcr.io/heptio-images/ks-guestbook-demo:0.1",
+      name: "guestbook",
+      replicas: 1,
+    },
+    redis: {
+      name: "redis",
+    },
+  },
+}
@@ -18,7 +18,6 @@ package pipeline
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"io"
 	"path/filepath"
 	"regexp"
@@ -104,9 +103,12 @@ func (p *Pipeline) EnvParameters(moduleName string, inherited bool) (string, err
 		return "", errors.Wrapf(err, "retrieve environment params for %s", p.envName)
 	}
 
-	envParams := upgradeParams(p.envName, data)
+	envParams, err := params.ApplyDestinationParams(p.app, p.envName, upgradeParams(p.envName, data))
+	if err != nil {
+		return "", errors.Wrapf(err, "apply destination params for %s", p.envName)
+	}
 
-	env, err := p.app.Environment(p.envName)
+	env, err := app.ResolveEnvironment(p.app, p.envName)
 	if err != nil {
 		return "", errors.Wrapf(err, "load environment %s", p.envName)
 	}
@@ -119,7 +121,7 @@ func (p *Pipeline) EnvParameters(moduleName string, inherited bool) (string, err
 	)
 	vm.ExtCode("__ksonnet/params", paramsStr)
 	log.Debugf("[Pipeline.EnvParameters] Evaluating: %v", envParams)
-	return vm.EvaluateSnippet("snippet", string(envParams))
+	return vm.EvaluateSnippet("snippet", envParams)
 }
 
 func (p *Pipeline) moduleParams(module component.Module, inherited bool) (string, error) {
@@ -132,8 +134,6 @@ func (p *Pipeline) moduleParams(module component.Module, inherited bool) (string
 		return "", errors.Wrapf(err, "resolve params for %s", module.Name())
 	}
 
-	fmt.Println(paramsStr)
-
 	return paramsStr, nil
 }
 
@@ -213,6 +213,15 @@ func (p *Pipeline) moduleObjects(module component.Module, filter []string) ([]*u
 		return nil, err
 	}
 
+	schemas, err := params.LoadSchemas(p.app.Fs(), module.Dir())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = schemas.ValidateJSON(module.Name(), envParamData); err != nil {
+		return nil, errors.Wrapf(err, "validating params for module %q in environment %q", module.Name(), p.envName)
+	}
+
 	var buf bytes.Buffer
 	if err = printer.Fprint(&buf, doc); err != nil {
 		return nil, err
@@ -26,8 +26,11 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/component"
 	cmocks "github.com/ksonnet/ksonnet/pkg/component/mocks"
 	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/params"
+	"github.com/ksonnet/ksonnet/pkg/prototype"
 	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
 	"github.com/pkg/errors"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -165,6 +168,7 @@ func TestPipeline_Objects(t *testing.T) {
 
 		module := &cmocks.Module{}
 		module.On("Name").Return("")
+		module.On("Dir").Return("/components")
 		object := &astext.Object{}
 		componentMap := map[string]string{"service": "yaml"}
 		module.On("Render", "default").Return(object, componentMap, nil)
@@ -195,6 +199,49 @@ func TestPipeline_Objects(t *testing.T) {
 	})
 }
 
+func TestPipeline_Objects_invalid_params(t *testing.T) {
+	withPipeline(t, func(p *Pipeline, m *cmocks.Manager, a *appmocks.App) {
+		schemas := params.Schemas{
+			"service": params.ComponentSchema{
+				"image":    {Type: prototype.String, Required: true},
+				"replicas": {Type: prototype.Number},
+			},
+		}
+		require.NoError(t, params.SaveSchemas(a.Fs(), "/components", schemas))
+
+		module := &cmocks.Module{}
+		module.On("Name").Return("")
+		module.On("Dir").Return("/components")
+		object := &astext.Object{}
+		componentMap := map[string]string{"service": "yaml"}
+		module.On("Render", "default").Return(object, componentMap, nil)
+		module.On("ResolvedParams", "default").Return("", nil)
+
+		modules := []component.Module{module}
+		m.On("Modules", p.app, "default").Return(modules, nil)
+		a.On("EnvironmentParams", "default").Return("{}", nil)
+
+		env := &app.EnvironmentConfig{Path: "default"}
+		a.On("Environment", "default").Return(env, nil)
+
+		p.evaluateEnvFn = func(_ app.App, envName, input, params string, opts ...jsonnet.VMOpt) (string, error) {
+			return "", errors.New("unexpected evaluation")
+		}
+
+		p.evaluateEnvParamsFn = func(_ app.App, paramsPath, paramData, envName, moduleName string) (string, error) {
+			return `{"components": {"service": {"replicas": "three"}}}`, nil
+		}
+
+		_, err := p.Objects(nil)
+		require.Error(t, err)
+
+		expected := `validating params for module "" in environment "default": invalid params:
+  param "image" of component "service" is required
+  param "replicas" of component "service" must be a number, got string "three"`
+		assert.Equal(t, expected, err.Error())
+	})
+}
+
 func TestPipeline_YAML(t *testing.T) {
 	withPipeline(t, func(p *Pipeline, m *cmocks.Manager, a *appmocks.App) {
 		p.buildObjectsFn = func(_ *Pipeline, filter []string) ([]*unstructured.Unstructured, error) {
@@ -296,6 +343,7 @@ func Test_stubModule(t *testing.T) {
 func withPipeline(t *testing.T, fn func(p *Pipeline, m *cmocks.Manager, a *appmocks.App)) {
 	a := &appmocks.App{}
 	a.On("Root").Return("/")
+	a.On("Fs").Return(afero.NewMemMapFs())
 	envName := "default"
 
 	manager := &cmocks.Manager{}
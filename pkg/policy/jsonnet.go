@@ -0,0 +1,130 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package policy
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// jsonnetRule is a rule written in Jsonnet. The Jsonnet evaluates to a function
+// with `object` and `env` parameters, which returns an array of violations. A
+// violation is either a message, or an object with a message and optionally a
+// severity.
+type jsonnetRule struct {
+	fs     afero.Fs
+	path   string
+	source string
+	jPaths []string
+}
+
+var _ Rule = (*jsonnetRule)(nil)
+
+func newJsonnetRule(a app.App, path string) (*jsonnetRule, error) {
+	source, err := afero.ReadFile(a.Fs(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jsonnetRule{
+		fs:     a.Fs(),
+		path:   path,
+		source: string(source),
+		jPaths: []string{
+			filepath.Dir(path),
+			filepath.Join(a.Root(), "lib"),
+			filepath.Join(a.Root(), "vendor"),
+		},
+	}, nil
+}
+
+// Check evaluates the rule for an object.
+func (r *jsonnetRule) Check(obj *unstructured.Unstructured, env *Environment) ([]Violation, error) {
+	objectCode, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling object")
+	}
+
+	envCode, err := json.Marshal(env)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling environment")
+	}
+
+	vm := jsonnet.NewVM(jsonnet.AferoImporterOpt(r.fs))
+	vm.AddJPath(r.jPaths...)
+	vm.TLACode("object", string(objectCode))
+	vm.TLACode("env", string(envCode))
+
+	out, err := vm.EvaluateSnippet(r.path, r.source)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []json.RawMessage
+	if err = json.Unmarshal([]byte(out), &results); err != nil {
+		return nil, errors.Errorf("%s did not return an array of violations", r.path)
+	}
+
+	var violations []Violation
+	for _, result := range results {
+		v, err := parseViolation(result)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing violation returned by %s", r.path)
+		}
+
+		violations = append(violations, v)
+	}
+
+	return violations, nil
+}
+
+// parseViolation parses a violation returned by a Jsonnet rule.
+func parseViolation(data json.RawMessage) (Violation, error) {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		return Violation{Message: message}, nil
+	}
+
+	var result struct {
+		Message  string `json:"message"`
+		Severity string `json:"severity"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return Violation{}, errors.New("violations must be strings or objects")
+	}
+
+	if result.Message == "" {
+		return Violation{}, errors.New("message is required")
+	}
+
+	v := Violation{Message: result.Message}
+
+	switch Severity(result.Severity) {
+	case "":
+	case SeverityError, SeverityWarning:
+		v.Severity = Severity(result.Severity)
+	default:
+		return Violation{}, errors.Errorf("invalid severity %q", result.Severity)
+	}
+
+	return v, nil
+}
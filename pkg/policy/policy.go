@@ -0,0 +1,186 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package policy
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Severity is the severity of a violation.
+type Severity string
+
+const (
+	// SeverityError is the severity of violations which fail validation.
+	SeverityError Severity = "error"
+	// SeverityWarning is the severity of violations which are reported, but do
+	// not fail validation.
+	SeverityWarning Severity = "warning"
+	// SeverityOff disables a rule.
+	SeverityOff Severity = "off"
+)
+
+// parseSeverity parses a configured severity. Severities default to error.
+func parseSeverity(s string) (Severity, error) {
+	switch Severity(s) {
+	case "":
+		return SeverityError, nil
+	case SeverityError, SeverityWarning, SeverityOff:
+		return Severity(s), nil
+	default:
+		return "", errors.Errorf("invalid severity %q", s)
+	}
+}
+
+// Violation is a violation of a rule by an object.
+type Violation struct {
+	// Rule is the name of the rule.
+	Rule string
+	// Severity is the severity of the violation.
+	Severity Severity
+	// Message describes the violation.
+	Message string
+}
+
+// Environment describes the environment objects are rendered for.
+type Environment struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Server    string `json:"server"`
+}
+
+// Rule checks objects. The Violations a rule returns only need a Message, and
+// optionally a Severity overriding the configured severity of the rule.
+type Rule interface {
+	Check(obj *unstructured.Unstructured, env *Environment) ([]Violation, error)
+}
+
+// RuleFunc is a function which implements Rule.
+type RuleFunc func(obj *unstructured.Unstructured, env *Environment) ([]Violation, error)
+
+// Check checks an object.
+func (fn RuleFunc) Check(obj *unstructured.Unstructured, env *Environment) ([]Violation, error) {
+	return fn(obj, env)
+}
+
+type configuredRule struct {
+	name     string
+	severity Severity
+	rule     Rule
+}
+
+// Engine checks objects against the rules configured for an app.
+type Engine struct {
+	rules []configuredRule
+}
+
+// New creates an Engine with the built-in and custom rules configured in an
+// app's app.yaml.
+func New(a app.App) (*Engine, error) {
+	config, err := a.Validation()
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Engine{}
+
+	var names []string
+	for name := range config.Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rule, ok := builtinRules[name]
+		if !ok {
+			return nil, errors.Errorf("unknown validation rule %q", name)
+		}
+
+		var severity string
+		if rc := config.Rules[name]; rc != nil {
+			severity = rc.Severity
+		}
+
+		if err = e.add(name, severity, rule); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, crc := range config.CustomRules {
+		if crc == nil || crc.Path == "" {
+			return nil, errors.New("custom validation rules require a path")
+		}
+
+		name := crc.Name
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(crc.Path), filepath.Ext(crc.Path))
+		}
+
+		rule, err := newJsonnetRule(a, filepath.Join(a.Root(), crc.Path))
+		if err != nil {
+			return nil, errors.Wrapf(err, "loading validation rule %q", name)
+		}
+
+		if err = e.add(name, crc.Severity, rule); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+func (e *Engine) add(name, severity string, rule Rule) error {
+	s, err := parseSeverity(severity)
+	if err != nil {
+		return errors.Wrapf(err, "configuring validation rule %q", name)
+	}
+
+	if s == SeverityOff {
+		return nil
+	}
+
+	e.rules = append(e.rules, configuredRule{name: name, severity: s, rule: rule})
+	return nil
+}
+
+// Check checks an object against the rules. Violations are returned in the order
+// the rules are configured.
+func (e *Engine) Check(obj *unstructured.Unstructured, env *Environment) ([]Violation, error) {
+	var violations []Violation
+
+	for _, cr := range e.rules {
+		found, err := cr.rule.Check(obj, env)
+		if err != nil {
+			return nil, errors.Wrapf(err, "checking rule %q", cr.name)
+		}
+
+		for _, v := range found {
+			v.Rule = cr.name
+			if v.Severity == "" {
+				v.Severity = cr.severity
+			}
+
+			violations = append(violations, v)
+		}
+	}
+
+	return violations, nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+const testRule = `
+function(object, env)
+  if object.metadata.name == env.name then
+    []
+  else
+    [
+      "name %s does not match environment %s" % [object.metadata.name, env.name],
+      { message: "advice", severity: "warning" },
+    ]
+`
+
+func TestEngine(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		require.NoError(t, afero.WriteFile(fs, "/app/rules/name.jsonnet", []byte(testRule), 0644))
+
+		config := &app.ValidationConfig{
+			Rules: map[string]*app.RuleConfig{
+				RuleNoLatestTag:         {Severity: "warning"},
+				RuleNamespaceMatchesEnv: nil,
+				RuleResourceLimits:      {Severity: "off"},
+			},
+			CustomRules: []*app.CustomRuleConfig{
+				{Path: "rules/name.jsonnet"},
+			},
+		}
+		a.On("Validation").Return(config, nil)
+
+		e, err := New(a)
+		require.NoError(t, err)
+
+		obj := genDeployment("prod", genContainer("web", "nginx", nil))
+		violations, err := e.Check(obj, &Environment{Name: "dev", Namespace: "dev"})
+		require.NoError(t, err)
+
+		expected := []Violation{
+			{
+				Rule:     RuleNamespaceMatchesEnv,
+				Severity: SeverityError,
+				Message:  `namespace "prod" does not match namespace "dev" of environment "dev"`,
+			},
+			{
+				Rule:     RuleNoLatestTag,
+				Severity: SeverityWarning,
+				Message:  `container "web" image "nginx" has no tag`,
+			},
+			{
+				Rule:     "name",
+				Severity: SeverityError,
+				Message:  "name web does not match environment dev",
+			},
+			{
+				Rule:     "name",
+				Severity: SeverityWarning,
+				Message:  "advice",
+			},
+		}
+		require.Equal(t, expected, violations)
+	})
+}
+
+func TestNew_invalid_config(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *app.ValidationConfig
+	}{
+		{
+			name: "unknown rule",
+			config: &app.ValidationConfig{
+				Rules: map[string]*app.RuleConfig{"unknown": nil},
+			},
+		},
+		{
+			name: "invalid severity",
+			config: &app.ValidationConfig{
+				Rules: map[string]*app.RuleConfig{RuleNoLatestTag: {Severity: "fatal"}},
+			},
+		},
+		{
+			name: "custom rule without a path",
+			config: &app.ValidationConfig{
+				CustomRules: []*app.CustomRuleConfig{{Name: "rule"}},
+			},
+		},
+		{
+			name: "missing custom rule",
+			config: &app.ValidationConfig{
+				CustomRules: []*app.CustomRuleConfig{{Path: "rules/missing.jsonnet"}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+				a.On("Validation").Return(tc.config, nil)
+
+				_, err := New(a)
+				require.Error(t, err)
+			})
+		})
+	}
+}
+
+func Test_parseViolation(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     string
+		expected Violation
+		isErr    bool
+	}{
+		{
+			name:     "message",
+			data:     `"invalid"`,
+			expected: Violation{Message: "invalid"},
+		},
+		{
+			name:     "object",
+			data:     `{"message": "invalid", "severity": "warning"}`,
+			expected: Violation{Message: "invalid", Severity: SeverityWarning},
+		},
+		{
+			name:  "object without a message",
+			data:  `{"severity": "warning"}`,
+			isErr: true,
+		},
+		{
+			name:  "invalid severity",
+			data:  `{"message": "invalid", "severity": "off"}`,
+			isErr: true,
+		},
+		{
+			name:  "number",
+			data:  `1`,
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := parseViolation([]byte(tc.data))
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, v)
+		})
+	}
+}
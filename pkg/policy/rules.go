@@ -0,0 +1,148 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// RuleResourceLimits requires containers to have CPU and memory limits.
+	RuleResourceLimits = "resource-limits"
+	// RuleNoLatestTag requires container images to have a tag other than latest,
+	// or a digest.
+	RuleNoLatestTag = "no-latest-tag"
+	// RuleNamespaceMatchesEnv requires namespaced objects to be in the namespace
+	// of their environment's destination.
+	RuleNamespaceMatchesEnv = "namespace-matches-env"
+)
+
+var (
+	builtinRules = map[string]Rule{
+		RuleResourceLimits:      RuleFunc(checkResourceLimits),
+		RuleNoLatestTag:         RuleFunc(checkNoLatestTag),
+		RuleNamespaceMatchesEnv: RuleFunc(checkNamespaceMatchesEnv),
+	}
+
+	// podSpecPaths are the paths of pod specs in the objects which contain them.
+	podSpecPaths = [][]string{
+		{"spec"},
+		{"spec", "template", "spec"},
+		{"spec", "jobTemplate", "spec", "template", "spec"},
+	}
+)
+
+func checkResourceLimits(obj *unstructured.Unstructured, env *Environment) ([]Violation, error) {
+	var violations []Violation
+
+	for _, c := range containers(obj) {
+		limits, _, _ := unstructured.NestedMap(c, "resources", "limits")
+		for _, resource := range []string{"cpu", "memory"} {
+			if _, ok := limits[resource]; !ok {
+				violations = append(violations, Violation{
+					Message: fmt.Sprintf("container %q has no %s limit", containerName(c), resource),
+				})
+			}
+		}
+	}
+
+	return violations, nil
+}
+
+func checkNoLatestTag(obj *unstructured.Unstructured, env *Environment) ([]Violation, error) {
+	var violations []Violation
+
+	for _, c := range containers(obj) {
+		image, _, _ := unstructured.NestedString(c, "image")
+		if strings.Contains(image, "@") {
+			continue
+		}
+
+		tag := imageTag(image)
+		switch tag {
+		case "":
+			violations = append(violations, Violation{
+				Message: fmt.Sprintf("container %q image %q has no tag", containerName(c), image),
+			})
+		case "latest":
+			violations = append(violations, Violation{
+				Message: fmt.Sprintf("container %q image %q uses the latest tag", containerName(c), image),
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+func checkNamespaceMatchesEnv(obj *unstructured.Unstructured, env *Environment) ([]Violation, error) {
+	namespace := obj.GetNamespace()
+	if namespace == "" || env == nil || env.Namespace == "" || namespace == env.Namespace {
+		return nil, nil
+	}
+
+	return []Violation{
+		{
+			Message: fmt.Sprintf("namespace %q does not match namespace %q of environment %q",
+				namespace, env.Namespace, env.Name),
+		},
+	}, nil
+}
+
+// containers returns the containers and init containers of an object's pod spec.
+func containers(obj *unstructured.Unstructured) []map[string]interface{} {
+	var found []map[string]interface{}
+
+	for _, path := range podSpecPaths {
+		podSpec, ok, _ := unstructured.NestedMap(obj.Object, path...)
+		if !ok {
+			continue
+		}
+
+		for _, field := range []string{"initContainers", "containers"} {
+			list, _, _ := unstructured.NestedSlice(podSpec, field)
+			for _, item := range list {
+				if c, ok := item.(map[string]interface{}); ok {
+					found = append(found, c)
+				}
+			}
+		}
+	}
+
+	return found
+}
+
+func containerName(c map[string]interface{}) string {
+	name, _, _ := unstructured.NestedString(c, "name")
+	return name
+}
+
+// imageTag returns the tag of an image reference. Registry ports are not tags.
+func imageTag(image string) string {
+	name := image
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	i := strings.LastIndex(name, ":")
+	if i < 0 {
+		return ""
+	}
+
+	return name[i+1:]
+}
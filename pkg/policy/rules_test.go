@@ -0,0 +1,160 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func genDeployment(namespace string, containers ...interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1beta2",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name": "web",
+			},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": containers,
+					},
+				},
+			},
+		},
+	}
+
+	if namespace != "" {
+		obj.SetNamespace(namespace)
+	}
+
+	return obj
+}
+
+func genContainer(name, image string, limits map[string]interface{}) map[string]interface{} {
+	c := map[string]interface{}{
+		"name":  name,
+		"image": image,
+	}
+
+	if limits != nil {
+		c["resources"] = map[string]interface{}{"limits": limits}
+	}
+
+	return c
+}
+
+func messages(violations []Violation) []string {
+	var got []string
+	for _, v := range violations {
+		got = append(got, v.Message)
+	}
+	return got
+}
+
+func Test_checkResourceLimits(t *testing.T) {
+	cases := []struct {
+		name     string
+		obj      *unstructured.Unstructured
+		expected []string
+	}{
+		{
+			name: "with limits",
+			obj: genDeployment("", genContainer("web", "nginx:1.15",
+				map[string]interface{}{"cpu": "100m", "memory": "64Mi"})),
+		},
+		{
+			name: "without a memory limit",
+			obj: genDeployment("", genContainer("web", "nginx:1.15",
+				map[string]interface{}{"cpu": "100m"})),
+			expected: []string{`container "web" has no memory limit`},
+		},
+		{
+			name: "without limits",
+			obj:  genDeployment("", genContainer("web", "nginx:1.15", nil)),
+			expected: []string{
+				`container "web" has no cpu limit`,
+				`container "web" has no memory limit`,
+			},
+		},
+		{
+			name: "without containers",
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{"apiVersion": "v1", "kind": "Service"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			violations, err := checkResourceLimits(tc.obj, &Environment{})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, messages(violations))
+		})
+	}
+}
+
+func Test_checkNoLatestTag(t *testing.T) {
+	cases := []struct {
+		image    string
+		expected []string
+	}{
+		{image: "nginx:1.15"},
+		{image: "registry:5000/team/nginx:1.15"},
+		{image: "nginx@sha256:abcdef"},
+		{image: "nginx", expected: []string{`container "web" image "nginx" has no tag`}},
+		{image: "registry:5000/nginx", expected: []string{`container "web" image "registry:5000/nginx" has no tag`}},
+		{image: "nginx:latest", expected: []string{`container "web" image "nginx:latest" uses the latest tag`}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.image, func(t *testing.T) {
+			obj := genDeployment("", genContainer("web", tc.image, nil))
+			violations, err := checkNoLatestTag(obj, &Environment{})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, messages(violations))
+		})
+	}
+}
+
+func Test_checkNamespaceMatchesEnv(t *testing.T) {
+	env := &Environment{Name: "dev", Namespace: "dev"}
+
+	cases := []struct {
+		name      string
+		namespace string
+		expected  []string
+	}{
+		{name: "matching namespace", namespace: "dev"},
+		{name: "without a namespace"},
+		{
+			name:      "other namespace",
+			namespace: "prod",
+			expected:  []string{`namespace "prod" does not match namespace "dev" of environment "dev"`},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			violations, err := checkNamespaceMatchesEnv(genDeployment(tc.namespace), env)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, messages(violations))
+		})
+	}
+}
@@ -16,11 +16,11 @@
 package registry
 
 import (
-	"bytes"
 	"io/ioutil"
 	"net/url"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
@@ -164,8 +164,8 @@ func (h *Helm) ResolveLibrary(partName string, partAlias string, version string,
 
 			name := path.Join(chart.Name, "helm", chart.Version, f.Name)
 
-			if containsHelmHook(name, b) {
-				// skip this file because it has a helm hook in it
+			if containsUnsupportedHelmHook(name, b) {
+				// skip this file because it has a helm hook ksonnet can't run
 				return nil
 			}
 
@@ -251,13 +251,23 @@ func (h *Helm) SetURI(uri string) error {
 	return nil
 }
 
-// containsHelmHook checks file contents for helm hooks. Helm hooks are denoted by
-// `helm.sh/hook`.
-func containsHelmHook(name string, b []byte) bool {
+// reHelmHook matches helm hook annotations, which are denoted by `helm.sh/hook`.
+var reHelmHook = regexp.MustCompile(`helm\.sh/hook["']?\s*:\s*["']?([^"'\n]*)`)
+
+// containsUnsupportedHelmHook checks file contents for helm hooks which do not have
+// a ksonnet phase. Supported hooks are converted to ksonnet hooks when the chart
+// is rendered.
+func containsUnsupportedHelmHook(name string, b []byte) bool {
 	dir := filepath.Dir(name)
 	ext := filepath.Ext(name)
-	if strings.Contains(dir, "templates") && ksstrings.InSlice(ext, []string{".yaml", ".yml"}) {
-		return bytes.Contains(b, []byte("helm.sh/hook"))
+	if !strings.Contains(dir, "templates") || !ksstrings.InSlice(ext, []string{".yaml", ".yml"}) {
+		return false
+	}
+
+	for _, match := range reHelmHook.FindAllSubmatch(b, -1) {
+		if _, ok := helm.HookPhase(string(match[1])); !ok {
+			return true
+		}
 	}
 
 	return false
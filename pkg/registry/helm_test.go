@@ -333,7 +333,7 @@ func TestHelm_ValidateURI_invalid(t *testing.T) {
 	})
 }
 
-func Test_containsHelmHook(t *testing.T) {
+func Test_containsUnsupportedHelmHook(t *testing.T) {
 	cases := []struct {
 		name     string
 		path     string
@@ -341,11 +341,23 @@ func Test_containsHelmHook(t *testing.T) {
 		expected bool
 	}{
 		{
-			name:     "contains helm hook",
+			name:     "contains unsupported helm hook",
 			path:     "templates/file.yaml",
 			b:        []byte(`"helm.sh/hook": test-success`),
 			expected: true,
 		},
+		{
+			name:     "contains supported helm hook",
+			path:     "templates/file.yaml",
+			b:        []byte("\"helm.sh/hook\": pre-install,pre-upgrade\n\"helm.sh/hook-weight\": \"-5\""),
+			expected: false,
+		},
+		{
+			name:     "contains supported and unsupported helm hooks",
+			path:     "templates/file.yaml",
+			b:        []byte("helm.sh/hook: \"pre-install\"\n---\nhelm.sh/hook: post-delete"),
+			expected: true,
+		},
 		{
 			name:     "doesn't contain helm hook",
 			path:     "templates/file.yaml",
@@ -374,7 +386,7 @@ func Test_containsHelmHook(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := containsHelmHook(tc.path, tc.b)
+			got := containsUnsupportedHelmHook(tc.path, tc.b)
 			assert.Equal(t, tc.expected, got)
 		})
 	}
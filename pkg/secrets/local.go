@@ -0,0 +1,180 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// KeyFileEnvVar sets the path of the key file used by the local key provider.
+	KeyFileEnvVar = "KS_SECRET_KEY_FILE"
+
+	keySize = 32
+)
+
+// LocalKeyProvider encrypts secrets with AES-256-GCM using a key stored in a
+// local file. The key file is created when the first secret is encrypted. It
+// must be shared out of band with everyone who renders the environment.
+type LocalKeyProvider struct {
+	fs   afero.Fs
+	path string
+}
+
+var _ KeyProvider = (*LocalKeyProvider)(nil)
+
+// NewLocalKeyProvider creates an instance of LocalKeyProvider.
+func NewLocalKeyProvider(fs afero.Fs, path string) *LocalKeyProvider {
+	return &LocalKeyProvider{
+		fs:   fs,
+		path: path,
+	}
+}
+
+func newLocalProvider(a app.App) (KeyProvider, error) {
+	path, err := defaultKeyPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLocalKeyProvider(a.Fs(), path), nil
+}
+
+// defaultKeyPath returns the path of the local key file. It is set with
+// KS_SECRET_KEY_FILE, and defaults to a file in the user's ksonnet config
+// directory.
+// TODO: make this work with windows
+func defaultKeyPath() (string, error) {
+	if path := os.Getenv(KeyFileEnvVar); path != "" {
+		return path, nil
+	}
+
+	homeDir := os.Getenv("HOME")
+	if homeDir == "" {
+		return "", errors.Errorf("could not find home directory; set %s to the path of the secret key file", KeyFileEnvVar)
+	}
+
+	return filepath.Join(homeDir, ".config", "ksonnet", "secret.key"), nil
+}
+
+// Encrypt encrypts a value. The nonce is prepended to the sealed value, and
+// additionalData is authenticated with it.
+func (p *LocalKeyProvider) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := p.aead(true)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "generating nonce")
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// Decrypt decrypts a value encrypted by Encrypt with the same additionalData.
+func (p *LocalKeyProvider) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := p.aead(false)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, additionalData)
+	if err != nil {
+		return nil, errors.Errorf("unable to decrypt with key %s", p.path)
+	}
+
+	return plaintext, nil
+}
+
+func (p *LocalKeyProvider) aead(create bool) (cipher.AEAD, error) {
+	key, err := p.key(create)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// key reads the key file. If create is true, a missing key file is generated.
+func (p *LocalKeyProvider) key(create bool) ([]byte, error) {
+	exists, err := afero.Exists(p.fs, p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		if !create {
+			return nil, errors.Errorf("secret key file %s does not exist", p.path)
+		}
+
+		return p.generate()
+	}
+
+	b, err := afero.ReadFile(p.fs, p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(b)))
+	if err != nil || len(key) != keySize {
+		return nil, errors.Errorf("secret key file %s is invalid", p.path)
+	}
+
+	return key, nil
+}
+
+func (p *LocalKeyProvider) generate() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, errors.Wrap(err, "generating key")
+	}
+
+	if err := p.fs.MkdirAll(filepath.Dir(p.path), 0700); err != nil {
+		return nil, err
+	}
+
+	data := base64.StdEncoding.EncodeToString(key) + "\n"
+	if err := afero.WriteFile(p.fs, p.path, []byte(data), 0600); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Generated secret key file %s", p.path)
+	return key, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package secrets
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalKeyProvider(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	p := NewLocalKeyProvider(fs, "/keys/secret.key")
+
+	aad := []byte("default/guestbook/password")
+
+	_, err := p.Decrypt([]byte("ciphertext"), aad)
+	require.Error(t, err, "decrypting without a key file")
+
+	ciphertext, err := p.Encrypt([]byte("s3cret"), aad)
+	require.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "s3cret")
+
+	fi, err := fs.Stat("/keys/secret.key")
+	require.NoError(t, err)
+	assert.Equal(t, "-rw-------", fi.Mode().String())
+
+	plaintext, err := p.Decrypt(ciphertext, aad)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", string(plaintext))
+
+	_, err = p.Decrypt(ciphertext, []byte("default/guestbook/token"))
+	require.Error(t, err, "decrypting with different additional data")
+
+	other := NewLocalKeyProvider(afero.NewMemMapFs(), "/keys/secret.key")
+	_, err = other.Encrypt([]byte("other"), aad)
+	require.NoError(t, err)
+
+	_, err = other.Decrypt(ciphertext, aad)
+	require.Error(t, err, "decrypting with a different key")
+}
+
+func TestLocalKeyProvider_invalid_key(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/secret.key", []byte("invalid"), 0600))
+
+	p := NewLocalKeyProvider(fs, "/secret.key")
+	_, err := p.Encrypt([]byte("s3cret"), nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,293 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+// Package secrets stores encrypted environment parameters. Secret values are
+// encrypted by a KeyProvider and kept in a file in the environment's
+// directory, next to its params.libsonnet.
+package secrets
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// ProviderEnvVar selects the key provider used to encrypt secrets.
+	ProviderEnvVar = "KS_SECRET_PROVIDER"
+	// DefaultProvider is the key provider used if none is selected.
+	DefaultProvider = "local"
+	// FileName is the name of the file holding an environment's secrets.
+	FileName = "secrets.json"
+	// Mask replaces secret values in output.
+	Mask = "********"
+)
+
+// KeyProvider encrypts and decrypts secret values. additionalData names the
+// secret as `<environment>/<component>/<param>`. It is not encrypted, but a
+// value must only decrypt with the additionalData it was encrypted with, so a
+// value can not be moved to another parameter or environment.
+type KeyProvider interface {
+	// Encrypt encrypts a value.
+	Encrypt(plaintext, additionalData []byte) ([]byte, error)
+	// Decrypt decrypts a value encrypted by Encrypt.
+	Decrypt(ciphertext, additionalData []byte) ([]byte, error)
+}
+
+// ProviderFactory creates a KeyProvider for an app.
+type ProviderFactory func(a app.App) (KeyProvider, error)
+
+var providers = map[string]ProviderFactory{
+	DefaultProvider: newLocalProvider,
+}
+
+// RegisterProvider registers a key provider, e.g. one backed by an external key
+// management service. It is not safe for concurrent use and should be called
+// from an init function.
+func RegisterProvider(name string, factory ProviderFactory) {
+	providers[name] = factory
+}
+
+// ProviderName returns the name of the selected key provider.
+func ProviderName() string {
+	if name := os.Getenv(ProviderEnvVar); name != "" {
+		return name
+	}
+
+	return DefaultProvider
+}
+
+// NewKeyProvider creates the named key provider.
+func NewKeyProvider(a app.App, name string) (KeyProvider, error) {
+	factory, ok := providers[name]
+	if !ok {
+		return nil, errors.Errorf("secret key provider %q is not registered", name)
+	}
+
+	return factory(a)
+}
+
+// Secrets are the encrypted parameters of an environment.
+type Secrets struct {
+	// Provider is the name of the key provider which encrypted the values.
+	Provider string `json:"provider"`
+	// Components maps component names to their encrypted parameters. Values are
+	// base64 encoded.
+	Components map[string]map[string]string `json:"components"`
+}
+
+// Entry is a secret parameter.
+type Entry struct {
+	// ComponentName is the component that owns this entry.
+	ComponentName string
+	// ParamName is the name of the parameter.
+	ParamName string
+}
+
+// additionalData returns the data authenticated with a secret value.
+// Destinations of an environment share its secrets.
+func additionalData(envName string, entry Entry) []byte {
+	envName, _ = app.SplitDestination(envName)
+	return []byte(strings.Join([]string{envName, entry.ComponentName, entry.ParamName}, "/"))
+}
+
+// Path returns the path of the secrets file for an environment. Destinations
+// of an environment share its secrets.
+func Path(a app.App, envName string) string {
+	envName, _ = app.SplitDestination(envName)
+	return filepath.Join(a.Root(), app.EnvironmentDirName, envName, FileName)
+}
+
+// Load loads the secrets of an environment. An environment without a secrets
+// file has no secrets.
+func Load(a app.App, envName string) (*Secrets, error) {
+	s := &Secrets{
+		Components: make(map[string]map[string]string),
+	}
+
+	path := Path(a, envName)
+	exists, err := afero.Exists(a.Fs(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return s, nil
+	}
+
+	b, err := afero.ReadFile(a.Fs(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(b, s); err != nil {
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+
+	if s.Components == nil {
+		s.Components = make(map[string]map[string]string)
+	}
+
+	return s, nil
+}
+
+// Save writes the secrets of an environment.
+func (s *Secrets) Save(a app.App, envName string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(a.Fs(), Path(a, envName), append(b, '\n'), app.DefaultFilePermissions)
+}
+
+// Entries returns the secret parameters sorted by component and name.
+func (s *Secrets) Entries() []Entry {
+	var entries []Entry
+	for componentName, params := range s.Components {
+		for paramName := range params {
+			entries = append(entries, Entry{ComponentName: componentName, ParamName: paramName})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].ComponentName != entries[j].ComponentName {
+			return entries[i].ComponentName < entries[j].ComponentName
+		}
+		return entries[i].ParamName < entries[j].ParamName
+	})
+
+	return entries
+}
+
+// Set encrypts a secret parameter for a component in an environment.
+func Set(a app.App, envName, componentName, paramName, value string) error {
+	s, err := Load(a, envName)
+	if err != nil {
+		return err
+	}
+
+	name := ProviderName()
+	if len(s.Components) > 0 && s.Provider != name {
+		return errors.Errorf("secrets for environment %q are encrypted by key provider %q, not %q",
+			envName, s.Provider, name)
+	}
+
+	provider, err := NewKeyProvider(a, name)
+	if err != nil {
+		return err
+	}
+
+	entry := Entry{ComponentName: componentName, ParamName: paramName}
+	ciphertext, err := provider.Encrypt([]byte(value), additionalData(envName, entry))
+	if err != nil {
+		return errors.Wrapf(err, "encrypting %s.%s", componentName, paramName)
+	}
+
+	if _, ok := s.Components[componentName]; !ok {
+		s.Components[componentName] = make(map[string]string)
+	}
+
+	s.Provider = name
+	s.Components[componentName][paramName] = base64.StdEncoding.EncodeToString(ciphertext)
+
+	return s.Save(a, envName)
+}
+
+// Reseal decrypts the secrets of an environment and encrypts them again for a
+// new environment name, because the environment name is authenticated with
+// each value. The result is saved once the environment has been renamed. It
+// is nil if the environment has no secrets.
+func Reseal(a app.App, from, to string) (*Secrets, error) {
+	s, err := Load(a, from)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.Components) == 0 {
+		return nil, nil
+	}
+
+	provider, err := NewKeyProvider(a, s.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range s.Entries() {
+		ciphertext, err := base64.StdEncoding.DecodeString(s.Components[entry.ComponentName][entry.ParamName])
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding %s.%s", entry.ComponentName, entry.ParamName)
+		}
+
+		plaintext, err := provider.Decrypt(ciphertext, additionalData(from, entry))
+		if err != nil {
+			return nil, errors.Wrapf(err, "decrypting %s.%s", entry.ComponentName, entry.ParamName)
+		}
+
+		ciphertext, err = provider.Encrypt(plaintext, additionalData(to, entry))
+		if err != nil {
+			return nil, errors.Wrapf(err, "encrypting %s.%s", entry.ComponentName, entry.ParamName)
+		}
+
+		s.Components[entry.ComponentName][entry.ParamName] = base64.StdEncoding.EncodeToString(ciphertext)
+	}
+
+	return s, nil
+}
+
+// Decrypt decrypts the secret parameters of an environment. The result is keyed
+// by component name, then parameter name.
+func Decrypt(a app.App, envName string) (map[string]map[string]interface{}, error) {
+	s, err := Load(a, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.Components) == 0 {
+		return nil, nil
+	}
+
+	provider, err := NewKeyProvider(a, s.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]map[string]interface{})
+	for _, entry := range s.Entries() {
+		ciphertext, err := base64.StdEncoding.DecodeString(s.Components[entry.ComponentName][entry.ParamName])
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding %s.%s", entry.ComponentName, entry.ParamName)
+		}
+
+		plaintext, err := provider.Decrypt(ciphertext, additionalData(envName, entry))
+		if err != nil {
+			return nil, errors.Wrapf(err, "decrypting %s.%s", entry.ComponentName, entry.ParamName)
+		}
+
+		if _, ok := values[entry.ComponentName]; !ok {
+			values[entry.ComponentName] = make(map[string]interface{})
+		}
+		values[entry.ComponentName][entry.ParamName] = string(plaintext)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package secrets
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withKeyFile(t *testing.T, fn func(*mocks.App, afero.Fs)) {
+	old := os.Getenv(KeyFileEnvVar)
+	require.NoError(t, os.Setenv(KeyFileEnvVar, "/keys/secret.key"))
+	defer os.Setenv(KeyFileEnvVar, old)
+
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		require.NoError(t, fs.MkdirAll("/app/environments/default", app.DefaultFolderPermissions))
+		fn(a, fs)
+	})
+}
+
+func TestSet(t *testing.T) {
+	withKeyFile(t, func(a *mocks.App, fs afero.Fs) {
+		require.NoError(t, Set(a, "default", "guestbook", "password", "s3cret"))
+		require.NoError(t, Set(a, "default@us-east", "guestbook", "token", "t0ken"))
+		require.NoError(t, Set(a, "default", "redis", "password", "r3dis"))
+
+		test.AssertExists(t, fs, "/keys/secret.key")
+
+		b, err := afero.ReadFile(fs, "/app/environments/default/secrets.json")
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), "s3cret")
+
+		var s Secrets
+		require.NoError(t, json.Unmarshal(b, &s))
+		assert.Equal(t, DefaultProvider, s.Provider)
+
+		expected := []Entry{
+			{ComponentName: "guestbook", ParamName: "password"},
+			{ComponentName: "guestbook", ParamName: "token"},
+			{ComponentName: "redis", ParamName: "password"},
+		}
+		assert.Equal(t, expected, s.Entries())
+
+		values, err := Decrypt(a, "default")
+		require.NoError(t, err)
+
+		expectedValues := map[string]map[string]interface{}{
+			"guestbook": {"password": "s3cret", "token": "t0ken"},
+			"redis":     {"password": "r3dis"},
+		}
+		assert.Equal(t, expectedValues, values)
+	})
+}
+
+func TestDecrypt_moved_value(t *testing.T) {
+	withKeyFile(t, func(a *mocks.App, fs afero.Fs) {
+		require.NoError(t, Set(a, "default", "guestbook", "password", "s3cret"))
+
+		s, err := Load(a, "default")
+		require.NoError(t, err)
+
+		s.Components["guestbook"]["token"] = s.Components["guestbook"]["password"]
+		delete(s.Components["guestbook"], "password")
+		require.NoError(t, s.Save(a, "default"))
+
+		_, err = Decrypt(a, "default")
+		require.Error(t, err)
+	})
+}
+
+func TestReseal(t *testing.T) {
+	withKeyFile(t, func(a *mocks.App, fs afero.Fs) {
+		resealed, err := Reseal(a, "default", "prod")
+		require.NoError(t, err)
+		assert.Nil(t, resealed)
+
+		require.NoError(t, Set(a, "default", "guestbook", "password", "s3cret"))
+
+		resealed, err = Reseal(a, "default", "prod")
+		require.NoError(t, err)
+
+		require.NoError(t, fs.MkdirAll("/app/environments/prod", app.DefaultFolderPermissions))
+		require.NoError(t, resealed.Save(a, "prod"))
+
+		values, err := Decrypt(a, "prod")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret", values["guestbook"]["password"])
+	})
+}
+
+func TestSet_different_provider(t *testing.T) {
+	withKeyFile(t, func(a *mocks.App, fs afero.Fs) {
+		s := &Secrets{
+			Provider: "kms",
+			Components: map[string]map[string]string{
+				"guestbook": {"password": base64.StdEncoding.EncodeToString([]byte("encrypted"))},
+			},
+		}
+		require.NoError(t, s.Save(a, "default"))
+
+		err := Set(a, "default", "guestbook", "token", "t0ken")
+		require.Error(t, err)
+	})
+}
+
+func TestDecrypt_no_secrets(t *testing.T) {
+	withKeyFile(t, func(a *mocks.App, fs afero.Fs) {
+		values, err := Decrypt(a, "default")
+		require.NoError(t, err)
+		assert.Nil(t, values)
+	})
+}
+
+type fakeProvider struct{}
+
+func (fakeProvider) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	return append([]byte("fake:"), plaintext...), nil
+}
+
+func (fakeProvider) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	return ciphertext[len("fake:"):], nil
+}
+
+func TestRegisterProvider(t *testing.T) {
+	RegisterProvider("fake", func(a app.App) (KeyProvider, error) {
+		return fakeProvider{}, nil
+	})
+	defer delete(providers, "fake")
+
+	old := os.Getenv(ProviderEnvVar)
+	require.NoError(t, os.Setenv(ProviderEnvVar, "fake"))
+	defer os.Setenv(ProviderEnvVar, old)
+
+	withKeyFile(t, func(a *mocks.App, fs afero.Fs) {
+		require.NoError(t, Set(a, "default", "guestbook", "password", "s3cret"))
+
+		s, err := Load(a, "default")
+		require.NoError(t, err)
+		assert.Equal(t, "fake", s.Provider)
+
+		values, err := Decrypt(a, "default")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret", values["guestbook"]["password"])
+
+		test.AssertNotExists(t, fs, "/keys/secret.key")
+	})
+}
+
+func TestNewKeyProvider_unknown(t *testing.T) {
+	withKeyFile(t, func(a *mocks.App, fs afero.Fs) {
+		_, err := NewKeyProvider(a, "unknown")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,60 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package archive
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+)
+
+// Tar handles tar archives.
+type Tar struct {
+}
+
+// Unarchive un-tars the contents of a reader. The handler will be called
+// for every regular file in the archive.
+func (t *Tar) Unarchive(r io.Reader, handler FileHandler) error {
+	if r == nil {
+		return errors.New("tar reader is nil")
+	}
+
+	tarReader := tar.NewReader(r)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeReg, tar.TypeRegA:
+			tf := &File{
+				Name:   header.Name,
+				Reader: tarReader,
+			}
+
+			if err = handler(tf); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
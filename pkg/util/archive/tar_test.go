@@ -0,0 +1,59 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Tar(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "app/", Typeflag: tar.TypeDir, Mode: 0755}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "app/app.yaml", Typeflag: tar.TypeReg, Mode: 0644, Size: 5}))
+	_, err := tw.Write([]byte("hello"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	files := make(map[string]string)
+
+	handler := func(tf *File) error {
+		b, err := ioutil.ReadAll(tf.Reader)
+		if err != nil {
+			return err
+		}
+		files[tf.Name] = string(b)
+		return nil
+	}
+
+	tr := &Tar{}
+	err = tr.Unarchive(&buf, handler)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]string{"app/app.yaml": "hello"}, files)
+}
+
+func Test_Tar_nil_reader(t *testing.T) {
+	tr := &Tar{}
+	err := tr.Unarchive(nil, func(*File) error { return nil })
+	require.Error(t, err)
+}
@@ -16,7 +16,6 @@
 package archive
 
 import (
-	"archive/tar"
 	"compress/gzip"
 	"errors"
 	"io"
@@ -38,31 +37,6 @@ func (t *Tgz) Unarchive(r io.Reader, handler FileHandler) error {
 		return err
 	}
 
-	tarReader := tar.NewReader(gzReader)
-
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		}
-
-		if err != nil {
-			return err
-		}
-
-		name := header.Name
-
-		switch header.Typeflag {
-		case 0:
-			tf := &File{
-				Name:   name,
-				Reader: tarReader,
-			}
-
-			if err = handler(tf); err != nil {
-				return err
-			}
-		}
-	}
-	return nil
+	tr := &Tar{}
+	return tr.Unarchive(gzReader, handler)
 }
@@ -0,0 +1,237 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+// Package compare compares decoded JSON values field by field.
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+var (
+	identifierRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Change is a field whose value differs between two values.
+type Change struct {
+	// Path is the JSON path of the field.
+	Path string
+	// Old is the value of the field in the first value. It is nil if the field
+	// only exists in the second value.
+	Old interface{}
+	// New is the value of the field in the second value. It is nil if the field
+	// only exists in the first value.
+	New interface{}
+}
+
+// Opt is an option for Values.
+type Opt func(*comparer)
+
+// FirstKeysOnly only compares the keys of maps and the items of named lists
+// which exist in the first value. Fields which were only added to the second
+// value, e.g. by a server, are ignored.
+func FirstKeysOnly() Opt {
+	return func(c *comparer) {
+		c.firstKeysOnly = true
+	}
+}
+
+type comparer struct {
+	firstKeysOnly bool
+}
+
+// Values returns the changes between two values. Lists of objects with names
+// are paired by name, other lists are compared by index. Numbers are compared
+// by value.
+func Values(path string, v1, v2 interface{}, opts ...Opt) []Change {
+	c := &comparer{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c.values(path, v1, v2, nil)
+}
+
+func (c *comparer) values(path string, v1, v2 interface{}, changes []Change) []Change {
+	switch t1 := v1.(type) {
+	case map[string]interface{}:
+		if t2, ok := v2.(map[string]interface{}); ok {
+			return c.maps(path, t1, t2, changes)
+		}
+	case []interface{}:
+		if t2, ok := v2.([]interface{}); ok {
+			return c.slices(path, t1, t2, changes)
+		}
+	}
+
+	if valuesEqual(v1, v2) {
+		return changes
+	}
+
+	return append(changes, Change{Path: path, Old: v1, New: v2})
+}
+
+func (c *comparer) maps(path string, m1, m2 map[string]interface{}, changes []Change) []Change {
+	keys := make(map[string]bool)
+	for k := range m1 {
+		keys[k] = true
+	}
+	if !c.firstKeysOnly {
+		for k := range m2 {
+			keys[k] = true
+		}
+	}
+
+	var sortedKeys []string
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, k := range sortedKeys {
+		changes = c.values(KeyPath(path, k), m1[k], m2[k], changes)
+	}
+
+	return changes
+}
+
+func (c *comparer) slices(path string, s1, s2 []interface{}, changes []Change) []Change {
+	named1, ok1 := namedItems(s1)
+	named2, ok2 := namedItems(s2)
+	if ok1 && ok2 {
+		return c.namedItems(path, s1, s2, named1, named2, changes)
+	}
+
+	max := len(s1)
+	if len(s2) > max {
+		max = len(s2)
+	}
+
+	for i := 0; i < max; i++ {
+		var v1, v2 interface{}
+		if i < len(s1) {
+			v1 = s1[i]
+		}
+		if i < len(s2) {
+			v2 = s2[i]
+		}
+
+		changes = c.values(fmt.Sprintf("%s[%d]", path, i), v1, v2, changes)
+	}
+
+	return changes
+}
+
+func (c *comparer) namedItems(path string, s1, s2 []interface{}, named1, named2 map[string]interface{}, changes []Change) []Change {
+	// Preserve the order of the first list, followed by the items which only
+	// exist in the second list.
+	var names []string
+	for _, item := range s1 {
+		names = append(names, itemName(item))
+	}
+	if !c.firstKeysOnly {
+		for _, item := range s2 {
+			if _, ok := named1[itemName(item)]; !ok {
+				names = append(names, itemName(item))
+			}
+		}
+	}
+
+	for _, name := range names {
+		changes = c.values(fmt.Sprintf("%s[name=%s]", path, name), named1[name], named2[name], changes)
+	}
+
+	return changes
+}
+
+// namedItems indexes a list by the name field of its items. It returns false
+// if any item is not an object with a unique name.
+func namedItems(s []interface{}) (map[string]interface{}, bool) {
+	m := make(map[string]interface{})
+
+	for _, item := range s {
+		name := itemName(item)
+		if name == "" {
+			return nil, false
+		}
+
+		if _, ok := m[name]; ok {
+			return nil, false
+		}
+
+		m[name] = item
+	}
+
+	return m, true
+}
+
+func itemName(item interface{}) string {
+	m, ok := item.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	name, _ := m["name"].(string)
+	return name
+}
+
+// KeyPath returns the JSON path of a key in the map at path.
+func KeyPath(path, key string) string {
+	if !identifierRe.MatchString(key) {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+
+	if path == "" {
+		return key
+	}
+
+	return fmt.Sprintf("%s.%s", path, key)
+}
+
+// valuesEqual compares scalar values. Numbers are compared by value, because
+// values decoded from JSON are floats.
+func valuesEqual(v1, v2 interface{}) bool {
+	f1, ok1 := number(v1)
+	f2, ok2 := number(v2)
+	if ok1 && ok2 {
+		return f1 == f2
+	}
+
+	return reflect.DeepEqual(v1, v2)
+}
+
+func number(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case int:
+		return float64(t), true
+	case int32:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case float32:
+		return float64(t), true
+	case float64:
+		return t, true
+	case json.Number:
+		f, err := t.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package compare
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValues(t *testing.T) {
+	containers := func(names ...string) []interface{} {
+		var s []interface{}
+		for _, name := range names {
+			s = append(s, map[string]interface{}{"name": name, "image": name + ":1"})
+		}
+		return s
+	}
+
+	cases := []struct {
+		name     string
+		path     string
+		v1       interface{}
+		v2       interface{}
+		opts     []Opt
+		expected []Change
+	}{
+		{
+			name: "equal",
+			v1:   map[string]interface{}{"a": "b"},
+			v2:   map[string]interface{}{"a": "b"},
+		},
+		{
+			name: "numbers are compared by value",
+			v1:   map[string]interface{}{"a": int64(1), "b": json.Number("2")},
+			v2:   map[string]interface{}{"a": float64(1), "b": 2},
+		},
+		{
+			name: "changed, added and removed keys",
+			v1:   map[string]interface{}{"a": "b", "c": "d"},
+			v2:   map[string]interface{}{"a": "x", "e": "f"},
+			expected: []Change{
+				{Path: "a", Old: "b", New: "x"},
+				{Path: "c", Old: "d"},
+				{Path: "e", New: "f"},
+			},
+		},
+		{
+			name: "added keys are ignored with first keys only",
+			v1:   map[string]interface{}{"a": "b", "c": "d"},
+			v2:   map[string]interface{}{"a": "x", "e": "f"},
+			opts: []Opt{FirstKeysOnly()},
+			expected: []Change{
+				{Path: "a", Old: "b", New: "x"},
+				{Path: "c", Old: "d"},
+			},
+		},
+		{
+			name: "keys which are not identifiers are quoted",
+			v1:   map[string]interface{}{"metadata": map[string]interface{}{"app.kubernetes.io/name": "a"}},
+			v2:   map[string]interface{}{"metadata": map[string]interface{}{"app.kubernetes.io/name": "b"}},
+			expected: []Change{
+				{Path: `metadata["app.kubernetes.io/name"]`, Old: "a", New: "b"},
+			},
+		},
+		{
+			name: "named list items are paired by name",
+			v1:   map[string]interface{}{"containers": containers("a", "b")},
+			v2:   map[string]interface{}{"containers": containers("b", "c")},
+			expected: []Change{
+				{Path: "containers[name=a]", Old: containers("a")[0]},
+				{Path: "containers[name=c]", New: containers("c")[0]},
+			},
+		},
+		{
+			name: "added named list items are ignored with first keys only",
+			v1:   map[string]interface{}{"containers": containers("a", "b")},
+			v2:   map[string]interface{}{"containers": containers("b", "c")},
+			opts: []Opt{FirstKeysOnly()},
+			expected: []Change{
+				{Path: "containers[name=a]", Old: containers("a")[0]},
+			},
+		},
+		{
+			name: "other lists are compared by index",
+			v1:   map[string]interface{}{"args": []interface{}{"a", "b"}},
+			v2:   map[string]interface{}{"args": []interface{}{"a", "c", "d"}},
+			expected: []Change{
+				{Path: "args[1]", Old: "b", New: "c"},
+				{Path: "args[2]", New: "d"},
+			},
+		},
+		{
+			name: "path prefix",
+			path: "spec",
+			v1:   map[string]interface{}{"replicas": 1},
+			v2:   map[string]interface{}{"replicas": 2},
+			expected: []Change{
+				{Path: "spec.replicas", Old: 1, New: 2},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes := Values(tc.path, tc.v1, tc.v2, tc.opts...)
+			assert.Equal(t, tc.expected, changes)
+		})
+	}
+}
+
+func TestKeyPath(t *testing.T) {
+	assert.Equal(t, "a", KeyPath("", "a"))
+	assert.Equal(t, "a.b", KeyPath("a", "b"))
+	assert.Equal(t, `a["b-c"]`, KeyPath("a", "b-c"))
+}
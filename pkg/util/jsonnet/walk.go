@@ -0,0 +1,135 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package jsonnet
+
+import (
+	"github.com/google/go-jsonnet/ast"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+)
+
+// Walk calls fn for a node and all of its descendants.
+func Walk(n ast.Node, fn func(ast.Node)) {
+	if n == nil {
+		return
+	}
+
+	fn(n)
+	for _, child := range Children(n) {
+		Walk(child, fn)
+	}
+}
+
+// Children returns the children of a node. Bodies of functions defined with
+// method or local function syntax are shared with the function, so they are
+// only returned once.
+func Children(n ast.Node) []ast.Node {
+	switch t := n.(type) {
+	case *ast.Apply:
+		nodes := []ast.Node{t.Target}
+		nodes = append(nodes, t.Arguments.Positional...)
+		for _, arg := range t.Arguments.Named {
+			nodes = append(nodes, arg.Arg)
+		}
+		return nodes
+	case *ast.ApplyBrace:
+		return []ast.Node{t.Left, t.Right}
+	case *ast.Array:
+		return t.Elements
+	case *ast.ArrayComp:
+		return append([]ast.Node{t.Body}, forSpecChildren(&t.Spec)...)
+	case *ast.Assert:
+		return []ast.Node{t.Cond, t.Message, t.Rest}
+	case *ast.Binary:
+		return []ast.Node{t.Left, t.Right}
+	case *ast.Conditional:
+		return []ast.Node{t.Cond, t.BranchTrue, t.BranchFalse}
+	case *ast.Error:
+		return []ast.Node{t.Expr}
+	case *ast.Function:
+		return append(parameterChildren(&t.Parameters), t.Body)
+	case *ast.Index:
+		return []ast.Node{t.Target, t.Index}
+	case *ast.InSuper:
+		return []ast.Node{t.Index}
+	case *ast.Local:
+		var nodes []ast.Node
+		for _, bind := range t.Binds {
+			if bind.Fun != nil {
+				nodes = append(nodes, parameterChildren(&bind.Fun.Parameters)...)
+			}
+			nodes = append(nodes, bind.Body)
+		}
+		return append(nodes, t.Body)
+	case *ast.Object:
+		var nodes []ast.Node
+		for _, field := range t.Fields {
+			nodes = append(nodes, fieldChildren(field)...)
+		}
+		return nodes
+	case *astext.Object:
+		var nodes []ast.Node
+		for _, field := range t.Fields {
+			nodes = append(nodes, fieldChildren(field.ObjectField)...)
+		}
+		return nodes
+	case *ast.ObjectComp:
+		var nodes []ast.Node
+		for _, field := range t.Fields {
+			nodes = append(nodes, fieldChildren(field)...)
+		}
+		return append(nodes, forSpecChildren(&t.Spec)...)
+	case *ast.Parens:
+		return []ast.Node{t.Inner}
+	case *ast.Slice:
+		return []ast.Node{t.Target, t.BeginIndex, t.EndIndex, t.Step}
+	case *ast.SuperIndex:
+		return []ast.Node{t.Index}
+	case *ast.Unary:
+		return []ast.Node{t.Expr}
+	default:
+		return nil
+	}
+}
+
+func fieldChildren(field ast.ObjectField) []ast.Node {
+	var nodes []ast.Node
+	if field.Params != nil {
+		nodes = append(nodes, parameterChildren(field.Params)...)
+	}
+
+	return append(nodes, field.Expr1, field.Expr2, field.Expr3)
+}
+
+func parameterChildren(params *ast.Parameters) []ast.Node {
+	var nodes []ast.Node
+	for _, p := range params.Optional {
+		nodes = append(nodes, p.DefaultArg)
+	}
+
+	return nodes
+}
+
+func forSpecChildren(spec *ast.ForSpec) []ast.Node {
+	var nodes []ast.Node
+	for ; spec != nil; spec = spec.Outer {
+		nodes = append(nodes, spec.Expr)
+		for _, cond := range spec.Conditions {
+			nodes = append(nodes, cond.Expr)
+		}
+	}
+
+	return nodes
+}
@@ -0,0 +1,40 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package jsonnet
+
+import (
+	"testing"
+
+	"github.com/google/go-jsonnet/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalk(t *testing.T) {
+	src := `local a = {b: [1, std.extVar("c")]}; a + {d: if true then "e" else "f"}`
+
+	node, err := ParseNode("walk.jsonnet", src)
+	require.NoError(t, err)
+
+	var strings []string
+	Walk(node, func(n ast.Node) {
+		if s, ok := n.(*ast.LiteralString); ok {
+			strings = append(strings, s.Value)
+		}
+	})
+
+	assert.Subset(t, strings, []string{"c", "e", "f"})
+}
@@ -0,0 +1,64 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package prompt
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirm(t *testing.T) {
+	cases := []struct {
+		name     string
+		answer   string
+		expected bool
+	}{
+		{name: "y", answer: "y\n", expected: true},
+		{name: "yes", answer: "YES\n", expected: true},
+		{name: "no", answer: "n\n", expected: false},
+		{name: "empty", answer: "\n", expected: false},
+		{name: "eof", answer: "", expected: false},
+		{name: "yes without newline", answer: "yes", expected: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			got, err := Confirm(strings.NewReader(tc.answer), &out, "Continue?")
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expected, got)
+			require.Equal(t, "Continue? [y/N]: ", out.String())
+		})
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	require.False(t, IsTerminal(strings.NewReader("")))
+
+	f, err := ioutil.TempFile("", "prompt")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	require.False(t, IsTerminal(f))
+}
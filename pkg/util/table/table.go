@@ -16,6 +16,7 @@
 package table
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -37,6 +38,8 @@ const (
 	FormatTable Format = iota
 	// FormatJSON prints JSON.
 	FormatJSON
+	// FormatCSV prints comma separated values.
+	FormatCSV
 )
 
 // DefaultFormat is the default format for output. It is a table.
@@ -47,6 +50,8 @@ func DetectFormat(formatName string) (Format, error) {
 	switch formatName {
 	case "json":
 		return FormatJSON, nil
+	case "csv":
+		return FormatCSV, nil
 	case "", "table":
 		return FormatTable, nil
 	default:
@@ -110,9 +115,34 @@ func (t *Table) Render() error {
 		return t.renderTable()
 	case FormatJSON:
 		return t.renderJSON()
+	case FormatCSV:
+		return t.renderCSV()
 	}
 }
 
+func (t *Table) renderCSV() error {
+	w := csv.NewWriter(t.w)
+
+	if len(t.header) > 0 {
+		if err := w.Write(t.header); err != nil {
+			return errors.Wrap(err, "writing header to csv")
+		}
+	}
+
+	for _, row := range t.rows {
+		if hl := len(t.header); hl > 0 && hl != len(row) {
+			return errors.New("header length doesn't match row length")
+		}
+
+		if err := w.Write(row); err != nil {
+			return errors.Wrap(err, "writing row to csv")
+		}
+	}
+
+	w.Flush()
+	return errors.Wrap(w.Error(), "flushing csv")
+}
+
 // jsonOutput is the structure for printing JSON output.
 type jsonOutput struct {
 	Kind string              `json:"kind"`
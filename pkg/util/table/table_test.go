@@ -39,6 +39,11 @@ func TestDetectFormat(t *testing.T) {
 			formatName: "json",
 			expected:   FormatJSON,
 		},
+		{
+			name:       "csv",
+			formatName: "csv",
+			expected:   FormatCSV,
+		},
 		{
 			name:       "table",
 			formatName: "table",
@@ -90,6 +95,12 @@ func TestTable(t *testing.T) {
 			rw:     &bytes.Buffer{},
 			output: "output.json",
 		},
+		{
+			name:   "CSV format",
+			format: FormatCSV,
+			rw:     &bytes.Buffer{},
+			output: "output.csv",
+		},
 		{
 			name:   "unknown format",
 			format: Format(99),
@@ -151,6 +162,11 @@ func TestTable_no_header(t *testing.T) {
 			format: FormatJSON,
 			isErr:  true,
 		},
+		{
+			name:   "in CSV format",
+			format: FormatCSV,
+			output: "output_no_header.csv",
+		},
 	}
 
 	for _, tc := range cases {
@@ -193,6 +209,10 @@ func TestTable_header_and_row_length_must_match(t *testing.T) {
 			name:   "in JSON format",
 			format: FormatJSON,
 		},
+		{
+			name:   "in CSV format",
+			format: FormatCSV,
+		},
 	}
 
 	for _, tc := range cases {
@@ -16,6 +16,8 @@
 package utils
 
 import (
+	"sort"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -24,12 +26,15 @@ var (
 	gkNamespace    = schema.GroupKind{Group: "", Kind: "Namespace"}
 	gkTpr          = schema.GroupKind{Group: "extensions", Kind: "ThirdPartyResource"}
 	gkStorageClass = schema.GroupKind{Group: "storage.k8s.io", Kind: "StorageClass"}
+	gkCrd          = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
 
-	gkPod         = schema.GroupKind{Group: "", Kind: "Pod"}
-	gkJob         = schema.GroupKind{Group: "batch", Kind: "Job"}
-	gkDeployment  = schema.GroupKind{Group: "extensions", Kind: "Deployment"}
-	gkDaemonSet   = schema.GroupKind{Group: "extensions", Kind: "DaemonSet"}
-	gkStatefulSet = schema.GroupKind{Group: "apps", Kind: "StatefulSet"}
+	gkPod            = schema.GroupKind{Group: "", Kind: "Pod"}
+	gkJob            = schema.GroupKind{Group: "batch", Kind: "Job"}
+	gkDeployment     = schema.GroupKind{Group: "extensions", Kind: "Deployment"}
+	gkDaemonSet      = schema.GroupKind{Group: "extensions", Kind: "DaemonSet"}
+	gkStatefulSet    = schema.GroupKind{Group: "apps", Kind: "StatefulSet"}
+	gkAppsDeployment = schema.GroupKind{Group: "apps", Kind: "Deployment"}
+	gkAppsDaemonSet  = schema.GroupKind{Group: "apps", Kind: "DaemonSet"}
 )
 
 // These kinds all start pods.
@@ -39,14 +44,16 @@ func isPodOrSimilar(gk schema.GroupKind) bool {
 		gk == gkJob ||
 		gk == gkDeployment ||
 		gk == gkDaemonSet ||
-		gk == gkStatefulSet
+		gk == gkStatefulSet ||
+		gk == gkAppsDeployment ||
+		gk == gkAppsDaemonSet
 }
 
 // Arbitrary numbers used to do a simple topological sort of resources.
 // TODO: expand this list.
 func depTier(o schema.ObjectKind) int {
 	gk := o.GroupVersionKind().GroupKind()
-	if gk == gkNamespace || gk == gkTpr || gk == gkStorageClass {
+	if gk == gkNamespace || gk == gkTpr || gk == gkStorageClass || gk == gkCrd {
 		return 10
 	} else if isPodOrSimilar(gk) {
 		return 100
@@ -67,6 +74,27 @@ func (l DependencyOrder) Less(i, j int) bool {
 	return depTier(l[i].GetObjectKind()) < depTier(l[j].GetObjectKind())
 }
 
+// DependencyTiers groups objects into tiers using the same best-effort
+// dependencies as DependencyOrder. Namespaces and resource definitions come
+// first, then configuration, then workloads. Objects within a tier do not
+// depend on each other, so they can be handled concurrently.
+func DependencyTiers(objects []*unstructured.Unstructured) [][]*unstructured.Unstructured {
+	sorted := make([]*unstructured.Unstructured, len(objects))
+	copy(sorted, objects)
+	sort.Stable(DependencyOrder(sorted))
+
+	var tiers [][]*unstructured.Unstructured
+	for i, obj := range sorted {
+		if i == 0 || depTier(obj.GetObjectKind()) != depTier(sorted[i-1].GetObjectKind()) {
+			tiers = append(tiers, nil)
+		}
+
+		tiers[len(tiers)-1] = append(tiers[len(tiers)-1], obj)
+	}
+
+	return tiers
+}
+
 // AlphabeticalOrder is a `sort.Interface` that sorts the
 // objects by namespace/name/kind alphabetical order
 type AlphabeticalOrder []*unstructured.Unstructured
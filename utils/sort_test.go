@@ -52,6 +52,45 @@ func TestDepSort(t *testing.T) {
 	}
 }
 
+func TestDependencyTiers(t *testing.T) {
+	newObj := func(apiVersion, kind string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": apiVersion,
+				"kind":       kind,
+			},
+		}
+	}
+
+	objs := []*unstructured.Unstructured{
+		newObj("apps/v1", "Deployment"),
+		newObj("v1", "ConfigMap"),
+		newObj("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition"),
+		newObj("v1", "Namespace"),
+		newObj("v1", "Service"),
+	}
+
+	tiers := DependencyTiers(objs)
+
+	expected := [][]*unstructured.Unstructured{
+		{objs[2], objs[3]},
+		{objs[1], objs[4]},
+		{objs[0]},
+	}
+
+	if !reflect.DeepEqual(tiers, expected) {
+		t.Errorf("actual != expected: %v != %v", tiers, expected)
+	}
+
+	if objs[0].GetKind() != "Deployment" {
+		t.Error("DependencyTiers should not reorder its input")
+	}
+
+	if tiers := DependencyTiers(nil); len(tiers) != 0 {
+		t.Errorf("expected no tiers for no objects, got %v", tiers)
+	}
+}
+
 func TestAlphaSort(t *testing.T) {
 	newObj := func(ns, name, kind string) *unstructured.Unstructured {
 		o := unstructured.Unstructured{}